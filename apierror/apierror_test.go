@@ -0,0 +1,112 @@
+package apierror_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"okra_board2/apierror"
+	"okra_board2/logging"
+	"okra_board2/models"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestFrom(t *testing.T) {
+    assert.Nil(t, apierror.From(nil))
+
+    e := apierror.From(gorm.ErrRecordNotFound)
+    assert.Equal(t, apierror.CodeNotFound, e.Code)
+    assert.Equal(t, 404, e.Status())
+
+    // 알 수 없는 에러의 내용은 응답에 포함되지 않는다.
+    e = apierror.From(errors.New("dial tcp: connection refused"))
+    assert.Equal(t, apierror.CodeInternal, e.Code)
+    assert.Equal(t, 500, e.Status())
+    body, _ := json.Marshal(e.Response("en", ""))
+    assert.NotContains(t, string(body), "connection refused")
+    assert.ErrorContains(t, e, "connection refused")
+
+    wrapped := apierror.Wrap(apierror.CodeSameTag, errors.New("same tag"))
+    assert.Same(t, wrapped, apierror.From(wrapped))
+    assert.Equal(t, 400, wrapped.Status())
+}
+
+func TestValidation(t *testing.T) {
+    title, content := "제목을 입력해주세요.", "내용을 입력해주세요."
+    e := apierror.Validation(&models.PostValidationResult{ Title: &title, Content: &content })
+    assert.Equal(t, 422, e.Status())
+    assert.Equal(t, []models.FieldError{
+        { Field: "title", Message: title },
+        { Field: "content", Message: content },
+    }, e.Fields)
+
+    group := "invalid group"
+    e = apierror.Validation(&models.FeaturedSlotValidationResult{
+        Group: &group,
+        Slots: map[int]string{ 10: "not found", 2: "duplicated" },
+    })
+    assert.Equal(t, []models.FieldError{
+        { Field: "group", Message: group },
+        { Field: "slots[2]", Message: "duplicated" },
+        { Field: "slots[10]", Message: "not found" },
+    }, e.Fields)
+}
+
+func TestInvalidBody(t *testing.T) {
+    var post models.Post
+    err := json.Unmarshal([]byte(`{"boardId":"one"}`), &post)
+    e := apierror.InvalidBody(err)
+    assert.Equal(t, apierror.CodeInvalidBody, e.Code)
+    assert.Equal(t, []models.FieldError{{ Field: "boardId" }}, e.Fields)
+
+    response := e.Response("en", "")
+    assert.Equal(t, "Invalid value.", response.Error.Fields[0].Message)
+}
+
+func TestAbort(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    route := gin.New()
+    route.Use(logging.Middleware())
+    route.Use(apierror.Recovery())
+    route.NoRoute(apierror.NotFound)
+    route.GET("/panic", func(c *gin.Context) {
+        panic("boom")
+    })
+    route.GET("/param/:id", func(c *gin.Context) {
+        apierror.Abort(c, apierror.InvalidParameter("id", errors.New("not a number")))
+    })
+
+    tests := []struct {
+        path    string
+        lang    string
+        status  int
+        code    string
+        message string
+    }{
+        { "/unknown", "", 404, "not_found", "요청한 리소스를 찾을 수 없습니다." },
+        { "/unknown?lang=en", "", 404, "not_found", "The requested resource was not found." },
+        { "/param/abc", "en-US,en;q=0.9", 400, "invalid_parameter", "A request parameter is invalid." },
+        { "/param/abc", "fr", 400, "invalid_parameter", "요청 파라미터가 올바르지 않습니다." },
+        { "/panic", "", 500, "internal_error", "서버에서 오류가 발생했습니다." },
+    }
+    for _, test := range tests {
+        w := httptest.NewRecorder()
+        req := httptest.NewRequest("GET", test.path, nil)
+        req.Header.Set("Accept-Language", test.lang)
+        route.ServeHTTP(w, req)
+
+        var response models.ErrorResponse
+        assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), test.path)
+        assert.Equal(t, test.status, w.Code, test.path)
+        assert.Equal(t, test.status, response.Error.Status, test.path)
+        assert.Equal(t, test.code, response.Error.Code, test.path)
+        assert.Equal(t, test.message, response.Error.Message, test.path)
+        assert.Equal(t, w.Header().Get(logging.RequestIDHeader), response.Error.RequestID, test.path)
+        assert.NotEmpty(t, response.Error.RequestID, test.path)
+        assert.False(t, strings.Contains(w.Body.String(), "boom"), test.path)
+    }
+}
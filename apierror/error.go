@@ -0,0 +1,213 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"okra_board2/models"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// 클라이언트가 에러의 종류를 구분하는 코드. 한 번 정한 코드는 바꾸지 않는다.
+type Code string
+
+const (
+    CodeBadRequest          Code = "bad_request"
+    CodeInvalidParameter    Code = "invalid_parameter"
+    CodeInvalidBody         Code = "invalid_body"
+    CodeValidationFailed    Code = "validation_failed"
+    CodeUnknownReaction     Code = "unknown_reaction"
+    CodeInvalidRankingWindow Code = "invalid_ranking_window"
+    CodeInvalidRankingSort  Code = "invalid_ranking_sort"
+    CodeSameTag             Code = "same_tag"
+    CodeUploadFailed        Code = "upload_failed"
+    CodeImageDeleteFailed   Code = "image_delete_failed"
+    CodeUnauthorized        Code = "unauthorized"
+    CodeLoginFailed         Code = "login_failed"
+    CodeTokenMissing        Code = "token_missing"
+    CodeTokenExpired        Code = "token_expired"
+    CodeTokenInvalid        Code = "token_invalid"
+    CodeForbidden           Code = "forbidden"
+    CodeCommentsDisabled    Code = "comments_disabled"
+    CodeWrongPassword       Code = "wrong_password"
+    CodeNotFound            Code = "not_found"
+    CodeBodyTooLarge        Code = "body_too_large"
+    CodeRateLimited         Code = "rate_limited"
+    CodeInternal            Code = "internal_error"
+    CodeUnavailable         Code = "unavailable"
+)
+
+var statuses = map[Code]int{
+    CodeBadRequest:             http.StatusBadRequest,
+    CodeInvalidParameter:       http.StatusBadRequest,
+    CodeInvalidBody:            http.StatusBadRequest,
+    CodeValidationFailed:       http.StatusUnprocessableEntity,
+    CodeUnknownReaction:        http.StatusBadRequest,
+    CodeInvalidRankingWindow:   http.StatusBadRequest,
+    CodeInvalidRankingSort:     http.StatusBadRequest,
+    CodeSameTag:                http.StatusBadRequest,
+    CodeUploadFailed:           http.StatusBadRequest,
+    CodeImageDeleteFailed:      http.StatusBadRequest,
+    CodeUnauthorized:           http.StatusUnauthorized,
+    CodeLoginFailed:            http.StatusUnauthorized,
+    CodeTokenMissing:           http.StatusUnauthorized,
+    CodeTokenExpired:           http.StatusUnauthorized,
+    CodeTokenInvalid:           http.StatusUnauthorized,
+    CodeForbidden:              http.StatusForbidden,
+    CodeCommentsDisabled:       http.StatusForbidden,
+    CodeWrongPassword:          http.StatusForbidden,
+    CodeNotFound:               http.StatusNotFound,
+    CodeBodyTooLarge:           http.StatusRequestEntityTooLarge,
+    CodeRateLimited:            http.StatusTooManyRequests,
+    CodeInternal:               http.StatusInternalServerError,
+    CodeUnavailable:            http.StatusServiceUnavailable,
+}
+
+// 코드에 해당하는 HTTP 응답 코드. 등록되지 않은 코드는 500.
+func (c Code) Status() int {
+    if status, ok := statuses[c]; ok {
+        return status
+    }
+    return http.StatusInternalServerError
+}
+
+// API 에러. 원인(Err)은 로그에만 기록되고 응답에는 포함되지 않는다.
+type Error struct {
+    Code        Code
+    Fields      []models.FieldError
+    Details     interface{}
+    Err         error
+}
+
+func New(code Code) *Error {
+    return &Error{ Code: code }
+}
+
+// err를 원인으로 하는 에러를 생성한다.
+func Wrap(code Code, err error) *Error {
+    return &Error{ Code: code, Err: err }
+}
+
+func (e *Error) Error() string {
+    if e.Err != nil {
+        return string(e.Code) + ": " + e.Err.Error()
+    }
+    return string(e.Code)
+}
+
+func (e *Error) Unwrap() error {
+    return e.Err
+}
+
+func (e *Error) Status() int {
+    return e.Code.Status()
+}
+
+func (e *Error) WithFields(fields ...models.FieldError) *Error {
+    e.Fields = append(e.Fields, fields...)
+    return e
+}
+
+func (e *Error) WithDetails(details interface{}) *Error {
+    e.Details = details
+    return e
+}
+
+// lang으로 번역된 응답을 생성한다. 메시지가 비어있는 항목에는 기본 메시지를 사용한다.
+func (e *Error) Response(lang, requestID string) models.ErrorResponse {
+    fields := make([]models.FieldError, len(e.Fields))
+    for i, field := range e.Fields {
+        if field.Message == "" {
+            field.Message = fieldMessage(lang)
+        }
+        fields[i] = field
+    }
+    return models.ErrorResponse{
+        Error: models.ErrorBody{
+            Code: string(e.Code),
+            Status: e.Status(),
+            Message: Message(e.Code, lang),
+            Fields: fields,
+            Details: e.Details,
+            RequestID: requestID,
+        },
+    }
+}
+
+// 경로 또는 쿼리 파라미터 name의 값이 올바르지 않다.
+func InvalidParameter(name string, err error) *Error {
+    return Wrap(CodeInvalidParameter, err).WithFields(models.FieldError{ Field: name })
+}
+
+// 요청 본문을 읽지 못했다. 타입이 맞지 않는 항목은 Fields에 포함된다.
+func InvalidBody(err error) *Error {
+    var maxBytesErr *http.MaxBytesError
+    if errors.As(err, &maxBytesErr) {
+        return Wrap(CodeBodyTooLarge, err)
+    }
+    e := Wrap(CodeInvalidBody, err)
+    var typeErr *json.UnmarshalTypeError
+    if errors.As(err, &typeErr) && typeErr.Field != "" {
+        e.WithFields(models.FieldError{ Field: typeErr.Field })
+    }
+    return e
+}
+
+// 서비스의 유효성 검사 결과(models.*ValidationResult)를 에러로 변환한다.
+// nil이 아닌 *string 항목과 map 항목의 값이 json 필드 이름과 함께 Fields에 포함된다.
+func Validation(result interface{}) *Error {
+    return New(CodeValidationFailed).WithFields(validationFields(reflect.ValueOf(result))...)
+}
+
+func validationFields(value reflect.Value) (fields []models.FieldError) {
+    value = reflect.Indirect(value)
+    if value.Kind() != reflect.Struct { return }
+    for i := 0; i < value.NumField(); i++ {
+        name := strings.Split(value.Type().Field(i).Tag.Get("json"), ",")[0]
+        field := value.Field(i)
+        switch {
+        case field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.String:
+            if !field.IsNil() {
+                fields = append(fields, models.FieldError{ Field: name, Message: field.Elem().String() })
+            }
+        case field.Kind() == reflect.Map && field.Type().Elem().Kind() == reflect.String:
+            keys := field.MapKeys()
+            sort.Slice(keys, func(i, j int) bool {
+                if keys[i].CanInt() {
+                    return keys[i].Int() < keys[j].Int()
+                }
+                return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+            })
+            for _, key := range keys {
+                fields = append(fields, models.FieldError{
+                    Field: fmt.Sprintf("%s[%v]", name, key),
+                    Message: field.MapIndex(key).String(),
+                })
+            }
+        }
+    }
+    return
+}
+
+// err를 API 에러로 변환한다. *Error는 그대로 반환하며, 조회 결과가 없을 경우 not_found,
+// 요청 본문이 너무 클 경우 body_too_large, 그 외의 에러는 원인을 숨기고 internal_error로 변환한다.
+func From(err error) *Error {
+    if err == nil { return nil }
+    var e *Error
+    if errors.As(err, &e) {
+        return e
+    }
+    var maxBytesErr *http.MaxBytesError
+    switch {
+    case errors.Is(err, gorm.ErrRecordNotFound):
+        return Wrap(CodeNotFound, err)
+    case errors.As(err, &maxBytesErr):
+        return Wrap(CodeBodyTooLarge, err)
+    }
+    return Wrap(CodeInternal, err)
+}
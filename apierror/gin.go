@@ -0,0 +1,47 @@
+package apierror
+
+import (
+	"fmt"
+	"log/slog"
+	"okra_board2/logging"
+	"okra_board2/utils/locale"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 에러 메시지의 언어. lang 쿼리가 Accept-Language 헤더보다 우선하며, 제공하지 않는 언어일 경우 기본 언어를 사용한다.
+func Language(c *gin.Context) string {
+    requested := locale.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+    if lang := c.Query("lang"); lang != "" {
+        requested = append([]string{ lang }, requested...)
+    }
+    if matched := locale.Match(requested, Languages); len(matched) > 0 {
+        return matched[0]
+    }
+    return Languages[0]
+}
+
+// err를 API 에러로 변환하여 응답하고 이후의 핸들러를 실행하지 않는다.
+// 원래의 에러는 c.Errors에 추가되어 요청 로그에 기록된다.
+func Abort(c *gin.Context, err error) {
+    e := From(err)
+    c.Error(err)
+    c.AbortWithStatusJSON(e.Status(), e.Response(Language(c), logging.RequestID(c)))
+}
+
+// 일치하는 경로가 없는 요청에 404를 응답한다.
+func NotFound(c *gin.Context) {
+    Abort(c, New(CodeNotFound))
+}
+
+// panic을 복구하고 스택과 함께 기록한 뒤 500을 응답한다.
+func Recovery() gin.HandlerFunc {
+    return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, err any) {
+        logging.FromContext(c.Request.Context()).Error("panic recovered",
+            slog.String("error", fmt.Sprint(err)),
+            slog.String("stack", string(debug.Stack())),
+        )
+        Abort(c, Wrap(CodeInternal, fmt.Errorf("panic: %v", err)))
+    })
+}
@@ -0,0 +1,123 @@
+package apierror
+
+// 에러 메시지를 제공하는 언어. 첫 번째 언어가 기본 언어이다.
+var Languages = []string{ "ko", "en" }
+
+var messages = map[Code]map[string]string{
+    CodeBadRequest: {
+        "ko": "잘못된 요청입니다.",
+        "en": "The request is invalid.",
+    },
+    CodeInvalidParameter: {
+        "ko": "요청 파라미터가 올바르지 않습니다.",
+        "en": "A request parameter is invalid.",
+    },
+    CodeInvalidBody: {
+        "ko": "요청 본문을 읽을 수 없습니다.",
+        "en": "The request body could not be read.",
+    },
+    CodeValidationFailed: {
+        "ko": "입력한 값이 올바르지 않습니다.",
+        "en": "Some fields are invalid.",
+    },
+    CodeUnknownReaction: {
+        "ko": "사용할 수 없는 반응입니다.",
+        "en": "Unknown reaction type.",
+    },
+    CodeInvalidRankingWindow: {
+        "ko": "순위 기간은 day, week, month, all 중 하나여야 합니다.",
+        "en": "The ranking window must be one of day, week, month or all.",
+    },
+    CodeInvalidRankingSort: {
+        "ko": "순위 기준은 views 또는 trending이어야 합니다.",
+        "en": "The ranking sort must be views or trending.",
+    },
+    CodeSameTag: {
+        "ko": "태그를 자기 자신과 합칠 수 없습니다.",
+        "en": "A tag cannot be merged into itself.",
+    },
+    CodeUploadFailed: {
+        "ko": "이미지를 업로드하지 못했습니다.",
+        "en": "The image could not be uploaded.",
+    },
+    CodeImageDeleteFailed: {
+        "ko": "이미지가 삭제되지 않았습니다.",
+        "en": "Some images could not be deleted.",
+    },
+    CodeUnauthorized: {
+        "ko": "인증이 필요합니다.",
+        "en": "Authentication is required.",
+    },
+    CodeLoginFailed: {
+        "ko": "아이디 또는 비밀번호가 올바르지 않습니다.",
+        "en": "The ID or password is incorrect.",
+    },
+    CodeTokenMissing: {
+        "ko": "토큰이 없습니다.",
+        "en": "The token is missing.",
+    },
+    CodeTokenExpired: {
+        "ko": "토큰이 만료되었습니다.",
+        "en": "The token has expired.",
+    },
+    CodeTokenInvalid: {
+        "ko": "올바르지 않은 토큰입니다.",
+        "en": "The token is invalid.",
+    },
+    CodeForbidden: {
+        "ko": "권한이 없습니다.",
+        "en": "Access is denied.",
+    },
+    CodeCommentsDisabled: {
+        "ko": "댓글을 작성할 수 없는 게시판입니다.",
+        "en": "Comments are disabled on this board.",
+    },
+    CodeWrongPassword: {
+        "ko": "비밀번호가 일치하지 않습니다.",
+        "en": "The password does not match.",
+    },
+    CodeNotFound: {
+        "ko": "요청한 리소스를 찾을 수 없습니다.",
+        "en": "The requested resource was not found.",
+    },
+    CodeBodyTooLarge: {
+        "ko": "요청 본문이 너무 큽니다.",
+        "en": "The request body is too large.",
+    },
+    CodeRateLimited: {
+        "ko": "요청이 너무 많습니다. 잠시 후 다시 시도해 주세요.",
+        "en": "Too many requests. Try again later.",
+    },
+    CodeInternal: {
+        "ko": "서버에서 오류가 발생했습니다.",
+        "en": "An internal server error occurred.",
+    },
+    CodeUnavailable: {
+        "ko": "일시적으로 사용할 수 없습니다.",
+        "en": "The service is temporarily unavailable.",
+    },
+}
+
+var fieldMessages = map[string]string{
+    "ko": "올바른 값이 아닙니다.",
+    "en": "Invalid value.",
+}
+
+// 코드의 메시지를 lang으로 반환한다. 번역이 없을 경우 기본 언어의 메시지를 사용한다.
+func Message(code Code, lang string) string {
+    translations, ok := messages[code]
+    if !ok {
+        translations = messages[CodeInternal]
+    }
+    if message, ok := translations[lang]; ok {
+        return message
+    }
+    return translations[Languages[0]]
+}
+
+func fieldMessage(lang string) string {
+    if message, ok := fieldMessages[lang]; ok {
+        return message
+    }
+    return fieldMessages[Languages[0]]
+}
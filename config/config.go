@@ -1,20 +1,12 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
-	"log"
-	"os"
-	"strings"
-	"time"
 
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 var DB *gorm.DB
-var Logger *log.Logger
 
 type Config struct {
     WhiteList       []string    `json:"whitelist"`
@@ -22,20 +14,146 @@ type Config struct {
     RefreshSecret   string      `json:"refresh_secret"`
     Domain          string      `json:"domain"`
     DefaultThumbnail string     `json:"default_thumbnail"`
+    Server          ServerConfig `json:"server"`
+    Metrics         MetricsConfig `json:"metrics"`
+    Tracing         TracingConfig `json:"tracing"`
     DB              DBConfig    `json:"db"`
     Log             LogConfig   `json:"log"`
     AWS             AWSConfig   `json:"aws"`
+    Ranking         RankingConfig `json:"ranking"`
+    Site            SiteConfig  `json:"site"`
+    Feed            FeedConfig  `json:"feed"`
+    Sanitizer       SanitizerConfig `json:"sanitizer"`
+    Post            PostConfig  `json:"post"`
+    Comment         CommentConfig `json:"comment"`
+    Reaction        ReactionConfig `json:"reaction"`
 }
 
+type ServerConfig struct {
+    // 서버 주소. 비어있을 경우 :3000.
+    Addr        string          `json:"addr"`
+    // 요청 헤더와 본문을 읽는 최대 시간(초). 0일 경우 30초.
+    ReadTimeout int             `json:"read_timeout"`
+    // 응답을 쓰는 최대 시간(초). 0일 경우 60초.
+    WriteTimeout int            `json:"write_timeout"`
+    // keep-alive 연결이 다음 요청을 기다리는 최대 시간(초). 0일 경우 120초.
+    IdleTimeout int             `json:"idle_timeout"`
+    // 요청 헤더의 최대 크기(byte). 0일 경우 1MB.
+    MaxHeaderBytes int          `json:"max_header_bytes"`
+    // 요청 본문의 최대 크기(byte). 0일 경우 32MB.
+    MaxBodyBytes int64          `json:"max_body_bytes"`
+    // 종료 시 처리 중인 요청이 끝나기를 기다리는 최대 시간(초). 0일 경우 30초.
+    ShutdownTimeout int         `json:"shutdown_timeout"`
+    // 종료 시 /readyz를 실패로 바꾼 뒤 새로운 연결을 거부하기까지 기다리는 시간(초).
+    ShutdownDelay int           `json:"shutdown_delay"`
+    // /readyz의 확인 결과를 재사용하는 시간(ms). 0일 경우 5000ms.
+    ReadyCacheTTL int           `json:"ready_cache_ttl"`
+    // X-Forwarded-For, X-Real-IP 헤더의 클라이언트 IP를 신뢰할 프록시의 IP 또는 CIDR (ex. 10.0.0.0/8).
+    // 비어있을 경우 헤더를 무시하고 연결된 주소를 클라이언트 IP로 사용한다.
+    TrustedProxies []string     `json:"trusted_proxies"`
+    TLS         TLSConfig       `json:"tls"`
+}
+
+// Prometheus 지표(/metrics). Addr와 Token이 모두 비어있을 경우 지표를 제공하지 않는다.
+type MetricsConfig struct {
+    // 지표만 제공하는 별도의 주소 (ex. 127.0.0.1:9100). 비어있을 경우 서버 주소의 /metrics로 제공한다.
+    Addr        string          `json:"addr"`
+    // 비어있지 않을 경우 /metrics 요청에 Authorization: Bearer <token> 헤더가 필요하다.
+    Token       string          `json:"token"`
+}
+
+func (c *MetricsConfig) Enabled() bool {
+    return c.Addr != "" || c.Token != ""
+}
+
+// span exporter
+const (
+    TracingExporterOTLP     = "otlp"
+    TracingExporterStdout   = "stdout"
+)
+
+// OpenTelemetry trace. Exporter가 비어있을 경우 span을 내보내지 않는다.
+type TracingConfig struct {
+    // otlp 또는 stdout. stdout은 span을 표준 출력에 JSON으로 기록한다.
+    Exporter    string          `json:"exporter"`
+    // OTLP/HTTP 수집기 주소 (ex. localhost:4318). 비어있을 경우 OTEL_EXPORTER_OTLP_ENDPOINT 또는 localhost:4318.
+    Endpoint    string          `json:"endpoint"`
+    // true일 경우 수집기에 HTTPS 대신 HTTP로 연결한다.
+    Insecure    bool            `json:"insecure"`
+    // span에 기록되는 서비스 이름. 비어있을 경우 okra_board.
+    ServiceName string          `json:"service_name"`
+    // 기록할 요청의 비율 (0 ~ 1). 0일 경우 1.
+    SampleRatio float64         `json:"sample_ratio"`
+}
+
+// 인증서 파일과 autocert 중 하나만 사용할 수 있다. 둘 다 비어있을 경우 HTTP로 실행된다.
+type TLSConfig struct {
+    CertFile    string          `json:"cert_file"`
+    KeyFile     string          `json:"key_file"`
+    Autocert    AutocertConfig  `json:"autocert"`
+}
+
+// Let's Encrypt 인증서 자동 발급
+type AutocertConfig struct {
+    // 인증서를 발급받을 도메인. 비어있을 경우 autocert를 사용하지 않는다.
+    Hosts       []string        `json:"hosts"`
+    // 발급받은 인증서를 저장하는 디렉토리. 비어있을 경우 ./certs.
+    CacheDir    string          `json:"cache_dir"`
+    Email       string          `json:"email"`
+    // HTTP-01 인증과 HTTPS 리다이렉트를 처리하는 주소 (ex. :80). 비어있을 경우 TLS-ALPN-01 인증만 사용한다.
+    HTTPAddr    string          `json:"http_addr"`
+}
+
+func (c *AutocertConfig) Enabled() bool {
+    return len(c.Hosts) > 0
+}
+
+// 지원하는 DB 드라이버
+const (
+    DriverMySQL     = "mysql"
+    DriverPostgres  = "postgres"
+    DriverSQLite    = "sqlite"
+)
+
 type DBConfig struct {
+    // mysql, postgres, sqlite 중 하나. 비어있을 경우 mysql.
+    Driver      string          `json:"driver"`
+    // 비어있지 않을 경우 아래 항목 대신 드라이버에 그대로 전달되는 DSN
+    DSN         string          `json:"dsn"`
     User        string          `json:"user"`
     Password    string          `json:"password"`
     Host        string          `json:"host"`
     Port        int             `json:"port"`
+    // DB 이름. sqlite일 경우 DB 파일의 경로 (:memory:일 경우 메모리 DB)
     Database    string          `json:"database"`
+    // postgres의 sslmode. 비어있을 경우 disable.
+    SSLMode     string          `json:"ssl_mode"`
+    // 서버 시작 시 적용되지 않은 마이그레이션을 적용한다.
+    AutoMigrate bool            `json:"auto_migrate"`
 }
 
 func (c *DBConfig) ToString() string {
+    if c.DSN != "" {
+        return c.DSN
+    }
+    switch c.Driver {
+    case DriverPostgres:
+        sslMode := c.SSLMode
+        if sslMode == "" {
+            sslMode = "disable"
+        }
+        return fmt.Sprintf(
+            "host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+            c.Host,
+            c.Port,
+            c.User,
+            c.Password,
+            c.Database,
+            sslMode,
+        )
+    case DriverSQLite:
+        return c.Database + "?_pragma=busy_timeout(5000)"
+    }
     return fmt.Sprintf(
         "%s:%s@tcp(%s:%d)/%s?parseTime=true",
         c.User,
@@ -46,10 +164,51 @@ func (c *DBConfig) ToString() string {
     )
 }
 
+// 로그 레벨. 대소문자를 구분하지 않으며 warning은 warn과 같다.
+const (
+    LogLevelDebug   = "debug"
+    LogLevelInfo    = "info"
+    LogLevelWarn    = "warn"
+    LogLevelWarning = "warning"
+    LogLevelError   = "error"
+    // SQL 로그에만 사용한다.
+    LogLevelSilent  = "silent"
+)
+
+// 로그 출력 형식
+const (
+    LogFormatJSON   = "json"
+    LogFormatText   = "text"
+)
+
 type LogConfig struct {
+    // 로그 파일을 저장하는 디렉토리
     Path        string          `json:"path"`
+    // 교체된 로그 파일 이름에 사용되는 시각 형식
     TimeFormat  string          `json:"time_format"`
+    // 로그 파일 이름. 현재 파일은 <prefix>.log, 교체된 파일은 <prefix>-<시각>.log
     Prefix      string          `json:"prefix"`
+    // debug, info, warn, error 중 하나. 비어있을 경우 info.
+    Level       string          `json:"level"`
+    // json 또는 text. 비어있을 경우 json.
+    Format      string          `json:"format"`
+    // 로그 파일을 교체하는 크기(MB). 0일 경우 100MB.
+    MaxSize     int             `json:"max_size"`
+    // 로그 파일을 교체하는 주기(시간). 0일 경우 24시간.
+    RotateInterval int          `json:"rotate_interval"`
+    // 교체된 로그 파일을 보관하는 기간(일). 0일 경우 30일.
+    MaxAge      int             `json:"max_age"`
+    // 보관할 교체된 로그 파일의 최대 개수. 0일 경우 개수를 제한하지 않는다.
+    MaxBackups  int             `json:"max_backups"`
+    SQL         SQLLogConfig    `json:"sql"`
+}
+
+type SQLLogConfig struct {
+    // silent, error, warn, info 중 하나. 비어있을 경우 warn.
+    // info는 모든 쿼리를, warn은 느린 쿼리와 에러를, error는 에러만 기록한다.
+    Level       string          `json:"level"`
+    // 느린 쿼리로 기록하는 기준 시간(ms). 0일 경우 200ms.
+    SlowThreshold int           `json:"slow_threshold"`
 }
 
 type AWSConfig struct {
@@ -60,44 +219,82 @@ type AWSConfig struct {
     Domain      string          `json:"domain"`
 }
 
-func LoadConfig() (*Config, error){
-    file, err := os.Open("config.json")
-    defer file.Close()
-    config := &Config{}
-    jsonParser := json.NewDecoder(file)
-    jsonParser.Decode(config)
-    return config, err
-}
-
-func LoadConfigTest() (*Config, error){
-    file, err := os.Open("../config.json")
-    defer file.Close()
-    config := &Config{}
-    jsonParser := json.NewDecoder(file)
-    jsonParser.Decode(config)
-    return config, err
-}
-
-
-func InitDBConnection(conf *Config) (*gorm.DB, error){
-    dsn := conf.DB.ToString()
-    db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-        Logger: logger.Default.LogMode(logger.Info),
-        NowFunc: func() time.Time {
-            ti, _ := time.LoadLocation("Asia/Seoul")
-            return time.Now().In(ti)
-        },
-    })
-    return db, err
-}
-
-func InitLogger(conf *Config) (*os.File, error) {
-    startTime := time.Now().Format(conf.Log.TimeFormat)
-    fileName := conf.Log.Path + "/" + conf.Log.Prefix + "-" + startTime
-    if file, err := os.Create(strings.TrimSpace(fileName)); err != nil {
-        return nil, err
-    } else {
-        Logger = log.New(file, "INFO: ", log.LstdFlags)
-        return file, nil
+type RankingConfig struct {
+    // 인기/트렌딩 게시물 순위의 갱신 주기(초). 0일 경우 300초.
+    RefreshInterval int         `json:"refresh_interval"`
+    // 트렌딩 점수의 반감기(시간). 0일 경우 24시간.
+    HalfLife    int             `json:"half_life"`
+}
+
+type SiteConfig struct {
+    Title       string          `json:"title"`
+    Description string          `json:"description"`
+    // 공개 사이트의 주소 (ex. https://okraseoul.com)
+    URL         string          `json:"url"`
+    // 게시물 페이지의 경로. {postId}, {boardId}, {slug}, {locale}은 각각 
+    // 게시물 번호, 게시판 번호, 게시물 slug, 게시물의 언어로 치환된다. (ex. /{locale}/boards/{boardId}/{slug})
+    PostPath    string          `json:"post_path"`
+    // 게시물 원문의 언어. 비어있을 경우 ko.
+    Language    string          `json:"language"`
+    // 번역을 제공하는 언어 목록 (원문의 언어 제외)
+    Locales     []string        `json:"locales"`
+    // 요청한 언어의 번역이 없을 때 차례로 시도할 언어. 모두 없을 경우 원문을 사용한다.
+    FallbackLocales []string    `json:"fallback_locales"`
+    BoardNames  map[int]string  `json:"board_names"`
+    // /sitemaps/ 경로가 제공되는 주소. 비어있을 경우 URL을 사용한다.
+    SitemapBaseURL string       `json:"sitemap_base_url"`
+}
+
+// 게시물 원문의 언어
+func (c *SiteConfig) DefaultLocale() string {
+    if c.Language == "" {
+        return "ko"
     }
+    return c.Language
+}
+
+type FeedConfig struct {
+    // 피드에 포함될 최대 게시물 수. 0일 경우 20개.
+    Size        int             `json:"size"`
+    // 게시물 요약의 최대 글자 수. 0일 경우 200자.
+    SummaryLength int           `json:"summary_length"`
+}
+
+type PostConfig struct {
+    // 게시물 요약의 최대 글자 수. 0일 경우 160자.
+    ExcerptLength int           `json:"excerpt_length"`
+    // 읽기 시간 계산에 사용되는 분당 읽는 단어 수. 0일 경우 200단어.
+    WordsPerMinute int          `json:"words_per_minute"`
+    // 읽기 시간 계산에 사용되는 분당 읽는 한글 등 CJK 글자 수. 0일 경우 500자.
+    CharsPerMinute int          `json:"chars_per_minute"`
+}
+
+type CommentConfig struct {
+    // true일 경우 스팸으로 분류되지 않은 댓글은 검토 없이 바로 공개된다.
+    AutoApprove bool            `json:"auto_approve"`
+    // 같은 IP에서 RateWindow초 동안 작성할 수 있는 최대 댓글 수. 0일 경우 5개.
+    RateLimit   int             `json:"rate_limit"`
+    // 작성 횟수를 제한하는 기간(초). 0일 경우 60초.
+    RateWindow  int             `json:"rate_window"`
+    // 댓글에 포함될 수 있는 최대 링크 수. 초과할 경우 스팸으로 분류된다. 0일 경우 2개.
+    MaxLinks    int             `json:"max_links"`
+    // 포함될 경우 스팸으로 분류되는 단어 (대소문자 구분 없음)
+    BlockedKeywords []string    `json:"blocked_keywords"`
+}
+
+type ReactionConfig struct {
+    // like 외에 사용할 수 있는 이모지 반응 종류. 비어있을 경우 love, haha, wow, sad.
+    Emojis      []string        `json:"emojis"`
+}
+
+// 게시물 HTML 정제 정책. 비어있는 항목은 기본 정책의 값을 사용한다.
+type SanitizerConfig struct {
+    // 허용할 태그와 태그별 허용 속성 (ex. {"p": [], "a": ["href", "title"]})
+    Tags        map[string][]string `json:"tags"`
+    // 모든 태그에 허용할 속성
+    Attrs       []string        `json:"attrs"`
+    // URL 속성에 허용할 scheme
+    Schemes     []string        `json:"schemes"`
+    // iframe으로 삽입할 수 있는 호스트
+    IframeHosts []string        `json:"iframe_hosts"`
 }
@@ -0,0 +1,101 @@
+package config_test
+
+import (
+	"okra_board2/config"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfig(t *testing.T, content string) string {
+    t.Helper()
+    path := filepath.Join(t.TempDir(), "config.json")
+    if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+        t.Fatal(err)
+    }
+    return path
+}
+
+func TestLoadConfig(t *testing.T) {
+    path := writeConfig(t, `{
+        "access_secret": "file-access",
+        "refresh_secret": "file-refresh",
+        "db": { "host": "file-host", "port": 3306, "user": "file-user" },
+        "aws": { "region": "ap-northeast-2" }
+    }`)
+    t.Setenv("OKRA_DB_HOST", "env-host")
+    t.Setenv("OKRA_DB_USER", "env-user")
+    t.Setenv("OKRA_COMMENT_AUTO_APPROVE", "true")
+    t.Setenv("OKRA_WHITELIST", "https://a.com, https://b.com")
+    t.Setenv("OKRA_SERVER_MAX_BODY_BYTES", "1048576")
+    t.Setenv("OKRA_TRACING_SAMPLE_RATIO", "0.25")
+
+    conf, args, err := config.LoadConfig([]string{ "-config", path, "-db.host=flag-host", "-db.port", "5432", "migrate", "up" })
+    assert.NoError(t, err)
+    assert.Equal(t, []string{ "migrate", "up" }, args)
+    // 설정 파일 < 환경 변수 < 플래그
+    assert.Equal(t, "file-access", conf.AccessSecret)
+    assert.Equal(t, "env-user", conf.DB.User)
+    assert.Equal(t, "flag-host", conf.DB.Host)
+    assert.Equal(t, 5432, conf.DB.Port)
+    assert.True(t, conf.Comment.AutoApprove)
+    assert.Equal(t, int64(1048576), conf.Server.MaxBodyBytes)
+    assert.Equal(t, 0.25, conf.Tracing.SampleRatio)
+    assert.Equal(t, []string{ "https://a.com", "https://b.com" }, conf.WhiteList)
+
+    _, _, err = config.LoadConfig([]string{ "-config", path, "-db.port", "abc" })
+    assert.Error(t, err)
+    _, _, err = config.LoadConfig([]string{ "-unknown", "value" })
+    assert.Error(t, err)
+    _, _, err = config.LoadConfig([]string{ "-config", filepath.Join(t.TempDir(), "missing.json") })
+    assert.Error(t, err)
+    _, _, err = config.LoadConfig([]string{ "-config", writeConfig(t, `{ "db": `) })
+    assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+    conf := &config.Config{
+        AccessSecret: "access",
+        RefreshSecret: "refresh",
+        DB: config.DBConfig{ Driver: config.DriverSQLite, Database: ":memory:" },
+        Log: config.LogConfig{ Path: t.TempDir() },
+        AWS: config.AWSConfig{ Region: "ap-northeast-2", Bucket: "bucket" },
+        Site: config.SiteConfig{ URL: "https://example.com" },
+    }
+    assert.NoError(t, conf.Validate())
+
+    conf.Server.TLS = config.TLSConfig{ CertFile: "cert.pem", Autocert: config.AutocertConfig{ Hosts: []string{ "example.com" } } }
+    // key_file 누락, autocert와 함께 사용, 인증서 파일 없음
+    assert.Len(t, conf.Validate().(config.ValidationError), 3)
+    conf.Server.TLS = config.TLSConfig{}
+
+    conf.Server.TrustedProxies = []string{ "10.0.0.1", "10.0.0.0/8", "::1", "proxy.local", "10.0.0.0/33" }
+    assert.Len(t, conf.Validate().(config.ValidationError), 2)
+    conf.Server.TrustedProxies = nil
+
+    conf.Log.Level, conf.Log.Format, conf.Log.SQL.Level = "verbose", "xml", "all"
+    assert.Len(t, conf.Validate().(config.ValidationError), 3)
+    conf.Log.Level, conf.Log.Format, conf.Log.SQL.Level = "WARNING", "text", "silent"
+    assert.NoError(t, conf.Validate())
+    conf.Log.Level, conf.Log.Format, conf.Log.SQL.Level = "", "", ""
+
+    conf.Tracing = config.TracingConfig{ Exporter: "jaeger", SampleRatio: 1.5 }
+    assert.Len(t, conf.Validate().(config.ValidationError), 2)
+    conf.Tracing = config.TracingConfig{}
+
+    conf.AccessSecret = ""
+    conf.DB = config.DBConfig{ Driver: config.DriverMySQL }
+    conf.AWS.Region = "seoul"
+    conf.Log.Path = filepath.Join(t.TempDir(), "missing")
+    conf.Site.URL = "example.com"
+    err := conf.Validate()
+    if assert.IsType(t, config.ValidationError{}, err) {
+        // access_secret, db.database, db.host, db.port, aws.region, log.path, site.url
+        assert.Len(t, err.(config.ValidationError), 7)
+    }
+
+    conf.DB.Driver = "oracle"
+    assert.Contains(t, conf.Validate().Error(), "db.driver")
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// 설정 파일 경로의 기본값
+const DefaultConfigPath = "config.json"
+
+// 설정 값을 덮어쓰는 환경 변수의 접두사. 
+// JSON 경로의 .을 _로 바꾸고 대문자로 변환한 이름을 사용한다. (ex. db.password -> OKRA_DB_PASSWORD)
+const EnvPrefix = "OKRA_"
+
+// 설정을 다음 순서로 불러온다. 뒤의 값이 앞의 값을 덮어쓴다.
+//   1. 설정 파일 (-config 플래그, OKRA_CONFIG 환경 변수, config.json 순)
+//   2. OKRA_* 환경 변수
+//   3. 명령행 플래그 (ex. -db.host=localhost, -aws.region ap-northeast-2)
+// 목록은 쉼표로 구분하며, map 형식의 설정은 설정 파일에서만 지정할 수 있다.
+// 플래그를 제외한 나머지 인자(ex. migrate up)를 함께 반환한다.
+func LoadConfig(args []string) (*Config, []string, error) {
+    config := &Config{}
+    fields := configFields(reflect.ValueOf(config).Elem(), "")
+
+    flags := flag.NewFlagSet("okra_board", flag.ContinueOnError)
+    flags.SetOutput(io.Discard)
+    path := flags.String("config", "", "설정 파일 경로 (기본값 " + DefaultConfigPath + ")")
+    values := make(map[string]*string, len(fields))
+    for _, field := range fields {
+        values[field.path] = flags.String(field.path, "", field.path)
+    }
+    if err := flags.Parse(args); err != nil {
+        return nil, nil, err
+    }
+
+    if err := loadConfigFile(config, *path); err != nil {
+        return nil, nil, err
+    }
+
+    for _, field := range fields {
+        name := EnvPrefix + strings.ToUpper(strings.ReplaceAll(field.path, ".", "_"))
+        if raw, ok := os.LookupEnv(name); ok {
+            if err := field.set(raw); err != nil {
+                return nil, nil, fmt.Errorf("%s: %w", name, err)
+            }
+        }
+    }
+
+    var err error
+    flags.Visit(func(f *flag.Flag) {
+        if err != nil { return }
+        for _, field := range fields {
+            if field.path == f.Name {
+                if setErr := field.set(*values[f.Name]); setErr != nil {
+                    err = fmt.Errorf("-%s: %w", f.Name, setErr)
+                }
+                return
+            }
+        }
+    })
+    if err != nil {
+        return nil, nil, err
+    }
+    return config, flags.Args(), nil
+}
+
+// 경로가 지정되지 않았고 기본 설정 파일이 없을 경우 환경 변수와 플래그만으로 설정한다.
+func loadConfigFile(config *Config, path string) error {
+    if path == "" {
+        path = os.Getenv(EnvPrefix + "CONFIG")
+    }
+    explicit := path != ""
+    if !explicit {
+        path = DefaultConfigPath
+    }
+
+    file, err := os.Open(path)
+    if err != nil {
+        if !explicit && errors.Is(err, os.ErrNotExist) {
+            return nil
+        }
+        return err
+    }
+    defer file.Close()
+
+    if err := json.NewDecoder(file).Decode(config); err != nil {
+        return fmt.Errorf("%s: %w", path, err)
+    }
+    return nil
+}
+
+// 환경 변수와 플래그로 지정할 수 있는 설정 항목
+type configField struct {
+    path    string
+    value   reflect.Value
+}
+
+func (f *configField) set(raw string) error {
+    switch f.value.Kind() {
+    case reflect.String:
+        f.value.SetString(raw)
+    case reflect.Int, reflect.Int64:
+        n, err := strconv.ParseInt(raw, 10, f.value.Type().Bits())
+        if err != nil { return err }
+        f.value.SetInt(n)
+    case reflect.Float64:
+        n, err := strconv.ParseFloat(raw, 64)
+        if err != nil { return err }
+        f.value.SetFloat(n)
+    case reflect.Bool:
+        b, err := strconv.ParseBool(raw)
+        if err != nil { return err }
+        f.value.SetBool(b)
+    case reflect.Slice:
+        list := []string{}
+        for _, item := range strings.Split(raw, ",") {
+            if item = strings.TrimSpace(item); item != "" {
+                list = append(list, item)
+            }
+        }
+        f.value.Set(reflect.ValueOf(list))
+    }
+    return nil
+}
+
+// 구조체의 json 태그를 따라 설정 항목을 모두 찾는다. 
+func configFields(value reflect.Value, prefix string) (fields []configField) {
+    for i := 0; i < value.NumField(); i++ {
+        field := value.Type().Field(i)
+        name := strings.Split(field.Tag.Get("json"), ",")[0]
+        if name == "" || name == "-" { continue }
+        path := prefix + name
+
+        switch field.Type.Kind() {
+        case reflect.Struct:
+            fields = append(fields, configFields(value.Field(i), path + ".")...)
+        case reflect.String, reflect.Int, reflect.Int64, reflect.Float64, reflect.Bool:
+            fields = append(fields, configField{ path: path, value: value.Field(i) })
+        case reflect.Slice:
+            if field.Type.Elem().Kind() == reflect.String {
+                fields = append(fields, configField{ path: path, value: value.Field(i) })
+            }
+        }
+    }
+    return
+}
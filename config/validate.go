@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// 설정 검증에 실패한 항목들의 메시지
+type ValidationError []string
+
+func (e ValidationError) Error() string {
+    return "잘못된 설정입니다.\n  - " + strings.Join(e, "\n  - ")
+}
+
+// ex. ap-northeast-2, us-gov-west-1
+var awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-\d+$`)
+
+// 서버 시작 전에 설정을 검증한다. 잘못된 항목이 있을 경우 모든 항목을 ValidationError로 반환한다.
+func (c *Config) Validate() error {
+    errs := ValidationError{}
+    if c.AccessSecret == "" {
+        errs = append(errs, "access_secret이 설정되지 않았습니다.")
+    }
+    if c.RefreshSecret == "" {
+        errs = append(errs, "refresh_secret이 설정되지 않았습니다.")
+    }
+    errs = append(errs, c.DB.validate()...)
+
+    if !awsRegionPattern.MatchString(c.AWS.Region) {
+        errs = append(errs, fmt.Sprintf("aws.region이 올바르지 않습니다: %q", c.AWS.Region))
+    }
+    if c.AWS.Bucket == "" {
+        errs = append(errs, "aws.bucket이 설정되지 않았습니다.")
+    }
+
+    if err := checkWritableDir(c.Log.Path); err != nil {
+        errs = append(errs, fmt.Sprintf("log.path에 로그를 기록할 수 없습니다: %s", err.Error()))
+    }
+    switch strings.ToLower(c.Log.Level) {
+    case "", LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelWarning, LogLevelError:
+    default:
+        errs = append(errs, fmt.Sprintf("log.level은 debug, info, warn, error 중 하나여야 합니다: %q", c.Log.Level))
+    }
+    if c.Log.Format != "" && c.Log.Format != LogFormatJSON && c.Log.Format != LogFormatText {
+        errs = append(errs, fmt.Sprintf("log.format은 json 또는 text여야 합니다: %q", c.Log.Format))
+    }
+    switch strings.ToLower(c.Log.SQL.Level) {
+    case "", LogLevelSilent, LogLevelError, LogLevelWarn, LogLevelWarning, LogLevelInfo:
+    default:
+        errs = append(errs, fmt.Sprintf("log.sql.level은 silent, error, warn, info 중 하나여야 합니다: %q", c.Log.SQL.Level))
+    }
+
+    errs = append(errs, c.Server.validate()...)
+
+    switch c.Tracing.Exporter {
+    case "", TracingExporterOTLP, TracingExporterStdout:
+    default:
+        errs = append(errs, fmt.Sprintf("tracing.exporter는 otlp 또는 stdout이어야 합니다: %q", c.Tracing.Exporter))
+    }
+    if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+        errs = append(errs, fmt.Sprintf("tracing.sample_ratio는 0 이상 1 이하여야 합니다: %v", c.Tracing.SampleRatio))
+    }
+
+    if c.Site.URL != "" {
+        if u, err := url.Parse(c.Site.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+            errs = append(errs, fmt.Sprintf("site.url은 http 또는 https 주소여야 합니다: %q", c.Site.URL))
+        }
+    }
+
+    if len(errs) > 0 {
+        return errs
+    }
+    return nil
+}
+
+// db 설정만을 검증한다. 마이그레이션처럼 DB만 사용하는 명령에서 Config.Validate 대신 사용한다.
+func (c *DBConfig) Validate() error {
+    if errs := ValidationError(c.validate()); len(errs) > 0 {
+        return errs
+    }
+    return nil
+}
+
+func (c *DBConfig) validate() (errs []string) {
+    switch c.Driver {
+    case "", DriverMySQL, DriverPostgres, DriverSQLite:
+    default:
+        return []string{ fmt.Sprintf("db.driver는 %s, %s, %s 중 하나여야 합니다: %q", DriverMySQL, DriverPostgres, DriverSQLite, c.Driver) }
+    }
+    if c.DSN != "" {
+        return
+    }
+    if c.Database == "" {
+        errs = append(errs, "db.database가 설정되지 않았습니다.")
+    }
+    if c.Driver == DriverSQLite {
+        return
+    }
+    if c.Host == "" {
+        errs = append(errs, "db.host가 설정되지 않았습니다.")
+    }
+    if c.Port <= 0 || c.Port > 65535 {
+        errs = append(errs, fmt.Sprintf("db.port가 올바르지 않습니다: %d", c.Port))
+    }
+    return
+}
+
+func (c *ServerConfig) validate() (errs []string) {
+    if c.ReadTimeout < 0 || c.WriteTimeout < 0 || c.IdleTimeout < 0 || c.ShutdownTimeout < 0 || c.ShutdownDelay < 0 {
+        errs = append(errs, "server의 timeout과 shutdown_delay는 0 이상이어야 합니다.")
+    }
+    if c.ReadyCacheTTL < 0 {
+        errs = append(errs, "server.ready_cache_ttl은 0 이상이어야 합니다.")
+    }
+    if c.MaxHeaderBytes < 0 || c.MaxBodyBytes < 0 {
+        errs = append(errs, "server.max_header_bytes와 server.max_body_bytes는 0 이상이어야 합니다.")
+    }
+    for _, proxy := range c.TrustedProxies {
+        if !validProxy(proxy) {
+            errs = append(errs, fmt.Sprintf("server.trusted_proxies는 IP 또는 CIDR이어야 합니다: %q", proxy))
+        }
+    }
+    tls := c.TLS
+    if (tls.CertFile == "") != (tls.KeyFile == "") {
+        errs = append(errs, "server.tls.cert_file과 server.tls.key_file은 함께 설정해야 합니다.")
+    }
+    if tls.CertFile != "" && tls.Autocert.Enabled() {
+        errs = append(errs, "server.tls의 인증서 파일과 autocert는 함께 사용할 수 없습니다.")
+    }
+    for _, file := range []string{ tls.CertFile, tls.KeyFile } {
+        if file == "" { continue }
+        if _, err := os.Stat(file); err != nil {
+            errs = append(errs, fmt.Sprintf("인증서 파일을 읽을 수 없습니다: %s", err.Error()))
+        }
+    }
+    return
+}
+
+// IP(ex. 10.0.0.1) 또는 CIDR(ex. 10.0.0.0/8) 형식인지 확인한다.
+func validProxy(proxy string) bool {
+    if strings.Contains(proxy, "/") {
+        _, _, err := net.ParseCIDR(proxy)
+        return err == nil
+    }
+    return net.ParseIP(proxy) != nil
+}
+
+// 디렉토리가 존재하며 파일을 생성할 수 있는지 확인한다.
+func checkWritableDir(path string) error {
+    if path == "" {
+        return fmt.Errorf("경로가 설정되지 않았습니다.")
+    }
+    info, err := os.Stat(path)
+    if err != nil {
+        return err
+    }
+    if !info.IsDir() {
+        return fmt.Errorf("%s는 디렉토리가 아닙니다.", path)
+    }
+    file, err := os.CreateTemp(path, ".write-test-*")
+    if err != nil {
+        return err
+    }
+    file.Close()
+    return os.Remove(file.Name())
+}
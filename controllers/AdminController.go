@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"okra_board2/apierror"
 	"okra_board2/models"
 	"okra_board2/services"
 	"github.com/gin-gonic/gin"
@@ -28,16 +29,16 @@ func (a *AdminControllerImpl) Register(c *gin.Context) {
     requestBody := &models.Admin{}
     err := c.ShouldBind(requestBody)
     if err != nil {
-        c.JSON(400, err.Error())
+        abort(c, apierror.InvalidBody(err))
         return
     }
     ok, result := a.adminService.Register(requestBody)
     if ok {
         c.Status(200)
     } else if result == nil {
-        c.Status(400)
+        abort(c, apierror.New(apierror.CodeBadRequest))
     } else {
-        c.IndentedJSON(422, result)
+        abort(c, apierror.Validation(result))
     }
 }
 
@@ -46,16 +47,16 @@ func (a *AdminControllerImpl) Update(c *gin.Context) {
     requestBody := &models.Admin{}
     err := c.ShouldBind(requestBody)
     if err != nil {
-        c.JSON(400, err.Error())
+        abort(c, apierror.InvalidBody(err))
         return
     }
     ok, result := a.adminService.Update(requestBody)
     if ok {
         c.Status(200)
     } else if result == nil {
-        c.Status(400)
+        abort(c, apierror.New(apierror.CodeBadRequest))
     } else {
-        c.IndentedJSON(422, result)
+        abort(c, apierror.Validation(result))
     }
 }
 
@@ -1,6 +1,9 @@
 package controllers
 
 import (
+    "okra_board2/apierror"
+    "okra_board2/logging"
+    "okra_board2/metrics"
     "okra_board2/services"
     "okra_board2/models"
     "github.com/gin-gonic/gin"
@@ -30,30 +33,26 @@ func NewAuthControllerImpl(
     }
 }
 
+// 토큰 검증 에러를 만료 여부에 따라 API 에러로 변환한다.
+func tokenError(err error) *apierror.Error {
+    if v, ok := err.(*jwt.ValidationError); ok && v.Errors == jwt.ValidationErrorExpired {
+        return apierror.Wrap(apierror.CodeTokenExpired, err)
+    }
+    return apierror.Wrap(apierror.CodeTokenInvalid, err)
+}
+
 func (a *AuthControllerImpl) Auth(c *gin.Context) {
     authorization := c.Request.Header.Get("Authorization")
     tokenPair := strings.Split(authorization, " ")
     token := tokenPair[0] // access token
     if token == "" {
-        c.JSON(401, gin.H {
-            "status": 401,
-            "message": "access token is empty.",
-        })
-        c.Abort()
-    } else if _, err := a.authService.VerifyAccessToken(token); err != nil {
-        if v, _ := err.(*jwt.ValidationError); v.Errors == jwt.ValidationErrorExpired {
-            c.JSON(401, gin.H {
-                "status": 401,
-                "message": "access token is expired.",
-            })
-            c.Abort()
-        } else {
-            c.JSON(401, gin.H {
-                "status": 401,
-                "message": "invalid access token.",
-            })
-            c.Abort()
+        abort(c, apierror.New(apierror.CodeTokenMissing))
+    } else if claims, err := a.authService.VerifyAccessToken(token); err == nil {
+        if id, ok := claims["id"].(string); ok {
+            logging.SetAdminID(c, id)
         }
+    } else {
+        abort(c, tokenError(err))
     }
 }
 
@@ -61,21 +60,23 @@ func (a *AuthControllerImpl) Login(c *gin.Context) {
     requestBody := &models.Admin{}
     err := c.ShouldBind(requestBody)
     if err != nil {
-        c.JSON(400, err.Error())
+        abort(c, apierror.InvalidBody(err))
         return
     }
 
-    if a.adminService.Login(requestBody) {
-        adminAuth, err := a.authService.CreateTokenPair(requestBody.ID)
+    success := a.adminService.Login(c.Request.Context(), requestBody)
+    metrics.ObserveLogin(success)
+    if success {
+        adminAuth, err := a.authService.CreateTokenPair(c.Request.Context(), requestBody.ID)
         if err != nil {
-            c.JSON(400, err.Error())
+            abort(c, err)
             return
         }
         tokenPair := adminAuth.AccessToken + " " + adminAuth.RefreshToken
         c.Header("Authorization", tokenPair)
         c.Status(200)
     } else {
-        c.Status(401)
+        abort(c, apierror.New(apierror.CodeLoginFailed))
     }
 }
 
@@ -88,34 +89,32 @@ func (a *AuthControllerImpl) Logout(c *gin.Context) {
     if len(tokenPair) >= 2 {
         refreshToken = tokenPair[1]
     } else {
-        c.JSON(401, gin.H {
-            "status": 401,
-            "message": "refresh token is empty",
-        })
+        abort(c, apierror.New(apierror.CodeTokenMissing))
         return
     }
 
-    uuid, err := a.authService.VerifyTokenPair(accessToken, refreshToken)
+    uuid, err := a.authService.VerifyTokenPair(c.Request.Context(), accessToken, refreshToken)
     if err != nil {
-        c.JSON(401, gin.H {
-            "status": 401,
-            "message": err.Error(),
-        })
+        abort(c, tokenError(err))
         return
     }
 
-    err = a.authService.DeleteTokenPair(uuid)
+    err = a.authService.DeleteTokenPair(c.Request.Context(), uuid)
     if err != nil {
-        c.JSON(401, gin.H {
-            "status": 401,
-            "message": err.Error(),
-        })
+        abort(c, err)
         return
     }
 
     c.Status(200)
 }
 
+// 검증에 실패한 토큰 쌍을 삭제한다. 삭제에 실패해도 토큰 검증 실패를 응답한다.
+func (a *AuthControllerImpl) deleteTokenPair(c *gin.Context, uuid string) {
+    if err := a.authService.DeleteTokenPair(c.Request.Context(), uuid); err != nil {
+        logging.FromContext(c.Request.Context()).Warn("토큰을 삭제하지 못했습니다.", "error", err)
+    }
+}
+
 func (a *AuthControllerImpl) ReissueAccessToken(c *gin.Context) {
     authorization := c.Request.Header.Get("Authorization")
     tokenPair := strings.Split(authorization, " ")
@@ -124,66 +123,31 @@ func (a *AuthControllerImpl) ReissueAccessToken(c *gin.Context) {
     if len(tokenPair) >= 2 {
         refreshToken = tokenPair[1]
     } else {
-        c.JSON(401, gin.H {
-            "status": 401,
-            "message": "refresh token is empty",
-        })
+        abort(c, apierror.New(apierror.CodeTokenMissing))
         return
     }
 
     claims, err := a.authService.VerifyRefreshToken(refreshToken)
     uuid, ok := claims["uuid"].(string)
     if !ok {
-        c.JSON(401, gin.H {
-            "status": 401,
-            "message": "invalid refresh token",
-        })
+        abort(c, apierror.New(apierror.CodeTokenInvalid))
         return
     }
     id, ok := claims["id"].(string)
     if !ok {
-        c.JSON(401, gin.H {
-            "status": 401,
-            "message": "invalid refresh token",
-        })
+        abort(c, apierror.New(apierror.CodeTokenInvalid))
         return
     }
     if err != nil {
-        if err := a.authService.DeleteTokenPair(uuid); err != nil {
-            c.JSON(401, gin.H {
-                "status": 401,
-                "message": err.Error(),
-            })
-        }
-        if v, _ := err.(*jwt.ValidationError); v.Errors == jwt.ValidationErrorExpired {
-            c.JSON(401, gin.H {
-                "status": 401,
-                "message": "refresh token is expired.",
-            })
-        } else {
-            c.JSON(401, gin.H {
-                "status": 401,
-                "message": "invalid refresh token.",
-            })
-        }
-    } else if _, err := a.authService.VerifyTokenPair(accessToken, refreshToken); err != nil {
-        if err := a.authService.DeleteTokenPair(uuid); err != nil {
-            c.JSON(401, gin.H {
-                "status": 401,
-                "message": err.Error(),
-            })
-        }
-        c.JSON(401, gin.H {
-            "status": 401,
-            "message": err.Error(),
-        })
+        a.deleteTokenPair(c, uuid)
+        abort(c, tokenError(err))
+    } else if _, err := a.authService.VerifyTokenPair(c.Request.Context(), accessToken, refreshToken); err != nil {
+        a.deleteTokenPair(c, uuid)
+        abort(c, tokenError(err))
     } else {
-        newAccessToken, err := a.authService.CreateAccessToken(uuid, id)
+        newAccessToken, err := a.authService.CreateAccessToken(c.Request.Context(), uuid, id)
         if err != nil {
-            c.JSON(401, gin.H {
-                "status": 401,
-                "message": err.Error(),
-            })
+            abort(c, err)
             return
         }
         newTokenPair := newAccessToken + " " + refreshToken
@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"math"
+	"okra_board2/apierror"
+	"okra_board2/models"
+	"okra_board2/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommentController interface {
+    GetComments(c *gin.Context)
+    WriteComment(c *gin.Context)
+    UpdateComment(c *gin.Context)
+    DeleteComment(c *gin.Context)
+    GetModerationQueue(c *gin.Context)
+    ModerateComments(c *gin.Context)
+    DeleteComments(c *gin.Context)
+    SetCommentEnabled(c *gin.Context)
+}
+
+type CommentControllerImpl struct {
+    commentService services.CommentService
+}
+
+func NewCommentControllerImpl(commentService services.CommentService) CommentController {
+    return &CommentControllerImpl{ commentService: commentService }
+}
+
+func (cc *CommentControllerImpl) GetComments(c *gin.Context) {
+    postId, err := strconv.Atoi(c.Param("postId"))
+    if err != nil { abort(c, apierror.InvalidParameter("postId", err)); return }
+
+    comments, err := cc.commentService.GetComments(c.Request.Context(), postId)
+    if err != nil { abort(c, err); return }
+    c.IndentedJSON(200, comments)
+}
+
+func (cc *CommentControllerImpl) WriteComment(c *gin.Context) {
+    postId, err := strconv.Atoi(c.Param("postId"))
+    if err != nil { abort(c, apierror.InvalidParameter("postId", err)); return }
+
+    requestBody := &models.Comment{}
+    if err := c.ShouldBind(requestBody); err != nil {
+        abort(c, apierror.InvalidBody(err))
+        return
+    }
+    requestBody.PostID = postId
+    requestBody.IP = c.ClientIP()
+
+    commentId, result, err := cc.commentService.WriteComment(c.Request.Context(), requestBody)
+    if result != nil {
+        abort(c, apierror.Validation(result))
+        return
+    }
+    if err != nil { abort(c, err); return }
+    c.JSON(200, gin.H {
+        "commentId": commentId,
+        "status": requestBody.Status,
+    })
+}
+
+func (cc *CommentControllerImpl) UpdateComment(c *gin.Context) {
+    postId, err := strconv.Atoi(c.Param("postId"))
+    if err != nil { abort(c, apierror.InvalidParameter("postId", err)); return }
+    commentId, err := strconv.Atoi(c.Param("commentId"))
+    if err != nil { abort(c, apierror.InvalidParameter("commentId", err)); return }
+
+    requestBody := &models.Comment{}
+    if err := c.ShouldBind(requestBody); err != nil {
+        abort(c, apierror.InvalidBody(err))
+        return
+    }
+    requestBody.PostID = postId
+    requestBody.CommentID = commentId
+
+    result, err := cc.commentService.UpdateComment(c.Request.Context(), requestBody)
+    if result != nil {
+        abort(c, apierror.Validation(result))
+        return
+    }
+    if err != nil { abort(c, err); return }
+    c.JSON(200, gin.H {
+        "status": requestBody.Status,
+    })
+}
+
+func (cc *CommentControllerImpl) DeleteComment(c *gin.Context) {
+    postId, err := strconv.Atoi(c.Param("postId"))
+    if err != nil { abort(c, apierror.InvalidParameter("postId", err)); return }
+    commentId, err := strconv.Atoi(c.Param("commentId"))
+    if err != nil { abort(c, apierror.InvalidParameter("commentId", err)); return }
+
+    requestBody := &struct {
+        Password    string  `json:"password"`
+    }{}
+    if err := c.ShouldBind(requestBody); err != nil {
+        abort(c, apierror.InvalidBody(err))
+        return
+    }
+
+    if err := cc.commentService.DeleteComment(c.Request.Context(), postId, commentId, requestBody.Password); err != nil {
+        abort(c, err)
+        return
+    }
+    c.Status(200)
+}
+
+func (cc *CommentControllerImpl) GetModerationQueue(c *gin.Context) {
+    size, err := strconv.Atoi(c.DefaultQuery("size", "30"))
+    if err != nil { abort(c, apierror.InvalidParameter("size", err)); return }
+
+    page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+    if err != nil { abort(c, apierror.InvalidParameter("page", err)); return }
+
+    var status *string
+    if statusStr, statusExists := c.GetQuery("status"); statusExists {
+        status = &statusStr
+    }
+
+    comments, count := cc.commentService.GetModerationQueue(c.Request.Context(), status, page, size)
+    c.IndentedJSON(200, gin.H {
+        "nowPage": page,
+        "pageCount": math.Ceil(float64(count) / float64(size)),
+        "pageSize": size,
+        "comments": comments,
+    })
+}
+
+func (cc *CommentControllerImpl) ModerateComments(c *gin.Context) {
+    requestBody := &struct {
+        CommentIDs  []int   `json:"commentIds"`
+        Status      string  `json:"status"`
+    }{}
+    if err := c.ShouldBind(requestBody); err != nil {
+        abort(c, apierror.InvalidBody(err))
+        return
+    }
+
+    updated, result, err := cc.commentService.ModerateComments(c.Request.Context(), requestBody.CommentIDs, requestBody.Status)
+    if result != nil {
+        abort(c, apierror.Validation(result))
+        return
+    }
+    if err != nil { abort(c, err); return }
+    c.JSON(200, gin.H {
+        "updated": updated,
+    })
+}
+
+func (cc *CommentControllerImpl) DeleteComments(c *gin.Context) {
+    requestBody := []int{}
+    if err := c.ShouldBind(&requestBody); err != nil {
+        abort(c, apierror.InvalidBody(err))
+        return
+    }
+
+    deleted, err := cc.commentService.DeleteComments(c.Request.Context(), requestBody)
+    if err != nil { abort(c, err); return }
+    c.JSON(200, gin.H {
+        "deleted": deleted,
+    })
+}
+
+func (cc *CommentControllerImpl) SetCommentEnabled(c *gin.Context) {
+    boardId, err := strconv.Atoi(c.Param("boardId"))
+    if err != nil { abort(c, apierror.InvalidParameter("boardId", err)); return }
+
+    requestBody := &models.CommentSetting{}
+    if err := c.ShouldBind(requestBody); err != nil {
+        abort(c, apierror.InvalidBody(err))
+        return
+    }
+
+    if err := cc.commentService.SetCommentEnabled(c.Request.Context(), boardId, requestBody.Enabled); err != nil {
+        abort(c, err)
+        return
+    }
+    c.Status(200)
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"okra_board2/apierror"
+	"okra_board2/models"
+	"okra_board2/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeaturedSlotController interface {
+    GetFeaturedPosts(c *gin.Context)
+    GetSlots(c *gin.Context)
+    ReplaceSlots(c *gin.Context)
+    ReorderSlots(c *gin.Context)
+}
+
+type FeaturedSlotControllerImpl struct {
+    slotService services.FeaturedSlotService
+}
+
+func NewFeaturedSlotControllerImpl(slotService services.FeaturedSlotService) FeaturedSlotController {
+    return &FeaturedSlotControllerImpl{ slotService: slotService }
+}
+
+func (f *FeaturedSlotControllerImpl) GetFeaturedPosts(c *gin.Context) {
+    posts := f.slotService.GetFeaturedPosts(c.Request.Context(), c.Param("group"))
+    c.IndentedJSON(200, posts)
+}
+
+func (f *FeaturedSlotControllerImpl) GetSlots(c *gin.Context) {
+    slots := f.slotService.GetSlots(c.Request.Context(), c.Param("group"))
+    c.IndentedJSON(200, slots)
+}
+
+func (f *FeaturedSlotControllerImpl) ReplaceSlots(c *gin.Context) {
+    requestBody := []models.FeaturedSlot{}
+    if err := c.ShouldBind(&requestBody); err != nil {
+        abort(c, apierror.InvalidBody(err))
+        return
+    }
+    result, err := f.slotService.ReplaceSlots(c.Request.Context(), c.Param("group"), requestBody)
+    if result != nil {
+        abort(c, apierror.Validation(result))
+        return
+    }
+    if err != nil {
+        abort(c, err)
+        return
+    }
+    c.Status(200)
+}
+
+func (f *FeaturedSlotControllerImpl) ReorderSlots(c *gin.Context) {
+    requestBody := []int{}
+    if err := c.ShouldBind(&requestBody); err != nil {
+        abort(c, apierror.InvalidBody(err))
+        return
+    }
+    result, err := f.slotService.ReorderSlots(c.Request.Context(), c.Param("group"), requestBody)
+    if result != nil {
+        abort(c, apierror.Validation(result))
+        return
+    }
+    if err != nil {
+        abort(c, err)
+        return
+    }
+    c.Status(200)
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"okra_board2/apierror"
+	"okra_board2/config"
+	"okra_board2/services"
+	"okra_board2/utils/feed"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+    FeedFormatRSS   = "rss"
+    FeedFormatAtom  = "atom"
+    FeedFormatJSON  = "json"
+)
+
+type FeedController interface {
+    GetFeed(format string) gin.HandlerFunc
+}
+
+type FeedControllerImpl struct {
+    feedService services.FeedService
+    conf        *config.Config
+}
+
+func NewFeedControllerImpl(
+    feedService services.FeedService,
+    conf *config.Config,
+) FeedController {
+    return &FeedControllerImpl{
+        feedService: feedService,
+        conf: conf,
+    }
+}
+
+// 요청된 피드 자신의 주소.
+// 응답이 캐시되므로 요청의 Host, X-Forwarded-Proto 헤더가 아닌 사이트 주소와 요청 경로로 만든다.
+func (f *FeedControllerImpl) feedURL(c *gin.Context) string {
+    return strings.TrimRight(f.conf.Site.URL, "/") + c.Request.URL.EscapedPath()
+}
+
+// If-None-Match 헤더의 ETag 중 etag와 같은 것이 있는지 확인한다.
+// 헤더는 쉼표로 구분된 목록일 수 있으며, W/로 시작하는 약한 ETag도 같은 값으로 비교한다.
+func etagMatches(header, etag string) bool {
+    for _, candidate := range strings.Split(header, ",") {
+        candidate = strings.TrimSpace(candidate)
+        if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+            return true
+        }
+    }
+    return false
+}
+
+func (f *FeedControllerImpl) render(format string, fd *feed.Feed) (body []byte, contentType string, err error) {
+    switch format {
+    case FeedFormatAtom:
+        body, err = fd.Atom()
+        contentType = "application/atom+xml; charset=utf-8"
+    case FeedFormatJSON:
+        body, err = fd.JSON()
+        contentType = "application/feed+json; charset=utf-8"
+    default:
+        body, err = fd.RSS()
+        contentType = "application/rss+xml; charset=utf-8"
+    }
+    return
+}
+
+// 게시판 번호는 boardId, 태그는 tag 경로 변수로 전달된다.
+// full 쿼리가 true일 경우 게시물 본문을 포함한다.
+func (f *FeedControllerImpl) GetFeed(format string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        var (
+            boardId *int
+            tag *string
+        )
+        if boardIdStr := c.Param("boardId"); boardIdStr != "" {
+            temp, err := strconv.Atoi(boardIdStr)
+            if err != nil { abort(c, apierror.InvalidParameter("boardId", err)); return }
+            boardId = &temp
+        }
+        if tagStr := c.Param("tag"); tagStr != "" {
+            tag = &tagStr
+        }
+        full, err := strconv.ParseBool(c.DefaultQuery("full", "false"))
+        if err != nil { abort(c, apierror.InvalidParameter("full", err)); return }
+
+        fd, err := f.feedService.GetFeed(c.Request.Context(), boardId, tag, full, f.feedURL(c))
+        if err != nil { abort(c, err); return }
+
+        body, contentType, err := f.render(format, fd)
+        if err != nil { abort(c, err); return }
+
+        hash := sha256.Sum256(body)
+        etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+        lastModified := fd.Updated.UTC().Truncate(time.Second)
+
+        c.Header("ETag", etag)
+        c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+        c.Header("Cache-Control", "public, max-age=300")
+
+        if match := c.GetHeader("If-None-Match"); match != "" {
+            if etagMatches(match, etag) {
+                c.Status(304)
+                return
+            }
+        } else if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil {
+            if !lastModified.After(since) {
+                c.Status(304)
+                return
+            }
+        }
+
+        c.Data(200, contentType, body)
+    }
+}
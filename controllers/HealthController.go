@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"okra_board2/models"
+	"okra_board2/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController interface {
+    // 프로세스가 요청을 처리할 수 있으면 항상 200을 응답한다.
+    Live(c *gin.Context)
+    // 의존성 확인 결과를 응답한다. 실패한 항목이 있거나 종료 중일 경우 503을 응답한다.
+    Ready(c *gin.Context)
+}
+
+type HealthControllerImpl struct {
+    healthService services.HealthService
+}
+
+func NewHealthControllerImpl(healthService services.HealthService) HealthController {
+    return &HealthControllerImpl{ healthService: healthService }
+}
+
+func (h *HealthControllerImpl) Live(c *gin.Context) {
+    c.JSON(200, gin.H{ "status": models.HealthStatusOK })
+}
+
+func (h *HealthControllerImpl) Ready(c *gin.Context) {
+    report := h.healthService.Ready(c.Request.Context())
+    if !report.OK() {
+        c.JSON(503, report)
+        return
+    }
+    c.JSON(200, report)
+}
@@ -1,7 +1,9 @@
 package controllers
 
 import (
-	"log"
+	"okra_board2/apierror"
+	"okra_board2/logging"
+	"okra_board2/config"
 	"os"
 
 	"github.com/google/uuid"
@@ -13,28 +15,28 @@ type ImageController interface {
     DeleteImage(c *gin.Context)
 }
 
-type ImageControllerImpl struct {}
+type ImageControllerImpl struct {
+    conf *config.Config
+}
 
-func NewImageControllerImpl() ImageController {
-    return &ImageControllerImpl{}
+func NewImageControllerImpl(conf *config.Config) ImageController {
+    return &ImageControllerImpl{ conf: conf }
 }
 
 func (i *ImageControllerImpl) UploadImage(c *gin.Context) {
     file, err := c.FormFile("file")
     if err != nil {
-        log.Println(err)
-        c.Status(400)
+        abort(c, apierror.InvalidBody(err))
         return
     }
 
     filename := uuid.NewString()
     if err := c.SaveUploadedFile(file, "./public/images/"+filename+".png"); err != nil {
-        log.Println(err)
-        c.Status(400)
+        abort(c, apierror.Wrap(apierror.CodeUploadFailed, err))
         return
     }
 
-    domain := os.Getenv("DOMAIN")
+    domain := i.conf.Domain
     url := "https://"+domain+"/images/"+filename+".png"
 
     c.JSON(200, gin.H { 
@@ -46,24 +48,23 @@ func (i *ImageControllerImpl) UploadImage(c *gin.Context) {
 func (i *ImageControllerImpl) DeleteImage(c *gin.Context) {
     requestBody := []string{}
     if err := c.ShouldBind(&requestBody); err != nil {
-        c.JSON(400, err.Error())
+        abort(c, apierror.InvalidBody(err))
         return
     }
     errs := []string{}
     for _, filename := range requestBody {
-        if filename == os.Getenv("DEFAULT_THUMBNAIL") {
+        if filename == i.conf.DefaultThumbnail {
             continue
         }
         if err := os.Remove("./public/images/"+filename); err!= nil {
-            log.Println(err)
+            logging.FromContext(c.Request.Context()).Error("이미지를 삭제하지 못했습니다.", "file", filename, "error", err)
             errs = append(errs, filename)
         }
     }
     if len(errs) > 0 {
-        c.JSON(400, gin.H {
-            "message": "이미지가 삭제되지 않았습니다.",
+        abort(c, apierror.New(apierror.CodeImageDeleteFailed).WithDetails(gin.H {
             "images": errs,
-        })
+        }))
         return
     }
 
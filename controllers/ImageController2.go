@@ -1,10 +1,9 @@
 package controllers
 
 import (
-	"log"
+	"okra_board2/apierror"
+	"okra_board2/logging"
 	"okra_board2/config"
-    "context"
-    "os"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
@@ -31,28 +30,25 @@ func NewImageControllerImpl2(
 func (i *ImageControllerImpl2) UploadImage(c *gin.Context) {
     fileHeader, err := c.FormFile("file")
     if err != nil {
-        log.Println(err)
-        c.Status(400)
+        abort(c, apierror.InvalidBody(err))
         return
     }
 
     file, err := fileHeader.Open()
     if err != nil {
-        log.Println(err)
-        c.Status(400)
+        abort(c, apierror.Wrap(apierror.CodeUploadFailed, err))
         return
     }
 
     filename := uuid.NewString() + ".png"
     uploader := manager.NewUploader(i.client)
-    _, err = uploader.Upload(context.TODO(), &s3.PutObjectInput {
+    _, err = uploader.Upload(c.Request.Context(), &s3.PutObjectInput {
         Bucket: aws.String(i.conf.AWS.Bucket),
         Key:    aws.String("images/"+filename),
         Body:   file,
     })
     if err != nil {
-        log.Println(err)
-        c.Status(400)
+        abort(c, apierror.Wrap(apierror.CodeUploadFailed, err))
         return
     }
 
@@ -67,28 +63,27 @@ func (i *ImageControllerImpl2) UploadImage(c *gin.Context) {
 func (i *ImageControllerImpl2) DeleteImage(c *gin.Context) {
     requestBody := []string{}
     if err := c.ShouldBind(&requestBody); err != nil {
-        c.JSON(400, err.Error())
+        abort(c, apierror.InvalidBody(err))
         return
     }
     errs := []string{}
     for _, filename := range requestBody {
-        if filename == os.Getenv("DEFAULT_THUMBNAIL") {
+        if filename == i.conf.DefaultThumbnail {
             continue
         }
-        _, err := i.client.DeleteObject(context.TODO(), &s3.DeleteObjectInput {
+        _, err := i.client.DeleteObject(c.Request.Context(), &s3.DeleteObjectInput {
             Bucket: aws.String(i.conf.AWS.Bucket),
             Key:    aws.String("images/"+filename),
         })
         if err != nil { 
-            log.Println(err)
+            logging.FromContext(c.Request.Context()).Error("이미지를 삭제하지 못했습니다.", "file", filename, "error", err)
             errs = append(errs, filename)
         }
     }
     if len(errs) > 0 {
-        c.JSON(400, gin.H {
-            "message": "이미지가 삭제되지 않았습니다.",
+        abort(c, apierror.New(apierror.CodeImageDeleteFailed).WithDetails(gin.H {
             "images": errs,
-        })
+        }))
         return
     }
 
@@ -1,10 +1,16 @@
 package controllers
 
 import (
+	"errors"
 	"math"
+	"net/url"
+	"okra_board2/apierror"
+	"okra_board2/logging"
 	"okra_board2/models"
 	"okra_board2/services"
+	"okra_board2/utils/locale"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -15,17 +21,43 @@ type PostController interface {
     UpdatePost(c *gin.Context)
     DeletePost(c *gin.Context)
     GetPost(enabled bool) gin.HandlerFunc
+    GetPostBySlug(enabled bool) gin.HandlerFunc
     GetPosts(enabled bool) gin.HandlerFunc
     ResetSelectedPosts(c *gin.Context)
     GetSelectedThumbnails(c *gin.Context)
+    GetTranslations(c *gin.Context)
+    SaveTranslation(c *gin.Context)
+    DeleteTranslation(c *gin.Context)
+    GetPostsMissingTranslation(c *gin.Context)
 }
 
 type PostControllerImpl struct {
     postService services.PostService
+    rankingService services.RankingService
+    relatedService services.RelatedPostService
+    reactionService services.ReactionService
 }
 
-func NewPostControllerImpl(postService services.PostService) PostController {
-    return &PostControllerImpl { postService: postService }
+func NewPostControllerImpl(
+    postService services.PostService,
+    rankingService services.RankingService,
+    relatedService services.RelatedPostService,
+    reactionService services.ReactionService,
+) PostController {
+    return &PostControllerImpl { 
+        postService: postService,
+        rankingService: rankingService,
+        relatedService: relatedService,
+        reactionService: reactionService,
+    }
+}
+
+// 요청한 언어 목록. lang 쿼리가 Accept-Language 헤더보다 우선한다.
+func requestLocales(c *gin.Context) (locales []string) {
+    if lang := c.Query("lang"); lang != "" {
+        locales = append(locales, lang)
+    }
+    return append(locales, locale.ParseAcceptLanguage(c.GetHeader("Accept-Language"))...)
 }
 
 func (p *PostControllerImpl) GetPosts(enabled bool) gin.HandlerFunc {
@@ -39,19 +71,19 @@ func (p *PostControllerImpl) GetPosts(enabled bool) gin.HandlerFunc {
             tag *string
         )
         size, err = strconv.Atoi(c.DefaultQuery("size", "15"))
-        if err != nil { c.JSON(400, err.Error()); return }
+        if err != nil { abort(c, apierror.InvalidParameter("size", err)); return }
 
         page, err = strconv.Atoi(c.DefaultQuery("page", "1"))
-        if err != nil { c.JSON(400, err.Error()); return }
+        if err != nil { abort(c, apierror.InvalidParameter("page", err)); return }
 
         if selectedStr, selectedExists := c.GetQuery("selected"); selectedExists {
             temp, err := strconv.ParseBool(selectedStr)
-            if err != nil { c.JSON(400, err.Error()); return }
+            if err != nil { abort(c, apierror.InvalidParameter("selected", err)); return }
             selected = &temp
         }
         if boardIdStr, boardIdExists := c.GetQuery("boarId"); boardIdExists {
             temp, err := strconv.Atoi(boardIdStr)
-            if err != nil { c.JSON(400, err.Error()); return }
+            if err != nil { abort(c, apierror.InvalidParameter("boardId", err)); return }
             boardId = &temp
         } else {
             boardId = nil
@@ -69,7 +101,11 @@ func (p *PostControllerImpl) GetPosts(enabled bool) gin.HandlerFunc {
             tag = nil
         }
 
-        posts, count := p.postService.GetPosts(enabled, selected, page, size, boardId, keyword, tag)
+        posts, count := p.postService.GetPosts(c.Request.Context(), enabled, selected, page, size, boardId, keyword, tag)
+        p.reactionService.SetReactionCounts(c.Request.Context(), posts)
+        if enabled {
+            p.postService.LocalizePosts(c.Request.Context(), posts, requestLocales(c))
+        }
         c.IndentedJSON(200, gin.H {
             "nowPage": page,
             "pageCount": math.Ceil(float64(count) / float64(size)),
@@ -86,7 +122,19 @@ func (p *PostControllerImpl) GetPost(enabled bool) gin.HandlerFunc {
         var postId int
 
         postId, err = strconv.Atoi(c.Param("postId"))
-        if err != nil { c.JSON(400, err.Error()); return }
+        if err != nil { abort(c, apierror.InvalidParameter("postId", err)); return }
+
+        related, err := strconv.Atoi(c.DefaultQuery("related", "0"))
+        if err != nil { abort(c, apierror.InvalidParameter("related", err)); return }
+
+        // 이전, 다음 게시글을 검색할 조건
+        var keyword, tag *string
+        if keywordStr, keywordExists := c.GetQuery("keyword"); keywordExists {
+            keyword = &keywordStr
+        }
+        if tagStr, tagExists := c.GetQuery("tag"); tagExists {
+            tag = &tagStr
+        }
 
         var status *bool
         if enabled { 
@@ -95,8 +143,85 @@ func (p *PostControllerImpl) GetPost(enabled bool) gin.HandlerFunc {
             status = nil 
         }
         
-        post, err := p.postService.GetPost(status, postId)
-        if err == gorm.ErrRecordNotFound { c.Status(404); return }
+        post, err := p.postService.GetPost(c.Request.Context(), status, postId, keyword, tag)
+        if err != nil { abort(c, err); return }
+        if enabled {
+            p.postService.Localize(c.Request.Context(), post, requestLocales(c))
+        }
+
+        if related > 0 {
+            post.Related = p.relatedService.GetRelatedPosts(postId, related)
+        }
+        post.Reactions = p.reactionService.GetReactionCounts(c.Request.Context(), postId)
+
+        // 공개된 게시물을 조회할 경우에만 조회수를 기록한다.
+        if enabled {
+            if err := p.rankingService.RecordView(c.Request.Context(), postId); err != nil {
+                logging.FromContext(c.Request.Context()).Warn("조회수를 기록하지 못했습니다.", "post_id", postId, "error", err)
+            }
+        }
+
+        c.IndentedJSON(200, post)
+
+    }
+}
+
+func (p *PostControllerImpl) GetPostBySlug(enabled bool) gin.HandlerFunc {
+    return func(c *gin.Context) {
+
+        boardId, err := strconv.Atoi(c.Param("boardId"))
+        if err != nil { abort(c, apierror.InvalidParameter("boardId", err)); return }
+
+        related, err := strconv.Atoi(c.DefaultQuery("related", "0"))
+        if err != nil { abort(c, apierror.InvalidParameter("related", err)); return }
+
+        // 이전, 다음 게시글을 검색할 조건
+        var keyword, tag *string
+        if keywordStr, keywordExists := c.GetQuery("keyword"); keywordExists {
+            keyword = &keywordStr
+        }
+        if tagStr, tagExists := c.GetQuery("tag"); tagExists {
+            tag = &tagStr
+        }
+
+        var status *bool
+        if enabled {
+            status = &enabled
+        }
+
+        post, moved, err := p.postService.GetPostBySlug(c.Request.Context(), status, boardId, c.Param("slug"), keyword, tag)
+        if err != nil { abort(c, err); return }
+
+        // 이전 slug로 요청한 경우 현재 slug로 영구 이동시킨다.
+        if moved {
+            location := strings.Replace(c.FullPath(), ":boardId", strconv.Itoa(post.BoardID), 1)
+            location = strings.Replace(location, ":slug", url.PathEscape(post.Slug), 1)
+            if c.Request.URL.RawQuery != "" {
+                location += "?" + c.Request.URL.RawQuery
+            }
+            c.Header("Location", location)
+            c.JSON(301, gin.H {
+                "postId": post.PostID,
+                "boardId": post.BoardID,
+                "slug": post.Slug,
+            })
+            return
+        }
+
+        if enabled {
+            p.postService.Localize(c.Request.Context(), post, requestLocales(c))
+        }
+
+        if related > 0 {
+            post.Related = p.relatedService.GetRelatedPosts(post.PostID, related)
+        }
+        post.Reactions = p.reactionService.GetReactionCounts(c.Request.Context(), post.PostID)
+
+        if enabled {
+            if err := p.rankingService.RecordView(c.Request.Context(), post.PostID); err != nil {
+                logging.FromContext(c.Request.Context()).Warn("조회수를 기록하지 못했습니다.", "post_id", post.PostID, "error", err)
+            }
+        }
 
         c.IndentedJSON(200, post)
 
@@ -108,21 +233,26 @@ func (p *PostControllerImpl) WritePost(c *gin.Context) {
     requestBody := &models.Post{}
 
     if err := c.ShouldBind(requestBody); err != nil {
-        c.JSON(400, err.Error())
+        abort(c, apierror.InvalidBody(err))
         return
     } 
-    postId, result, err := p.postService.WritePost(requestBody)
-    if result != nil {
-        c.JSON(422, result)
+    postId, result, err := p.postService.WritePost(c.Request.Context(), requestBody)
+    if result != nil && !result.Valid() {
+        abort(c, apierror.Validation(result))
         return
     }
     if err != nil {
-        c.JSON(400, err.Error())
+        abort(c, err)
         return
     }
-    c.JSON(200, gin.H {
+    response := gin.H {
         "postId": postId,
-    })
+    }
+    // 정제되어 제거된 태그와 속성을 함께 알린다.
+    if result != nil {
+        response["sanitized"] = result.Sanitized
+    }
+    c.JSON(200, response)
     
     
 }
@@ -130,21 +260,28 @@ func (p *PostControllerImpl) WritePost(c *gin.Context) {
 func (p *PostControllerImpl) UpdatePost(c *gin.Context) {
 
     postId, err := strconv.Atoi(c.Param("postId"))
-    if err != nil { c.JSON(400, err.Error()); return }
+    if err != nil { abort(c, apierror.InvalidParameter("postId", err)); return }
     requestBody := &models.Post{}
 
     if err := c.ShouldBind(requestBody); err != nil {
-        c.JSON(400, err.Error())
+        abort(c, apierror.InvalidBody(err))
         return
     } 
     requestBody.PostID = postId
-    result, err := p.postService.UpdatePost(requestBody)
-    if result != nil {
-        c.JSON(422, result)
+    result, err := p.postService.UpdatePost(c.Request.Context(), requestBody)
+    if result != nil && !result.Valid() {
+        abort(c, apierror.Validation(result))
         return
     }
     if err != nil {
-        c.JSON(400, err.Error())
+        abort(c, err)
+        return
+    }
+    // 정제되어 제거된 태그와 속성을 함께 알린다.
+    if result != nil {
+        c.JSON(200, gin.H {
+            "sanitized": result.Sanitized,
+        })
         return
     }
     c.Status(200)
@@ -156,15 +293,11 @@ func (p *PostControllerImpl) DeletePost(c *gin.Context) {
     
     postId, err = strconv.Atoi(c.Param("postId"))
 
-    if err != nil { c.JSON(400, err.Error()); return }
+    if err != nil { abort(c, apierror.InvalidParameter("postId", err)); return }
 
-    err = p.postService.DeletePost(postId)
+    err = p.postService.DeletePost(c.Request.Context(), postId)
     if err != nil {
-        if err == gorm.ErrRecordNotFound {
-            c.Status(404)
-        } else {
-            c.JSON(400, err.Error())
-        }
+        abort(c, err)
     } else {
         c.Status(200)
     }
@@ -173,15 +306,16 @@ func (p *PostControllerImpl) DeletePost(c *gin.Context) {
 func (p *PostControllerImpl) ResetSelectedPosts(c *gin.Context) {
     requestBody := &[]int{}
     if err := c.ShouldBind(requestBody); err != nil {
-        c.JSON(400, err.Error())
+        abort(c, apierror.InvalidBody(err))
         return
     }
-    ids, err := p.postService.ResetSelectedPosts(requestBody)
+    ids, err := p.postService.ResetSelectedPosts(c.Request.Context(), requestBody)
     if err != nil {
-        if err == gorm.ErrRecordNotFound {
-            c.JSON(404, ids)
+        // 존재하지 않는 게시물 번호를 함께 알린다.
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            abort(c, apierror.Wrap(apierror.CodeNotFound, err).WithDetails(gin.H { "postIds": ids }))
         } else {
-            c.JSON(400, err.Error())
+            abort(c, err)
         }
     } else {
         c.Status(200)
@@ -189,7 +323,72 @@ func (p *PostControllerImpl) ResetSelectedPosts(c *gin.Context) {
 }
 
 func (p *PostControllerImpl) GetSelectedThumbnails(c *gin.Context) {
-    thumbnails := p.postService.GetSelectedThumbnails()
+    thumbnails := p.postService.GetSelectedThumbnails(c.Request.Context())
     c.IndentedJSON(200, thumbnails)
 }
 
+
+func (p *PostControllerImpl) GetTranslations(c *gin.Context) {
+    postId, err := strconv.Atoi(c.Param("postId"))
+    if err != nil { abort(c, apierror.InvalidParameter("postId", err)); return }
+
+    translations, err := p.postService.GetTranslations(c.Request.Context(), postId)
+    if err != nil { abort(c, err); return }
+    c.IndentedJSON(200, translations)
+}
+
+func (p *PostControllerImpl) SaveTranslation(c *gin.Context) {
+    postId, err := strconv.Atoi(c.Param("postId"))
+    if err != nil { abort(c, apierror.InvalidParameter("postId", err)); return }
+
+    requestBody := &models.PostTranslation{}
+    if err := c.ShouldBind(requestBody); err != nil {
+        abort(c, apierror.InvalidBody(err))
+        return
+    }
+    requestBody.PostID = postId
+    requestBody.Locale = c.Param("locale")
+
+    result, err := p.postService.SaveTranslation(c.Request.Context(), requestBody)
+    if result != nil && !result.Valid() {
+        abort(c, apierror.Validation(result))
+        return
+    }
+    if err != nil { abort(c, err); return }
+    response := gin.H {
+        "slug": requestBody.Slug,
+    }
+    if result != nil {
+        response["sanitized"] = result.Sanitized
+    }
+    c.JSON(200, response)
+}
+
+func (p *PostControllerImpl) DeleteTranslation(c *gin.Context) {
+    postId, err := strconv.Atoi(c.Param("postId"))
+    if err != nil { abort(c, apierror.InvalidParameter("postId", err)); return }
+
+    err = p.postService.DeleteTranslation(c.Request.Context(), postId, c.Param("locale"))
+    if err != nil { abort(c, err); return }
+    c.Status(200)
+}
+
+func (p *PostControllerImpl) GetPostsMissingTranslation(c *gin.Context) {
+    size, err := strconv.Atoi(c.DefaultQuery("size", "15"))
+    if err != nil { abort(c, apierror.InvalidParameter("size", err)); return }
+
+    page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+    if err != nil { abort(c, apierror.InvalidParameter("page", err)); return }
+
+    target := c.Query("locale")
+    if target == "" { abort(c, apierror.New(apierror.CodeInvalidParameter).WithFields(models.FieldError{ Field: "locale" })); return }
+
+    posts, count := p.postService.GetPostsMissingTranslation(c.Request.Context(), target, page, size)
+    c.IndentedJSON(200, gin.H {
+        "locale": target,
+        "nowPage": page,
+        "pageCount": math.Ceil(float64(count) / float64(size)),
+        "pageSize": size,
+        "posts": posts,
+    })
+}
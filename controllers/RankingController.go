@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"okra_board2/apierror"
+	"okra_board2/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RankingController interface {
+    GetRanking(c *gin.Context)
+}
+
+type RankingControllerImpl struct {
+    rankingService services.RankingService
+}
+
+func NewRankingControllerImpl(rankingService services.RankingService) RankingController {
+    return &RankingControllerImpl{ rankingService: rankingService }
+}
+
+func (r *RankingControllerImpl) GetRanking(c *gin.Context) {
+    var err error
+    var (
+        size int
+        boardId *int
+    )
+    size, err = listSize(c, 10)
+    if err != nil { abort(c, err); return }
+
+    if boardIdStr, boardIdExists := c.GetQuery("boardId"); boardIdExists {
+        temp, err := strconv.Atoi(boardIdStr)
+        if err != nil { abort(c, apierror.InvalidParameter("boardId", err)); return }
+        boardId = &temp
+    }
+
+    window := c.DefaultQuery("window", services.RankingWindowWeek)
+    sortBy := c.DefaultQuery("sort", services.RankingSortViews)
+
+    posts, err := r.rankingService.GetRanking(window, sortBy, boardId, size)
+    if err != nil { abort(c, err); return }
+
+    c.IndentedJSON(200, gin.H {
+        "window": window,
+        "sort": sortBy,
+        "posts": posts,
+    })
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"context"
+	"okra_board2/apierror"
+	"okra_board2/services"
+	"okra_board2/utils/encryption"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReactionController interface {
+    GetReactionTypes(c *gin.Context)
+    React(c *gin.Context)
+    Unreact(c *gin.Context)
+    GetReactionRanking(c *gin.Context)
+}
+
+type ReactionControllerImpl struct {
+    reactionService services.ReactionService
+}
+
+func NewReactionControllerImpl(reactionService services.ReactionService) ReactionController {
+    return &ReactionControllerImpl{ reactionService: reactionService }
+}
+
+// 방문자를 구분하기 위해 IP와 User-Agent의 해시값을 사용한다.
+// IP는 server.trusted_proxies로 설정된 프록시가 전달한 경우에만 X-Forwarded-For 헤더에서 가져오므로
+// 헤더를 바꾸어 같은 반응을 여러 번 남길 수 없다.
+func visitorID(c *gin.Context) string {
+    return encryption.EncryptSHA256(c.ClientIP() + "|" + c.GetHeader("User-Agent"))
+}
+
+func (r *ReactionControllerImpl) GetReactionTypes(c *gin.Context) {
+    c.IndentedJSON(200, r.reactionService.GetReactionTypes())
+}
+
+func (r *ReactionControllerImpl) handle(
+    c *gin.Context,
+    react func(ctx context.Context, postId int, reactionType, visitorId string) (map[string]int, error),
+) {
+    postId, err := strconv.Atoi(c.Param("postId"))
+    if err != nil { abort(c, apierror.InvalidParameter("postId", err)); return }
+
+    requestBody := &struct {
+        Type    string  `json:"type"`
+    }{}
+    if err := c.ShouldBind(requestBody); err != nil {
+        abort(c, apierror.InvalidBody(err))
+        return
+    }
+
+    counts, err := react(c.Request.Context(), postId, requestBody.Type, visitorID(c))
+    if err != nil { abort(c, err); return }
+    c.JSON(200, counts)
+}
+
+func (r *ReactionControllerImpl) React(c *gin.Context) {
+    r.handle(c, r.reactionService.React)
+}
+
+func (r *ReactionControllerImpl) Unreact(c *gin.Context) {
+    r.handle(c, r.reactionService.Unreact)
+}
+
+func (r *ReactionControllerImpl) GetReactionRanking(c *gin.Context) {
+    size, err := listSize(c, 10)
+    if err != nil { abort(c, err); return }
+
+    days, err := strconv.Atoi(c.DefaultQuery("days", "0"))
+    if err != nil { abort(c, apierror.InvalidParameter("days", err)); return }
+
+    var reactionType *string
+    if typeStr, typeExists := c.GetQuery("type"); typeExists {
+        reactionType = &typeStr
+    }
+
+    posts, err := r.reactionService.GetReactionRanking(c.Request.Context(), reactionType, days, size)
+    if err != nil { abort(c, err); return }
+    c.IndentedJSON(200, posts)
+}
@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"okra_board2/apierror"
+	"okra_board2/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeoController interface {
+    GetPostMeta(c *gin.Context)
+}
+
+type SeoControllerImpl struct {
+    seoService services.SeoService
+}
+
+func NewSeoControllerImpl(seoService services.SeoService) SeoController {
+    return &SeoControllerImpl{ seoService: seoService }
+}
+
+func (s *SeoControllerImpl) GetPostMeta(c *gin.Context) {
+    postId, err := strconv.Atoi(c.Param("postId"))
+    if err != nil { abort(c, apierror.InvalidParameter("postId", err)); return }
+
+    meta, err := s.seoService.GetPostMeta(c.Request.Context(), postId, requestLocales(c))
+    if err != nil { abort(c, err); return }
+
+    c.IndentedJSON(200, meta)
+}
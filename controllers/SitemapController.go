@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"okra_board2/apierror"
+	"okra_board2/services"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SitemapController interface {
+    GetSitemap(c *gin.Context)
+    GetSitemapPage(c *gin.Context)
+}
+
+type SitemapControllerImpl struct {
+    sitemapService services.SitemapService
+}
+
+func NewSitemapControllerImpl(sitemapService services.SitemapService) SitemapController {
+    return &SitemapControllerImpl{ sitemapService: sitemapService }
+}
+
+func (s *SitemapControllerImpl) GetSitemap(c *gin.Context) {
+    body := s.sitemapService.GetSitemap()
+    if body == nil { abort(c, apierror.New(apierror.CodeUnavailable)); return }
+    c.Data(200, "application/xml; charset=utf-8", body)
+}
+
+// /sitemaps/sitemap-{page}.xml
+func (s *SitemapControllerImpl) GetSitemapPage(c *gin.Context) {
+    file := c.Param("file")
+    if !strings.HasPrefix(file, "sitemap-") || !strings.HasSuffix(file, ".xml") {
+        abort(c, apierror.New(apierror.CodeNotFound))
+        return
+    }
+    page, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(file, "sitemap-"), ".xml"))
+    if err != nil { abort(c, apierror.Wrap(apierror.CodeNotFound, err)); return }
+
+    body := s.sitemapService.GetSitemapPage(page)
+    if body == nil { abort(c, apierror.New(apierror.CodeNotFound)); return }
+    c.Data(200, "application/xml; charset=utf-8", body)
+}
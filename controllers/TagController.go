@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"okra_board2/apierror"
+	"okra_board2/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagController interface {
+    GetTags(enabled bool) gin.HandlerFunc
+    SearchTags(c *gin.Context)
+    RenameTag(c *gin.Context)
+    MergeTags(c *gin.Context)
+    DeleteUnusedTags(c *gin.Context)
+}
+
+type TagControllerImpl struct {
+    tagService services.TagService
+}
+
+func NewTagControllerImpl(tagService services.TagService) TagController {
+    return &TagControllerImpl{ tagService: tagService }
+}
+
+func (t *TagControllerImpl) GetTags(enabled bool) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        c.IndentedJSON(200, t.tagService.GetTags(c.Request.Context(), enabled))
+    }
+}
+
+func (t *TagControllerImpl) SearchTags(c *gin.Context) {
+    size, err := listSize(c, 10)
+    if err != nil { abort(c, err); return }
+
+    keyword := c.Query("keyword")
+    c.IndentedJSON(200, t.tagService.SearchTags(c.Request.Context(), keyword, size))
+}
+
+func (t *TagControllerImpl) RenameTag(c *gin.Context) {
+    tagId, err := strconv.Atoi(c.Param("tagId"))
+    if err != nil { abort(c, apierror.InvalidParameter("tagId", err)); return }
+
+    requestBody := &struct {
+        Name string `json:"name"`
+    }{}
+    if err := c.ShouldBind(requestBody); err != nil {
+        abort(c, apierror.InvalidBody(err))
+        return
+    }
+
+    result, err := t.tagService.RenameTag(c.Request.Context(), tagId, requestBody.Name)
+    if err != nil { abort(c, err); return }
+    if result != nil { abort(c, apierror.Validation(result)); return }
+    c.Status(200)
+}
+
+func (t *TagControllerImpl) MergeTags(c *gin.Context) {
+    tagId, err := strconv.Atoi(c.Param("tagId"))
+    if err != nil { abort(c, apierror.InvalidParameter("tagId", err)); return }
+
+    requestBody := &struct {
+        TargetID int `json:"targetId"`
+    }{}
+    if err := c.ShouldBind(requestBody); err != nil {
+        abort(c, apierror.InvalidBody(err))
+        return
+    }
+
+    err = t.tagService.MergeTags(c.Request.Context(), tagId, requestBody.TargetID)
+    if err != nil { abort(c, err); return }
+    c.Status(200)
+}
+
+func (t *TagControllerImpl) DeleteUnusedTags(c *gin.Context) {
+    count, err := t.tagService.DeleteUnusedTags(c.Request.Context())
+    if err != nil { abort(c, err); return }
+    c.JSON(200, gin.H {
+        "deleted": count,
+    })
+}
@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"errors"
+	"okra_board2/apierror"
+	"okra_board2/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 서비스의 에러와 응답할 에러 코드
+var serviceErrors = map[error]apierror.Code{
+    services.ErrCommentsDisabled:       apierror.CodeCommentsDisabled,
+    services.ErrWrongPassword:          apierror.CodeWrongPassword,
+    services.ErrCommentRateLimited:     apierror.CodeRateLimited,
+    services.ErrUnknownReaction:        apierror.CodeUnknownReaction,
+    services.ErrInvalidRankingWindow:   apierror.CodeInvalidRankingWindow,
+    services.ErrInvalidRankingSort:     apierror.CodeInvalidRankingSort,
+    services.ErrSameTag:                apierror.CodeSameTag,
+}
+
+// 서비스와 저장소의 에러를 API 에러로 변환하여 응답한다.
+// 등록되지 않은 에러는 apierror.From에 따라 변환되며, 알 수 없는 에러의 내용은 응답에 포함되지 않는다.
+func abort(c *gin.Context, err error) {
+    for target, code := range serviceErrors {
+        if errors.Is(err, target) {
+            err = apierror.Wrap(code, err)
+            break
+        }
+    }
+    apierror.Abort(c, err)
+}
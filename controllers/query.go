@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"fmt"
+	"okra_board2/apierror"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 순위, 검색 결과 등 목록 하나에 포함할 수 있는 최대 항목 수
+const maxListSize = 100
+
+// 쿼리 파라미터 size를 읽는다. 값이 없을 경우 defaultSize를 사용하며,
+// 1 이상 maxListSize 이하가 아닐 경우 invalid_parameter 에러를 반환한다.
+func listSize(c *gin.Context, defaultSize int) (int, error) {
+    size, err := strconv.Atoi(c.DefaultQuery("size", strconv.Itoa(defaultSize)))
+    if err != nil {
+        return 0, apierror.InvalidParameter("size", err)
+    }
+    if size < 1 || size > maxListSize {
+        return 0, apierror.InvalidParameter("size", fmt.Errorf("size must be between 1 and %d", maxListSize))
+    }
+    return size, nil
+}
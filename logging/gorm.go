@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"okra_board2/config"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// 문자열을 gorm의 로그 레벨로 변환한다. 비어있을 경우 warn.
+func ParseSQLLevel(level string) (gormlogger.LogLevel, error) {
+    switch strings.ToLower(level) {
+    case config.LogLevelSilent:
+        return gormlogger.Silent, nil
+    case config.LogLevelError:
+        return gormlogger.Error, nil
+    case "", config.LogLevelWarn, config.LogLevelWarning:
+        return gormlogger.Warn, nil
+    case config.LogLevelInfo:
+        return gormlogger.Info, nil
+    }
+    return gormlogger.Warn, fmt.Errorf("Unknown SQL log level: %s", level)
+}
+
+// gorm의 로그를 slog로 기록한다. 
+// Info 레벨에서는 모든 쿼리를, Warn 레벨에서는 SlowThreshold보다 오래 걸린 쿼리와 에러를, Error 레벨에서는 에러만 기록한다.
+// 쿼리 로그에는 ctx에 저장된 Logger의 속성(요청 ID 등)이 포함된다.
+type GormLogger struct {
+    Level           gormlogger.LogLevel
+    SlowThreshold   time.Duration
+}
+
+func NewGormLogger(level gormlogger.LogLevel, slowThreshold time.Duration) gormlogger.Interface {
+    return &GormLogger{ Level: level, SlowThreshold: slowThreshold }
+}
+
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+    copied := *l
+    copied.Level = level
+    return &copied
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+    if l.Level >= gormlogger.Info {
+        FromContext(ctx).InfoContext(ctx, fmt.Sprintf(msg, data...))
+    }
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+    if l.Level >= gormlogger.Warn {
+        FromContext(ctx).WarnContext(ctx, fmt.Sprintf(msg, data...))
+    }
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+    if l.Level >= gormlogger.Error {
+        FromContext(ctx).ErrorContext(ctx, fmt.Sprintf(msg, data...))
+    }
+}
+
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+    if l.Level <= gormlogger.Silent {
+        return
+    }
+    elapsed := time.Since(begin)
+    slow := l.SlowThreshold > 0 && elapsed > l.SlowThreshold
+    failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+
+    var level slog.Level
+    var msg string
+    switch {
+    case failed && l.Level >= gormlogger.Error:
+        level, msg = slog.LevelError, "sql error"
+    case slow && l.Level >= gormlogger.Warn:
+        level, msg = slog.LevelWarn, "slow sql"
+    case l.Level >= gormlogger.Info:
+        level, msg = slog.LevelInfo, "sql"
+    default:
+        return
+    }
+
+    sql, rows := fc()
+    attrs := []any{
+        slog.String("sql", sql),
+        slog.Int64("rows", rows),
+        slog.Duration("elapsed", elapsed),
+    }
+    if failed {
+        attrs = append(attrs, slog.String("error", err.Error()))
+    }
+    FromContext(ctx).Log(ctx, level, msg, attrs...)
+}
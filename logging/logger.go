@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"okra_board2/config"
+	"strings"
+)
+
+// 로그 출력 형식
+const (
+    FormatJSON  = config.LogFormatJSON
+    FormatText  = config.LogFormatText
+)
+
+type contextKey struct{}
+
+// 문자열을 로그 레벨로 변환한다. 비어있을 경우 info.
+func ParseLevel(level string) (slog.Level, error) {
+    switch strings.ToLower(level) {
+    case config.LogLevelDebug:
+        return slog.LevelDebug, nil
+    case "", config.LogLevelInfo:
+        return slog.LevelInfo, nil
+    case config.LogLevelWarn, config.LogLevelWarning:
+        return slog.LevelWarn, nil
+    case config.LogLevelError:
+        return slog.LevelError, nil
+    }
+    return slog.LevelInfo, fmt.Errorf("Unknown log level: %s", level)
+}
+
+// w에 level 이상의 로그를 format 형식으로 기록하는 Logger를 생성한다. format이 비어있을 경우 JSON.
+func New(w io.Writer, level slog.Level, format string) *slog.Logger {
+    options := &slog.HandlerOptions{ Level: level }
+    if format == FormatText {
+        return slog.New(slog.NewTextHandler(w, options))
+    }
+    return slog.New(slog.NewJSONHandler(w, options))
+}
+
+// 요청 ID 등 요청 단위의 속성이 포함된 Logger를 ctx에 저장한다.
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+    return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// ctx에 저장된 Logger를 반환한다. 없을 경우 기본 Logger를 반환한다.
+func FromContext(ctx context.Context) *slog.Logger {
+    if ctx != nil {
+        if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+            return logger
+        }
+    }
+    return slog.Default()
+}
+
+// ctx의 Logger에 속성을 추가한다.
+func With(ctx context.Context, args ...any) context.Context {
+    return WithContext(ctx, FromContext(ctx).With(args...))
+}
@@ -0,0 +1,110 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"okra_board2/logging"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestRotatingFile(t *testing.T) {
+    dir := t.TempDir()
+
+    // 보관 기간이 지난 파일은 열 때 삭제된다.
+    old := filepath.Join(dir, "app-old.log")
+    os.WriteFile(old, []byte("old"), 0644)
+    os.Chtimes(old, time.Now().Add(-48 * time.Hour), time.Now().Add(-48 * time.Hour))
+
+    file := &logging.RotatingFile{ Dir: dir, Prefix: "app", MaxSize: 10, MaxAge: 24 * time.Hour, MaxBackups: 2 }
+    assert.NoError(t, file.Open())
+    _, err := os.Stat(old)
+    assert.True(t, os.IsNotExist(err))
+
+    // 크기를 넘으면 교체되며, 최대 개수를 넘은 파일은 삭제된다.
+    for i := 0; i < 5; i++ {
+        _, err := file.Write([]byte("12345678\n"))
+        assert.NoError(t, err)
+    }
+    assert.NoError(t, file.Close())
+    backups, _ := filepath.Glob(filepath.Join(dir, "app-*.log"))
+    assert.Len(t, backups, 2)
+    current, _ := os.ReadFile(filepath.Join(dir, "app.log"))
+    assert.Equal(t, "12345678\n", string(current))
+
+    // 주기가 지나면 교체된다.
+    file = &logging.RotatingFile{ Dir: dir, Prefix: "interval", Interval: time.Nanosecond }
+    assert.NoError(t, file.Open())
+    file.Write([]byte("first\n"))
+    time.Sleep(time.Millisecond)
+    file.Write([]byte("second\n"))
+    assert.NoError(t, file.Close())
+    backups, _ = filepath.Glob(filepath.Join(dir, "interval-*.log"))
+    assert.NotEmpty(t, backups)
+}
+
+func TestMiddleware(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    var buf bytes.Buffer
+    defaultLogger := slog.Default()
+    slog.SetDefault(logging.New(&buf, slog.LevelInfo, logging.FormatJSON))
+    t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+    route := gin.New()
+    route.Use(logging.Middleware("/"))
+    route.GET("/posts/:postId", func(c *gin.Context) {
+        logging.SetAdminID(c, "administrator11")
+        logging.FromContext(c.Request.Context()).Info("handler")
+        c.Status(200)
+    })
+
+    req := httptest.NewRequest("GET", "/posts/1", nil)
+    req.Header.Set(logging.RequestIDHeader, "client-request-1")
+    w := httptest.NewRecorder()
+    route.ServeHTTP(w, req)
+    assert.Equal(t, "client-request-1", w.Header().Get(logging.RequestIDHeader))
+
+    lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+    if assert.Len(t, lines, 2) {
+        entry := map[string]interface{}{}
+        assert.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+        assert.Equal(t, "request", entry["msg"])
+        assert.Equal(t, "client-request-1", entry["request_id"])
+        assert.Equal(t, "administrator11", entry["admin_id"])
+        assert.Equal(t, "/posts/:postId", entry["route"])
+        assert.Equal(t, float64(200), entry["status"])
+    }
+
+    // 올바르지 않은 요청 ID는 새로 발급한다.
+    req = httptest.NewRequest("GET", "/posts/1", nil)
+    req.Header.Set(logging.RequestIDHeader, "bad id\n")
+    w = httptest.NewRecorder()
+    route.ServeHTTP(w, req)
+    assert.Len(t, w.Header().Get(logging.RequestIDHeader), 36)
+}
+
+func TestGormLogger(t *testing.T) {
+    var buf bytes.Buffer
+    ctx := logging.WithContext(context.Background(), logging.New(&buf, slog.LevelInfo, logging.FormatJSON))
+    sql := func() (string, int64) { return "SELECT 1", 1 }
+
+    logger := logging.NewGormLogger(gormlogger.Warn, 100 * time.Millisecond)
+    logger.Trace(ctx, time.Now(), sql, nil)
+    assert.Empty(t, buf.String())
+    logger.Trace(ctx, time.Now().Add(-time.Second), sql, nil)
+    assert.Contains(t, buf.String(), "slow sql")
+
+    buf.Reset()
+    logger.LogMode(gormlogger.Silent).Trace(ctx, time.Now().Add(-time.Second), sql, nil)
+    assert.Empty(t, buf.String())
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// 요청 ID를 주고받는 헤더. 클라이언트가 보낸 값이 올바를 경우 그대로 사용한다.
+const RequestIDHeader = "X-Request-ID"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// 요청마다 요청 ID를 발급하여 응답 헤더로 돌려주고, 요청 ID가 포함된 Logger를 요청의 context에 저장한다.
+// 요청이 끝나면 경로, 상태 코드, 처리 시간 등을 기록한다. skipPaths의 요청은 기록하지 않는다.
+func Middleware(skipPaths ...string) gin.HandlerFunc {
+    skip := make(map[string]bool, len(skipPaths))
+    for _, path := range skipPaths {
+        skip[path] = true
+    }
+    return func(c *gin.Context) {
+        start := time.Now()
+        requestID := c.GetHeader(RequestIDHeader)
+        if !requestIDPattern.MatchString(requestID) {
+            requestID = uuid.NewString()
+        }
+        c.Header(RequestIDHeader, requestID)
+        c.Set(RequestIDHeader, requestID)
+
+        ctx := With(c.Request.Context(), slog.String("request_id", requestID))
+        c.Request = c.Request.WithContext(ctx)
+
+        c.Next()
+
+        if skip[c.Request.URL.Path] {
+            return
+        }
+        status := c.Writer.Status()
+        level := slog.LevelInfo
+        if status >= 500 {
+            level = slog.LevelError
+        } else if status >= 400 {
+            level = slog.LevelWarn
+        }
+        attrs := []any{
+            slog.String("method", c.Request.Method),
+            slog.String("route", c.FullPath()),
+            slog.String("path", c.Request.URL.Path),
+            slog.Int("status", status),
+            slog.Duration("latency", time.Since(start)),
+            slog.String("client_ip", c.ClientIP()),
+            slog.Int("size", c.Writer.Size()),
+        }
+        if len(c.Errors) > 0 {
+            attrs = append(attrs, slog.String("errors", c.Errors.String()))
+        }
+        // 인증 과정에서 추가된 관리자 ID 등을 포함하기 위해 처리가 끝난 뒤의 context를 사용한다.
+        FromContext(c.Request.Context()).Log(c.Request.Context(), level, "request", attrs...)
+    }
+}
+
+// 요청의 ID를 반환한다. Middleware를 거치지 않은 요청일 경우 빈 문자열을 반환한다.
+func RequestID(c *gin.Context) string {
+    return c.GetString(RequestIDHeader)
+}
+
+// 인증된 관리자의 ID를 이후 요청의 로그에 포함한다.
+func SetAdminID(c *gin.Context, adminID string) {
+    c.Request = c.Request.WithContext(With(c.Request.Context(), slog.String("admin_id", adminID)))
+}
@@ -0,0 +1,154 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 크기나 시간이 기준을 넘으면 새로운 파일에 기록하는 로그 파일.
+// 현재 파일은 <Dir>/<Prefix>.log이며, 교체된 파일은 <Dir>/<Prefix>-<교체 시각>.log로 이름이 바뀐다.
+type RotatingFile struct {
+    Dir         string
+    Prefix      string
+    // 교체된 파일 이름에 사용되는 시각 형식
+    TimeFormat  string
+    // 파일의 최대 크기(byte). 0일 경우 크기로 교체하지 않는다.
+    MaxSize     int64
+    // 파일을 교체하는 주기. 0일 경우 시간으로 교체하지 않는다.
+    Interval    time.Duration
+    // 교체된 파일을 보관하는 기간. 0일 경우 기간으로 삭제하지 않는다.
+    MaxAge      time.Duration
+    // 보관할 교체된 파일의 최대 개수. 0일 경우 개수로 삭제하지 않는다.
+    MaxBackups  int
+
+    mutex       sync.Mutex
+    file        *os.File
+    size        int64
+    openedAt    time.Time
+}
+
+const defaultRotateTimeFormat = "2006-01-02T15-04-05"
+
+func (f *RotatingFile) current() string {
+    return filepath.Join(f.Dir, f.Prefix + ".log")
+}
+
+// 현재 파일을 열고 이전 실행에서 남은 파일을 정리한다.
+func (f *RotatingFile) Open() error {
+    f.mutex.Lock()
+    defer f.mutex.Unlock()
+    if err := f.open(); err != nil {
+        return err
+    }
+    f.cleanup()
+    return nil
+}
+
+func (f *RotatingFile) open() error {
+    file, err := os.OpenFile(f.current(), os.O_CREATE | os.O_WRONLY | os.O_APPEND, 0644)
+    if err != nil {
+        return err
+    }
+    info, err := file.Stat()
+    if err != nil {
+        file.Close()
+        return err
+    }
+    f.file = file
+    f.size = info.Size()
+    f.openedAt = time.Now()
+    return nil
+}
+
+func (f *RotatingFile) Write(p []byte) (n int, err error) {
+    f.mutex.Lock()
+    defer f.mutex.Unlock()
+    if f.file == nil {
+        if err = f.open(); err != nil { return }
+    }
+    expired := f.Interval > 0 && time.Now().Sub(f.openedAt) >= f.Interval
+    full := f.MaxSize > 0 && f.size > 0 && f.size + int64(len(p)) > f.MaxSize
+    if expired || full {
+        if err = f.rotate(); err != nil { return }
+    }
+    n, err = f.file.Write(p)
+    f.size += int64(n)
+    return
+}
+
+// 현재 파일을 즉시 교체한다.
+func (f *RotatingFile) Rotate() error {
+    f.mutex.Lock()
+    defer f.mutex.Unlock()
+    return f.rotate()
+}
+
+func (f *RotatingFile) rotate() error {
+    if f.file != nil {
+        if err := f.file.Close(); err != nil { return err }
+        f.file = nil
+    }
+    format := f.TimeFormat
+    if format == "" {
+        format = defaultRotateTimeFormat
+    }
+    base := filepath.Join(f.Dir, f.Prefix + "-" + time.Now().Format(format))
+    name := base + ".log"
+    for i := 2; ; i++ {
+        if _, err := os.Stat(name); os.IsNotExist(err) { break }
+        name = fmt.Sprintf("%s.%d.log", base, i)
+    }
+    if err := os.Rename(f.current(), name); err != nil && !os.IsNotExist(err) {
+        return err
+    }
+    if err := f.open(); err != nil {
+        return err
+    }
+    f.cleanup()
+    return nil
+}
+
+// 보관 기간이 지났거나 최대 개수를 넘은 교체된 파일을 오래된 순으로 삭제한다.
+func (f *RotatingFile) cleanup() {
+    if f.MaxAge <= 0 && f.MaxBackups <= 0 {
+        return
+    }
+    matches, err := filepath.Glob(filepath.Join(f.Dir, f.Prefix + "-*.log"))
+    if err != nil {
+        return
+    }
+    type backup struct {
+        path    string
+        modTime time.Time
+    }
+    backups := []backup{}
+    for _, path := range matches {
+        if info, err := os.Stat(path); err == nil && !info.IsDir() && strings.HasSuffix(path, ".log") {
+            backups = append(backups, backup{ path, info.ModTime() })
+        }
+    }
+    sort.Slice(backups, func(i, j int) bool { return backups[i].modTime.After(backups[j].modTime) })
+    for i, b := range backups {
+        tooMany := f.MaxBackups > 0 && i >= f.MaxBackups
+        tooOld := f.MaxAge > 0 && time.Now().Sub(b.modTime) > f.MaxAge
+        if tooMany || tooOld {
+            os.Remove(b.path)
+        }
+    }
+}
+
+func (f *RotatingFile) Close() error {
+    f.mutex.Lock()
+    defer f.mutex.Unlock()
+    if f.file == nil {
+        return nil
+    }
+    err := f.file.Close()
+    f.file = nil
+    return err
+}
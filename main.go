@@ -1,105 +1,114 @@
 package main
 
 import (
-	"io"
+	"context"
+	"fmt"
 	"log"
+	"log/slog"
 	"okra_board2/config"
-	"okra_board2/controllers"
+	"okra_board2/migrations"
 	"okra_board2/module"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
 
-    conf, err := config.LoadConfig()
+    conf, args, err := config.LoadConfig(os.Args[1:])
     if err != nil {
         log.Println("설정 파일을 불러오지 못했습니다. 서버를 종료합니다.")    
         log.Println(err.Error())
+        os.Exit(1)
+    }
+    // 스키마 마이그레이션 명령. DB 설정만 사용하므로 다른 설정을 검증하거나 로그 파일을 만들지 않고 실행한 뒤 종료한다.
+    if len(args) > 0 && args[0] == "migrate" {
+        if err := migrate(conf, args[1:]); err != nil {
+            fmt.Println(err.Error())
+            os.Exit(1)
+        }
         return
     }
 
-    if file, err := config.InitLogger(conf); err != nil {
-        log.Println("로그 파일을 생성하지 못했습니다. 서버를 종료합니다.")
+    if err := conf.Validate(); err != nil {
         log.Println(err.Error())
-        return
-    } else {
-        gin.DefaultWriter = io.MultiWriter(file)
-        log.SetOutput(file)
+        os.Exit(1)
     }
 
-    db, err := config.InitDBConnection(conf)
-    if err != nil {
-        log.Println("DB 연결에 실패했습니다. 서버를 종료합니다.")
-        log.Println(err.Error())
-        return
+    // 실패 시 run 안의 defer로 로그 파일과 DB 연결을 정리한 뒤 0이 아닌 상태로 종료하여
+    // systemd, Kubernetes 등이 프로세스를 다시 시작할 수 있도록 한다.
+    if err := run(conf); err != nil {
+        os.Exit(1)
     }
+}
 
-    s3, err := config.InitAwsS3Client(conf)
+// 로그, trace, DB, 이미지 저장소를 초기화하고 종료 신호를 받을 때까지 서버를 실행한다.
+// 초기화에 실패하거나 서버가 비정상적으로 종료된 경우 에러를 기록하고 반환한다.
+func run(conf *config.Config) error {
+    logger, logFile, err := module.InitLogger(conf)
     if err != nil {
-        log.Println("AWS S3 연결에 실패했습니다. 서버를 종료합니다.")
+        log.Println("로그 파일을 생성하지 못했습니다. 서버를 종료합니다.")
         log.Println(err.Error())
-        return
+        return err
     }
+    defer logFile.Close()
+    // log 패키지로 기록되는 로그도 같은 Logger를 통해 기록된다.
+    slog.SetDefault(logger)
 
-    os.Setenv("ACCESS_SECRET", conf.AccessSecret)
-    os.Setenv("REFRESH_SECRET", conf.RefreshSecret)
-    os.Setenv("DOMAIN", conf.Domain)
-    os.Setenv("DEFAULT_THUMBNAIL", conf.DefaultThumbnail)
-    
-    gin.SetMode(gin.ReleaseMode)
-    route := gin.New()
-    route.Use(cors.New(cors.Config {
-        AllowAllOrigins:    true,
-        AllowMethods:       []string{"GET", "POST", "PUT", "DELETE"},
-        AllowHeaders:       []string{"Content-Type", "Authorization"},
-        ExposeHeaders:      []string{"Authorization"},
-        AllowCredentials:   true,
-        MaxAge: 12 * time.Hour,
-    }))
-    route.Use(gin.LoggerWithConfig(gin.LoggerConfig{SkipPaths: []string{"/"}}))
-    route.Use(gin.Recovery())
-
-    route.Static("/images", "./public/images")
-
-    authController := module.InitAuthController(db)
-    adminController := module.InitAdminController(db)
-    postController := module.InitPostController(db, conf, s3)
-    //imageController := controllers.NewImageControllerImpl()
-    imageController := controllers.NewImageControllerImpl2(conf, s3)
-
-    // Route for health check
-    route.GET("/", func(c *gin.Context) {
-        c.Status(200)
-    })
-
-    v1 := route.Group("/api/v1")
-    {
-        v1.GET("/posts_enabled", postController.GetPosts(true))
-        v1.GET("/posts_enabled/:postId", postController.GetPost(true))
-        v1.GET("/thumbnails", postController.GetSelectedThumbnails)
-
-        v1.GET("/posts", authController.Auth, postController.GetPosts(false))
-        v1.GET("/posts/:postId", authController.Auth, postController.GetPost(false))
+    shutdownTracer, err := module.InitTracer(context.Background(), conf)
+    if err != nil {
+        slog.Error("trace exporter를 생성하지 못했습니다. 서버를 종료합니다.", "error", err)
+        return err
+    }
+    defer func() {
+        // 종료 전 남은 span을 내보낸다.
+        ctx, cancel := context.WithTimeout(context.Background(), 5 * time.Second)
+        defer cancel()
+        if err := shutdownTracer(ctx); err != nil {
+            slog.Error("span을 내보내지 못했습니다.", "error", err)
+        }
+    }()
+
+    db, err := module.InitDBConnection(conf)
+    if err != nil {
+        slog.Error("DB 연결에 실패했습니다. 서버를 종료합니다.", "error", err)
+        return err
+    }
+    if sqlDB, err := db.DB(); err == nil {
+        defer sqlDB.Close()
+    }
 
-        v1.POST("/posts", authController.Auth, postController.WritePost)
-        v1.PUT("/posts/:postId", authController.Auth, postController.UpdatePost)
-        v1.DELETE("/posts/:postId", authController.Auth, postController.DeletePost)
-        v1.POST("/posts/selected", authController.Auth, postController.ResetSelectedPosts)
+    if conf.DB.AutoMigrate {
+        applied, err := migrations.NewMigratorImpl(db, migrations.All).Up()
+        for _, migration := range applied {
+            slog.Info("마이그레이션을 적용했습니다.", "version", migration.Version, "name", migration.Name)
+        }
+        if err != nil {
+            slog.Error("마이그레이션에 실패했습니다. 서버를 종료합니다.", "error", err)
+            return err
+        }
+    }
 
-        // TODO
-        v1.POST("/admin", authController.Auth, adminController.Register)
-        v1.PUT("/admin/:id", authController.Auth, adminController.Update)
-        v1.DELETE("/admin/:id", authController.Auth, adminController.Delete)
-        v1.POST("/admin/login", authController.Login)
-        v1.POST("/admin/logout", authController.Logout)
-        v1.POST("/admin/auth", authController.ReissueAccessToken)
+    s3, err := module.InitAwsS3Client(conf)
+    if err != nil {
+        slog.Error("AWS S3 연결에 실패했습니다. 서버를 종료합니다.", "error", err)
+        return err
+    }
 
-        v1.POST("/image/upload", authController.Auth, imageController.UploadImage) 
-        v1.POST("/image/delete", authController.Auth, imageController.DeleteImage)
+    gin.SetMode(gin.ReleaseMode)
+    srv := newServer(conf, db, s3)
+    srv.prepare()
+
+    // SIGTERM 또는 SIGINT를 받으면 처리 중인 요청을 마친 뒤 DB 연결과 로그 파일을 닫고 종료한다.
+    ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+    defer stop()
+    if err := srv.serve(ctx); err != nil {
+        slog.Error("서버가 비정상적으로 종료되었습니다.", "error", err)
+        return err
     }
-    route.Run(":3000")
+    slog.Info("서버를 종료합니다.")
+    return nil
 }
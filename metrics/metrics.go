@@ -0,0 +1,179 @@
+package metrics
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "okra"
+
+// 작업 결과
+const (
+    ResultSuccess   = "success"
+    ResultError     = "error"
+    ResultFailure   = "failure"
+)
+
+// 모든 지표가 등록되는 Registry
+var Registry = prometheus.NewRegistry()
+
+var (
+    httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name: "http_requests_total",
+        Help: "처리한 HTTP 요청 수",
+    }, []string{ "method", "route", "status" })
+
+    httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+        Namespace: namespace,
+        Name: "http_request_duration_seconds",
+        Help: "HTTP 요청 처리 시간",
+        Buckets: prometheus.DefBuckets,
+    }, []string{ "method", "route", "status" })
+
+    storageOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name: "storage_operations_total",
+        Help: "이미지 저장소(S3) 요청 수",
+    }, []string{ "operation", "result" })
+
+    storageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+        Namespace: namespace,
+        Name: "storage_operation_duration_seconds",
+        Help: "이미지 저장소(S3) 요청 처리 시간",
+        Buckets: prometheus.DefBuckets,
+    }, []string{ "operation" })
+
+    logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name: "admin_logins_total",
+        Help: "관리자 로그인 시도 수",
+    }, []string{ "result" })
+
+    jobRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name: "job_runs_total",
+        Help: "백그라운드 작업 실행 수",
+    }, []string{ "job", "result" })
+
+    jobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+        Namespace: namespace,
+        Name: "job_duration_seconds",
+        Help: "백그라운드 작업 실행 시간",
+        Buckets: []float64{ .01, .05, .1, .5, 1, 5, 10, 30, 60 },
+    }, []string{ "job" })
+
+    jobLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+        Namespace: namespace,
+        Name: "job_last_success_timestamp_seconds",
+        Help: "백그라운드 작업이 마지막으로 성공한 시각",
+    }, []string{ "job" })
+)
+
+func init() {
+    Registry.MustRegister(
+        collectors.NewGoCollector(),
+        collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+        httpRequests, httpDuration,
+        storageOperations, storageDuration,
+        logins,
+        jobRuns, jobDuration, jobLastSuccess,
+    )
+}
+
+var dbMutex sync.Mutex
+var dbCollector prometheus.Collector
+
+// DB 연결 풀의 통계를 등록한다. 이미 등록된 DB가 있을 경우 교체한다.
+func RegisterDB(db *sql.DB, name string) {
+    dbMutex.Lock()
+    defer dbMutex.Unlock()
+    if dbCollector != nil {
+        Registry.Unregister(dbCollector)
+    }
+    dbCollector = collectors.NewDBStatsCollector(db, name)
+    Registry.MustRegister(dbCollector)
+}
+
+// 요청 수와 처리 시간을 경로의 템플릿(ex. /api/v1/posts/:postId)과 상태 코드별로 기록한다.
+// 등록되지 않은 경로의 요청은 route가 unmatched로 기록된다.
+func Middleware() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        start := time.Now()
+        c.Next()
+        route := c.FullPath()
+        if route == "" {
+            route = "unmatched"
+        }
+        status := strconv.Itoa(c.Writer.Status())
+        httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+        httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+    }
+}
+
+// 지표를 Prometheus 형식으로 응답한다. token이 비어있지 않을 경우 Authorization: Bearer <token> 헤더가 필요하다.
+func Handler(token string) http.Handler {
+    handler := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{ Registry: Registry })
+    if token == "" {
+        return handler
+    }
+    expected := []byte("Bearer " + token)
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+            w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+            w.WriteHeader(http.StatusUnauthorized)
+            return
+        }
+        handler.ServeHTTP(w, r)
+    })
+}
+
+// 관리자 로그인 결과를 기록한다.
+func ObserveLogin(success bool) {
+    if success {
+        logins.WithLabelValues(ResultSuccess).Inc()
+    } else {
+        logins.WithLabelValues(ResultFailure).Inc()
+    }
+}
+
+// start에 시작한 작업의 결과를 기록한다.
+func ObserveJob(job string, start time.Time, err error) {
+    jobDuration.WithLabelValues(job).Observe(time.Since(start).Seconds())
+    if err != nil {
+        jobRuns.WithLabelValues(job, ResultError).Inc()
+        return
+    }
+    jobRuns.WithLabelValues(job, ResultSuccess).Inc()
+    jobLastSuccess.WithLabelValues(job).SetToCurrentTime()
+}
+
+// S3 클라이언트의 모든 요청을 기록하는 미들웨어. s3.Options.APIOptions에 추가한다.
+func S3Middleware(stack *middleware.Stack) error {
+    return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("OkraMetrics", func(
+        ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+    ) (out middleware.InitializeOutput, metadata middleware.Metadata, err error) {
+        start := time.Now()
+        out, metadata, err = next.HandleInitialize(ctx, in)
+        operation := awsmiddleware.GetOperationName(ctx)
+        storageDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+        if err != nil {
+            storageOperations.WithLabelValues(operation, ResultError).Inc()
+        } else {
+            storageOperations.WithLabelValues(operation, ResultSuccess).Inc()
+        }
+        return
+    }), middleware.After)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"okra_board2/config"
+	"okra_board2/migrations"
+	"okra_board2/module"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// db 설정만 검증하고 DB에 연결하여 마이그레이션 명령을 실행한다.
+func migrate(conf *config.Config, args []string) error {
+    if err := conf.DB.Validate(); err != nil { return err }
+    db, err := module.InitDBConnection(conf)
+    if err != nil { return err }
+    if sqlDB, err := db.DB(); err == nil {
+        defer sqlDB.Close()
+    }
+    return runMigrate(db, args)
+}
+
+// okra_board2 migrate up|down [steps]|status
+// down의 steps를 생략할 경우 마지막 마이그레이션 하나만 되돌린다.
+func runMigrate(db *gorm.DB, args []string) error {
+    migrator := migrations.NewMigratorImpl(db, migrations.All)
+    if len(args) == 0 {
+        return errors.New("usage: okra_board2 migrate up|down [steps]|status")
+    }
+
+    switch args[0] {
+    case "up":
+        applied, err := migrator.Up()
+        for _, migration := range applied {
+            fmt.Printf("applied  %04d_%s\n", migration.Version, migration.Name)
+        }
+        if err == nil && len(applied) == 0 {
+            fmt.Println("schema is up to date")
+        }
+        return err
+    case "down":
+        steps := 1
+        if len(args) > 1 {
+            var err error
+            if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+                return fmt.Errorf("invalid steps: %s", args[1])
+            }
+        }
+        reverted, err := migrator.Down(steps)
+        for _, migration := range reverted {
+            fmt.Printf("reverted %04d_%s\n", migration.Version, migration.Name)
+        }
+        return err
+    case "status":
+        status, err := migrator.Status()
+        if err != nil { return err }
+        for _, item := range status {
+            appliedAt := "pending"
+            if item.AppliedAt != nil {
+                appliedAt = item.AppliedAt.Format("2006-01-02 15:04:05")
+            }
+            fmt.Printf("%04d_%-24s %s\n", item.Version, item.Name, appliedAt)
+        }
+        return nil
+    }
+    return fmt.Errorf("unknown migrate command: %s", args[0])
+}
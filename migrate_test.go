@@ -0,0 +1,21 @@
+package main
+
+import (
+	"okra_board2/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 마이그레이션 명령은 db 설정만으로 실행할 수 있다.
+func TestMigrate(t *testing.T) {
+    conf := &config.Config{
+        DB: config.DBConfig{ Driver: config.DriverSQLite, Database: ":memory:" },
+    }
+    assert.NoError(t, migrate(conf, []string{ "up" }))
+    assert.Error(t, migrate(conf, []string{ "sideways" }))
+
+    conf.DB = config.DBConfig{ Driver: config.DriverMySQL }
+    err := migrate(conf, []string{ "status" })
+    assert.IsType(t, config.ValidationError{}, err)
+}
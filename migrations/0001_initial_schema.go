@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 게시물마다 태그 이름을 저장하던 기존 post_tags 구조. 0003_tags에서 변환된다.
+type legacyPostTagTable struct {
+    TagID       int         `gorm:"primaryKey"`
+    Name        string      `gorm:"size:100"`
+    PostID      int         `gorm:"index"`
+}
+
+// 마이그레이션 도입 이전의 스키마
+var initialSchema = Migration{
+    Version: 1,
+    Name: "initial_schema",
+    Up: func(tx *gorm.DB) error {
+        type post struct {
+            PostID      int         `gorm:"primaryKey"`
+            BoardID     int
+            Title       string
+            Thumbnail   string
+            Content     string
+            AddedDate   time.Time   `gorm:"not null;default:CURRENT_TIMESTAMP"`
+            Status      bool
+            Selected    bool
+            Views       int
+        }
+        type admin struct {
+            ID          string      `gorm:"primaryKey;size:50"`
+            Password    string      `gorm:"size:255"`
+            Name        string      `gorm:"size:50"`
+            Email       string      `gorm:"size:255"`
+            Phone       string      `gorm:"size:20"`
+        }
+        type adminAuth struct {
+            UUID        string      `gorm:"primaryKey;size:36"`
+            AdminID     string      `gorm:"size:50;index"`
+            AccessToken string      `gorm:"size:1024"`
+            RefreshToken string     `gorm:"size:1024"`
+        }
+
+        if err := createTable(tx, "posts", &post{}); err != nil { return err }
+        if err := createTable(tx, "post_tags", &legacyPostTagTable{}); err != nil { return err }
+        if err := createTable(tx, "admins", &admin{}); err != nil { return err }
+        return createTable(tx, "admin_auths", &adminAuth{})
+    },
+    Down: func(tx *gorm.DB) error {
+        return dropTables(tx, "admin_auths", "admins", "post_tags", "posts")
+    },
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 게시물의 일자별 조회수
+var postViews = Migration{
+    Version: 2,
+    Name: "post_views",
+    Up: func(tx *gorm.DB) error {
+        type postView struct {
+            PostID      int         `gorm:"primaryKey;autoIncrement:false"`
+            ViewDate    time.Time   `gorm:"primaryKey;type:date"`
+            Count       int
+        }
+        return createTable(tx, "post_views", &postView{})
+    },
+    Down: func(tx *gorm.DB) error {
+        return dropTables(tx, "post_views")
+    },
+}
@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"okra_board2/utils/slug"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tagTable struct {
+    TagID       int         `gorm:"primaryKey"`
+    Name        string      `gorm:"size:100;not null"`
+    Slug        string      `gorm:"size:100;not null;uniqueIndex"`
+}
+
+type postTagTable struct {
+    PostID      int         `gorm:"primaryKey;autoIncrement:false"`
+    TagID       int         `gorm:"primaryKey;autoIncrement:false;index"`
+}
+
+// 게시물마다 이름을 중복 저장하던 기존 post_tags 테이블을 tags 테이블과 연결 테이블로 변환한다.
+// 기존 테이블은 legacy_post_tags로 이름을 바꾸어 보존하며, 비어있을 경우 삭제한다.
+var tags = Migration{
+    Version: 3,
+    Name: "tags",
+    Up: func(tx *gorm.DB) error {
+        if err := createTable(tx, "tags", &tagTable{}); err != nil { return err }
+
+        migrator := tx.Migrator()
+        if !migrator.HasColumn("post_tags", "name") {
+            return createTable(tx, "post_tags", &postTagTable{})
+        }
+        var count int64
+        if err := tx.Table("post_tags").Count(&count).Error; err != nil { return err }
+        if count == 0 {
+            if err := dropTables(tx, "post_tags"); err != nil { return err }
+            return createTable(tx, "post_tags", &postTagTable{})
+        }
+        if err := migrator.RenameTable("post_tags", "legacy_post_tags"); err != nil { return err }
+        if err := createTable(tx, "post_tags", &postTagTable{}); err != nil { return err }
+
+        var legacyTags []struct {
+            Name        string
+            PostID      int
+        }
+        err := tx.Table("legacy_post_tags").Order("tag_id asc").Find(&legacyTags).Error
+        if err != nil { return err }
+
+        for _, legacyTag := range legacyTags {
+            name := strings.Join(strings.Fields(legacyTag.Name), " ")
+            tagSlug := slug.MakeTag(name)
+            if tagSlug == "" { continue }
+
+            tag := tagTable{}
+            err := tx.Table("tags").
+                Where(tagTable{ Slug: tagSlug }).
+                Attrs(tagTable{ Name: name }).
+                FirstOrCreate(&tag).
+                Error
+            if err != nil { return err }
+
+            err = tx.Table("post_tags").
+                Clauses(clause.OnConflict{ DoNothing: true }).
+                Create(&postTagTable{ PostID: legacyTag.PostID, TagID: tag.TagID }).
+                Error
+            if err != nil { return err }
+        }
+        return nil
+    },
+    Down: func(tx *gorm.DB) error {
+        if err := dropTables(tx, "post_tags", "tags"); err != nil { return err }
+        if tx.Migrator().HasTable("legacy_post_tags") {
+            return tx.Migrator().RenameTable("legacy_post_tags", "post_tags")
+        }
+        return createTable(tx, "post_tags", &legacyPostTagTable{})
+    },
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type postDatesColumns struct {
+    PublishedDate *time.Time
+    UpdatedDate *time.Time
+}
+
+// 게시물의 최초 공개일과 수정일. 공개일이 없는 게시물은 작성일을 공개일로 사용한다.
+var postDates = Migration{
+    Version: 4,
+    Name: "post_dates",
+    Up: func(tx *gorm.DB) error {
+        return addColumns(tx, "posts", &postDatesColumns{}, "PublishedDate", "UpdatedDate")
+    },
+    Down: func(tx *gorm.DB) error {
+        return dropColumns(tx, "posts", &postDatesColumns{}, "PublishedDate", "UpdatedDate")
+    },
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 홈페이지에 노출될 게시물의 슬롯.
+// 기존에 선택된(selected) 게시물은 post_id의 역순으로 main 그룹의 슬롯이 된다.
+var featuredSlots = Migration{
+    Version: 5,
+    Name: "featured_slots",
+    Up: func(tx *gorm.DB) error {
+        type featuredSlot struct {
+            SlotID      int         `gorm:"primaryKey"`
+            GroupName   string      `gorm:"size:50;not null;index"`
+            PostID      int
+            Position    int
+            StartDate   *time.Time
+            EndDate     *time.Time
+            Title       *string     `gorm:"size:255"`
+            Image       *string     `gorm:"size:1024"`
+        }
+        if err := createTable(tx, "featured_slots", &featuredSlot{}); err != nil { return err }
+
+        var count int64
+        err := tx.Table("featured_slots").Where("group_name = ?", "main").Count(&count).Error
+        if err != nil || count > 0 { return err }
+        var postIds []int
+        err = tx.Table("posts").
+            Where("selected = ?", true).
+            Order("post_id desc").
+            Pluck("post_id", &postIds).
+            Error
+        if err != nil || len(postIds) == 0 { return err }
+        slots := make([]featuredSlot, len(postIds))
+        for i, postId := range postIds {
+            slots[i] = featuredSlot{ GroupName: "main", PostID: postId, Position: i }
+        }
+        return tx.Table("featured_slots").Create(&slots).Error
+    },
+    Down: func(tx *gorm.DB) error {
+        return dropTables(tx, "featured_slots")
+    },
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type postSeoColumns struct {
+    MetaTitle   *string     `gorm:"size:255"`
+    MetaDescription *string `gorm:"size:500"`
+    CanonicalURL *string    `gorm:"size:1024"`
+    OGImage     *string     `gorm:"size:1024"`
+    NoIndex     *bool
+}
+
+var postSeoFields = []string{"MetaTitle", "MetaDescription", "CanonicalURL", "OGImage", "NoIndex"}
+
+// 게시물별 검색 엔진 및 링크 미리보기용 정보
+var postSeo = Migration{
+    Version: 6,
+    Name: "post_seo",
+    Up: func(tx *gorm.DB) error {
+        return addColumns(tx, "posts", &postSeoColumns{}, postSeoFields...)
+    },
+    Down: func(tx *gorm.DB) error {
+        return dropColumns(tx, "posts", &postSeoColumns{}, postSeoFields...)
+    },
+}
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"fmt"
+	"okra_board2/utils/slug"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type postSlugColumns struct {
+    Slug        string      `gorm:"size:191"`
+}
+
+// 게시판 내에서 고유한 게시물 slug와 변경 전 slug 기록.
+// 기존 게시물은 PostService와 같은 규칙으로 제목으로부터 slug를 생성한다.
+var postSlugs = Migration{
+    Version: 7,
+    Name: "post_slugs",
+    Up: func(tx *gorm.DB) error {
+        type postSlug struct {
+            BoardID     int         `gorm:"primaryKey;autoIncrement:false"`
+            Slug        string      `gorm:"primaryKey;size:191"`
+            PostID      int         `gorm:"index"`
+            AddedDate   time.Time
+        }
+        if err := createTable(tx, "post_slugs", &postSlug{}); err != nil { return err }
+        if err := addColumns(tx, "posts", &postSlugColumns{}, "Slug"); err != nil { return err }
+
+        var posts []struct {
+            PostID      int
+            BoardID     int
+            Title       string
+            Slug        string
+        }
+        if err := tx.Table("posts").Order("post_id asc").Find(&posts).Error; err != nil { return err }
+
+        used := make(map[string]bool)
+        for _, post := range posts {
+            if post.Slug != "" { used[fmt.Sprint(post.BoardID, "/", post.Slug)] = true }
+        }
+        for _, post := range posts {
+            if post.Slug != "" { continue }
+            runes := []rune(slug.Make(post.Title))
+            if len(runes) > 80 { runes = runes[:80] }
+            base := strings.Trim(string(runes), "-")
+            if base == "" { base = "post" }
+
+            candidate := base
+            for i := 2; used[fmt.Sprint(post.BoardID, "/", candidate)]; i++ {
+                candidate = fmt.Sprintf("%s-%d", base, i)
+            }
+            used[fmt.Sprint(post.BoardID, "/", candidate)] = true
+            err := tx.Table("posts").
+                Where("post_id = ?", post.PostID).
+                UpdateColumn("slug", candidate).
+                Error
+            if err != nil { return err }
+        }
+
+        if tx.Migrator().HasIndex("posts", "idx_posts_board_slug") { return nil }
+        return tx.Exec("CREATE UNIQUE INDEX idx_posts_board_slug ON posts (board_id, slug)").Error
+    },
+    Down: func(tx *gorm.DB) error {
+        if tx.Migrator().HasIndex("posts", "idx_posts_board_slug") {
+            if err := tx.Migrator().DropIndex("posts", "idx_posts_board_slug"); err != nil { return err }
+        }
+        if err := dropColumns(tx, "posts", &postSlugColumns{}, "Slug"); err != nil { return err }
+        return dropTables(tx, "post_slugs")
+    },
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type postContentFormatColumns struct {
+    ContentFormat string    `gorm:"size:16;default:html"`
+    Source      *string
+}
+
+// 게시물 내용의 작성 형식과 Markdown 원문
+var postContentFormat = Migration{
+    Version: 8,
+    Name: "post_content_format",
+    Up: func(tx *gorm.DB) error {
+        return addColumns(tx, "posts", &postContentFormatColumns{}, "ContentFormat", "Source")
+    },
+    Down: func(tx *gorm.DB) error {
+        return dropColumns(tx, "posts", &postContentFormatColumns{}, "ContentFormat", "Source")
+    },
+}
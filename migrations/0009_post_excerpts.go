@@ -0,0 +1,20 @@
+package migrations
+
+import "gorm.io/gorm"
+
+type postExcerptsColumns struct {
+    Excerpt     string      `gorm:"size:1000"`
+    ReadingTime int
+}
+
+// 게시물의 요약과 예상 읽기 시간. 기존 게시물의 값은 서버 시작 시 PostService.GenerateMissingExcerpts가 채운다.
+var postExcerpts = Migration{
+    Version: 9,
+    Name: "post_excerpts",
+    Up: func(tx *gorm.DB) error {
+        return addColumns(tx, "posts", &postExcerptsColumns{}, "Excerpt", "ReadingTime")
+    },
+    Down: func(tx *gorm.DB) error {
+        return dropColumns(tx, "posts", &postExcerptsColumns{}, "Excerpt", "ReadingTime")
+    },
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 게시물의 댓글과 게시판별 댓글 설정
+var comments = Migration{
+    Version: 10,
+    Name: "comments",
+    Up: func(tx *gorm.DB) error {
+        type comment struct {
+            CommentID   int         `gorm:"primaryKey"`
+            PostID      int         `gorm:"index"`
+            ParentID    *int        `gorm:"index"`
+            Nickname    string      `gorm:"size:30"`
+            Password    string      `gorm:"size:64"`
+            Content     string      `gorm:"size:2000"`
+            Status      string      `gorm:"size:16;index"`
+            IP          string      `gorm:"size:45;index"`
+            AddedDate   time.Time   `gorm:"not null;default:CURRENT_TIMESTAMP"`
+            UpdatedDate *time.Time
+        }
+        type commentSetting struct {
+            BoardID     int         `gorm:"primaryKey;autoIncrement:false"`
+            Enabled     bool
+        }
+        if err := createTable(tx, "comments", &comment{}); err != nil { return err }
+        return createTable(tx, "comment_settings", &commentSetting{})
+    },
+    Down: func(tx *gorm.DB) error {
+        return dropTables(tx, "comment_settings", "comments")
+    },
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 게시물에 대한 방문자의 반응
+var reactions = Migration{
+    Version: 11,
+    Name: "reactions",
+    Up: func(tx *gorm.DB) error {
+        type reaction struct {
+            PostID      int         `gorm:"primaryKey;autoIncrement:false"`
+            Type        string      `gorm:"primaryKey;size:32"`
+            VisitorID   string      `gorm:"primaryKey;size:64"`
+            AddedDate   time.Time   `gorm:"not null;default:CURRENT_TIMESTAMP;index"`
+        }
+        return createTable(tx, "reactions", &reaction{})
+    },
+    Down: func(tx *gorm.DB) error {
+        return dropTables(tx, "reactions")
+    },
+}
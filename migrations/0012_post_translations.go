@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 게시물의 언어별 번역
+var postTranslations = Migration{
+    Version: 12,
+    Name: "post_translations",
+    Up: func(tx *gorm.DB) error {
+        type postTranslation struct {
+            PostID      int         `gorm:"primaryKey;autoIncrement:false"`
+            Locale      string      `gorm:"primaryKey;size:16"`
+            Title       string
+            Slug        string      `gorm:"size:191;index"`
+            Thumbnail   string
+            Content     string
+            ContentFormat string    `gorm:"size:16;default:html"`
+            Source      *string
+            Excerpt     string      `gorm:"size:1000"`
+            ReadingTime int
+            MetaTitle   *string     `gorm:"size:255"`
+            MetaDescription *string `gorm:"size:500"`
+            OGImage     *string     `gorm:"size:1024"`
+            UpdatedDate *time.Time
+        }
+        return createTable(tx, "post_translations", &postTranslation{})
+    },
+    Down: func(tx *gorm.DB) error {
+        return dropTables(tx, "post_translations")
+    },
+}
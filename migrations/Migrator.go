@@ -0,0 +1,177 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrMigrationLocked = errors.New("Another process is running migrations.")
+
+// 스키마 변경 단위. Version 순으로 적용되며, Down은 Up을 되돌린다.
+type Migration struct {
+    Version     int
+    Name        string
+    Up          func(tx *gorm.DB) error
+    Down        func(tx *gorm.DB) error
+}
+
+// 적용된 마이그레이션 기록
+type SchemaMigration struct {
+    Version     int         `gorm:"primaryKey;autoIncrement:false"`
+    Name        string      `gorm:"size:100"`
+    AppliedAt   time.Time
+}
+
+// 동시에 여러 서버가 시작될 때 마이그레이션이 한 번만 실행되도록 하는 잠금.
+// ID가 1인 행이 존재하는 동안 다른 프로세스는 마이그레이션을 실행할 수 없다.
+type SchemaMigrationLock struct {
+    ID          int         `gorm:"primaryKey;autoIncrement:false"`
+    Owner       string      `gorm:"size:255"`
+    LockedAt    time.Time
+}
+
+// Response Only
+type MigrationStatus struct {
+    Version     int
+    Name        string
+    AppliedAt   *time.Time
+}
+
+type Migrator interface {
+
+    // 적용되지 않은 마이그레이션을 모두 순서대로 적용하고, 적용된 마이그레이션을 반환한다.
+    Up()                (applied []Migration, err error)
+
+    // 마지막으로 적용된 마이그레이션부터 steps개를 되돌리고, 되돌린 마이그레이션을 반환한다.
+    Down(steps int)     (reverted []Migration, err error)
+
+    // 모든 마이그레이션과 적용 시각을 Version 순으로 반환한다. 적용되지 않은 마이그레이션의 AppliedAt은 nil이다.
+    Status()            (status []MigrationStatus, err error)
+
+}
+
+type MigratorImpl struct {
+    db          *gorm.DB
+    migrations  []Migration
+    // 잠금을 기다리는 최대 시간
+    LockTimeout time.Duration
+    // 잠금을 획득한 프로세스가 비정상 종료된 것으로 보고 잠금을 해제하기까지의 시간
+    StaleLockAfter time.Duration
+}
+
+func NewMigratorImpl(db *gorm.DB, migrations []Migration) Migrator {
+    sorted := make([]Migration, len(migrations))
+    copy(sorted, migrations)
+    sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+    return &MigratorImpl{
+        db: db,
+        migrations: sorted,
+        LockTimeout: time.Minute,
+        StaleLockAfter: 10 * time.Minute,
+    }
+}
+
+func (m *MigratorImpl) init() error {
+    return m.db.Migrator().AutoMigrate(&SchemaMigration{}, &SchemaMigrationLock{})
+}
+
+// 잠금을 획득하고 잠금을 해제하는 함수를 반환한다.
+func (m *MigratorImpl) lock() (unlock func(), err error) {
+    host, _ := os.Hostname()
+    owner := fmt.Sprintf("%s:%d", host, os.Getpid())
+    deadline := time.Now().Add(m.LockTimeout)
+    for {
+        lock := &SchemaMigrationLock{ ID: 1, Owner: owner, LockedAt: time.Now() }
+        if m.db.Create(lock).Error == nil {
+            return func() { m.db.Delete(&SchemaMigrationLock{}, "id = ? AND owner = ?", 1, owner) }, nil
+        }
+
+        m.db.Where("id = ? AND locked_at < ?", 1, time.Now().Add(-m.StaleLockAfter)).
+            Delete(&SchemaMigrationLock{})
+        if time.Now().After(deadline) {
+            return nil, ErrMigrationLocked
+        }
+        time.Sleep(time.Second)
+    }
+}
+
+func (m *MigratorImpl) applied() (applied map[int]SchemaMigration, err error) {
+    var rows []SchemaMigration
+    if err = m.db.Order("version asc").Find(&rows).Error; err != nil {
+        return
+    }
+    applied = make(map[int]SchemaMigration)
+    for _, row := range rows {
+        applied[row.Version] = row
+    }
+    return
+}
+
+func (m *MigratorImpl) Up() (applied []Migration, err error) {
+    if err = m.init(); err != nil { return }
+    unlock, err := m.lock()
+    if err != nil { return }
+    defer unlock()
+
+    done, err := m.applied()
+    if err != nil { return }
+    for _, migration := range m.migrations {
+        if _, ok := done[migration.Version]; ok { continue }
+        err = m.db.Transaction(func(tx *gorm.DB) error {
+            if err := migration.Up(tx); err != nil { return err }
+            return tx.Create(&SchemaMigration{
+                Version: migration.Version,
+                Name: migration.Name,
+                AppliedAt: time.Now(),
+            }).Error
+        })
+        if err != nil {
+            return applied, fmt.Errorf("%04d_%s: %w", migration.Version, migration.Name, err)
+        }
+        applied = append(applied, migration)
+    }
+    return
+}
+
+func (m *MigratorImpl) Down(steps int) (reverted []Migration, err error) {
+    if err = m.init(); err != nil { return }
+    unlock, err := m.lock()
+    if err != nil { return }
+    defer unlock()
+
+    done, err := m.applied()
+    if err != nil { return }
+    for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+        migration := m.migrations[i]
+        if _, ok := done[migration.Version]; !ok { continue }
+        err = m.db.Transaction(func(tx *gorm.DB) error {
+            if err := migration.Down(tx); err != nil { return err }
+            return tx.Delete(&SchemaMigration{}, "version = ?", migration.Version).Error
+        })
+        if err != nil {
+            return reverted, fmt.Errorf("%04d_%s: %w", migration.Version, migration.Name, err)
+        }
+        reverted = append(reverted, migration)
+    }
+    return
+}
+
+func (m *MigratorImpl) Status() (status []MigrationStatus, err error) {
+    if err = m.init(); err != nil { return }
+    done, err := m.applied()
+    if err != nil { return }
+    for _, migration := range m.migrations {
+        item := MigrationStatus{ Version: migration.Version, Name: migration.Name }
+        if row, ok := done[migration.Version]; ok {
+            appliedAt := row.AppliedAt
+            item.AppliedAt = &appliedAt
+        }
+        status = append(status, item)
+    }
+    return
+}
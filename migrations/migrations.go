@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 모든 마이그레이션. 새로운 마이그레이션은 목록의 끝에 추가한다.
+// 각 마이그레이션은 적용 당시의 테이블 구조를 별도의 구조체로 정의하여 models의 변경에 영향을 받지 않도록 한다.
+// 마이그레이션 도입 전에 직접 생성된 테이블과 컬럼은 건너뛰므로 기존 DB에도 그대로 적용할 수 있다.
+var All = []Migration{
+    initialSchema,
+    postViews,
+    tags,
+    postDates,
+    featuredSlots,
+    postSeo,
+    postSlugs,
+    postContentFormat,
+    postExcerpts,
+    comments,
+    reactions,
+    postTranslations,
+}
+
+// 테이블이 존재하지 않을 경우 model의 구조로 생성한다.
+func createTable(tx *gorm.DB, table string, model interface{}) error {
+    migrator := tx.Table(table).Migrator()
+    if migrator.HasTable(table) {
+        return nil
+    }
+    return migrator.CreateTable(model)
+}
+
+func dropTables(tx *gorm.DB, tables ...string) error {
+    for _, table := range tables {
+        if err := tx.Migrator().DropTable(table); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// model의 필드 중 fields에 해당하는 컬럼을 존재하지 않을 경우 추가한다.
+func addColumns(tx *gorm.DB, table string, model interface{}, fields ...string) error {
+    migrator := tx.Table(table).Migrator()
+    for _, field := range fields {
+        if migrator.HasColumn(model, field) { continue }
+        if err := migrator.AddColumn(model, field); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// SQLite 드라이버의 DropColumn은 테이블을 다시 생성하므로 트랜잭션 안에서 실패한다.
+// MySQL, PostgreSQL, SQLite(3.35 이상)에서 모두 지원하는 ALTER TABLE ... DROP COLUMN을 사용한다.
+func dropColumns(tx *gorm.DB, table string, model interface{}, fields ...string) error {
+    migrator := tx.Table(table).Migrator()
+    for _, field := range fields {
+        if !migrator.HasColumn(model, field) { continue }
+        column := tx.NamingStrategy.ColumnName("", field)
+        err := tx.Exec("ALTER TABLE ? DROP COLUMN ?", clause.Table{ Name: table }, clause.Column{ Name: column }).Error
+        if err != nil { return err }
+    }
+    return nil
+}
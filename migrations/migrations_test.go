@@ -0,0 +1,80 @@
+package migrations_test
+
+import (
+	"okra_board2/migrations"
+	"okra_board2/testutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrations(t *testing.T) {
+    // 버전은 1부터 빠짐없이 순서대로 증가해야 한다.
+    for i, migration := range migrations.All {
+        assert.Equal(t, i + 1, migration.Version)
+        assert.NotEmpty(t, migration.Name)
+        assert.NotNil(t, migration.Up, migration.Name)
+        assert.NotNil(t, migration.Down, migration.Name)
+    }
+}
+
+func TestMigrator(t *testing.T) {
+    db := testutil.NewDB(t)
+    m := migrations.NewMigratorImpl(db, migrations.All)
+
+    // testutil.NewDB에서 모든 마이그레이션이 적용된다.
+    status, err := m.Status()
+    assert.NoError(t, err)
+    assert.Len(t, status, len(migrations.All))
+    for _, item := range status {
+        assert.NotNil(t, item.AppliedAt, item.Name)
+    }
+    applied, err := m.Up()
+    assert.NoError(t, err)
+    assert.Empty(t, applied)
+
+    // 모두 되돌린 뒤 다시 적용할 수 있다.
+    reverted, err := m.Down(len(migrations.All))
+    assert.NoError(t, err)
+    assert.Len(t, reverted, len(migrations.All))
+    assert.False(t, db.Migrator().HasTable("posts"))
+
+    applied, err = m.Up()
+    assert.NoError(t, err)
+    assert.Len(t, applied, len(migrations.All))
+    assert.True(t, db.Migrator().HasColumn("posts", "slug"))
+    assert.True(t, db.Migrator().HasColumn("post_tags", "tag_id"))
+    assert.False(t, db.Migrator().HasColumn("post_tags", "name"))
+}
+
+func TestFeaturedSlotsBackfill(t *testing.T) {
+    db := testutil.NewDB(t)
+    m := migrations.NewMigratorImpl(db, migrations.All)
+    _, err := m.Down(len(migrations.All) - 4)
+    assert.NoError(t, err)
+    assert.False(t, db.Migrator().HasTable("featured_slots"))
+
+    // 0005_featured_slots 이전에 선택된 게시물
+    for i, selected := range []bool{ true, false, true } {
+        err := db.Table("posts").Create(map[string]interface{}{
+            "post_id": i + 1, "board_id": 1, "title": "title", "status": true, "selected": selected,
+        }).Error
+        assert.NoError(t, err)
+    }
+
+    _, err = m.Up()
+    assert.NoError(t, err)
+    var slots []struct {
+        GroupName   string
+        PostID      int
+        Position    int
+    }
+    db.Table("featured_slots").Order("position asc").Find(&slots)
+    if assert.Len(t, slots, 2) {
+        assert.Equal(t, "main", slots[0].GroupName)
+        assert.Equal(t, 3, slots[0].PostID)
+        assert.Equal(t, 0, slots[0].Position)
+        assert.Equal(t, 1, slots[1].PostID)
+        assert.Equal(t, 1, slots[1].Position)
+    }
+}
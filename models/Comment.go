@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// 댓글의 검토 상태
+const (
+    CommentStatusPending    = "pending"
+    CommentStatusApproved   = "approved"
+    CommentStatusRejected   = "rejected"
+    CommentStatusSpam       = "spam"
+)
+
+type Comment struct {
+    CommentID   int         `json:"commentId" gorm:"primaryKey;<-:false"`
+    PostID      int         `json:"postId" gorm:"index"`
+    // 답글일 경우 원 댓글의 id. 답글에는 다시 답글을 달 수 없다.
+    ParentID    *int        `json:"parentId,omitempty" gorm:"index"`
+    Nickname    string      `json:"nickname" gorm:"size:30"`
+    // 수정, 삭제 시 확인하는 비밀번호. SHA256으로 저장되며 응답에는 포함되지 않는다.
+    Password    string      `json:"password,omitempty" gorm:"size:64"`
+    Content     string      `json:"content" gorm:"size:2000"`
+    Status      string      `json:"status,omitempty" gorm:"size:16;index"`
+    IP          string      `json:"-" gorm:"size:45;index"`
+    AddedDate   time.Time   `json:"addedDate" gorm:"->"`
+    UpdatedDate *time.Time  `json:"updatedDate,omitempty"`
+
+    Replies     []Comment   `json:"replies,omitempty" gorm:"-"`
+}
+
+// 게시판별 댓글 설정. 설정이 없는 게시판은 댓글을 사용한다.
+type CommentSetting struct {
+    BoardID     int         `json:"boardId" gorm:"primaryKey;autoIncrement:false"`
+    Enabled     bool        `json:"enabled"`
+}
+
+type CommentValidationResult struct {
+    ParentID    *string     `json:"parentId,omitempty"`
+    Nickname    *string     `json:"nickname,omitempty"`
+    Password    *string     `json:"password,omitempty"`
+    Content     *string     `json:"content,omitempty"`
+    Status      *string     `json:"status,omitempty"`
+}
+
+func (result *CommentValidationResult) GetOrNil() *CommentValidationResult {
+    if result.ParentID == nil && result.Nickname == nil && result.Password == nil &&
+        result.Content == nil && result.Status == nil {
+        return nil
+    }
+    return result
+}
@@ -0,0 +1,28 @@
+package models
+
+// Response Only
+// 모든 API 에러 응답의 형식
+type ErrorResponse struct {
+    Error       ErrorBody   `json:"error"`
+}
+
+// Response Only
+type ErrorBody struct {
+    // 에러를 구분하는 변하지 않는 코드 (ex. not_found, validation_failed)
+    Code        string      `json:"code"`
+    Status      int         `json:"status"`
+    // 요청한 언어로 번역된 메시지
+    Message     string      `json:"message"`
+    // 유효성 검사에 실패한 항목
+    Fields      []FieldError `json:"fields,omitempty"`
+    // 에러에 따른 추가 정보 (ex. 존재하지 않는 게시물 번호 목록)
+    Details     interface{} `json:"details,omitempty"`
+    RequestID   string      `json:"requestId,omitempty"`
+}
+
+// Response Only
+type FieldError struct {
+    // 요청 본문의 json 필드 또는 경로/쿼리 파라미터의 이름 (ex. title, slots[2], postId)
+    Field       string      `json:"field"`
+    Message     string      `json:"message"`
+}
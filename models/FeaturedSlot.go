@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// 홈페이지에 노출될 게시물의 슬롯.
+// GroupName으로 구분되는 여러 슬롯 그룹(main, sidebar 등)이 존재하며,
+// 각 그룹 내에서 Position 순으로 정렬된다.
+type FeaturedSlot struct {
+    SlotID      int         `json:"slotId,omitempty" gorm:"primaryKey"`
+    GroupName   string      `json:"group" gorm:"size:50;not null;index"`
+    PostID      int         `json:"postId"`
+    Position    int         `json:"position"`
+    StartDate   *time.Time  `json:"startDate,omitempty"`
+    EndDate     *time.Time  `json:"endDate,omitempty"`
+    Title       *string     `json:"title,omitempty" gorm:"size:255"`
+    Image       *string     `json:"image,omitempty" gorm:"size:1024"`
+}
+
+// Response Only
+type FeaturedPost struct {
+    SlotID      int         `json:"slotId"`
+    PostID      int         `json:"postId"`
+    BoardID     int         `json:"boardId"`
+    Slug        string      `json:"slug,omitempty"`
+    Position    int         `json:"position"`
+    Title       string      `json:"title"`
+    Thumbnail   string      `json:"thumbnail"`
+    Image       *string     `json:"image,omitempty"`
+}
+
+// Response Only
+type FeaturedSlotValidationResult struct {
+    Group       *string         `json:"group,omitempty"`
+    // 유효하지 않은 슬롯의 순서(index)와 메시지
+    Slots       map[int]string  `json:"slots,omitempty"`
+}
+
+func (result *FeaturedSlotValidationResult) GetOrNil() *FeaturedSlotValidationResult {
+    if result.Group == nil && len(result.Slots) == 0 {
+        return nil
+    }
+    return result
+}
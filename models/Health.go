@@ -0,0 +1,26 @@
+package models
+
+// 상태 확인 결과
+const (
+    HealthStatusOK      = "ok"
+    HealthStatusFail    = "fail"
+    // 종료 중이므로 더 이상 요청을 받지 않는다.
+    HealthStatusShutdown = "shutting_down"
+)
+
+// Response Only
+type HealthCheck struct {
+    Name        string      `json:"name"`
+    Status      string      `json:"status"`
+    LatencyMs   float64     `json:"latencyMs"`
+}
+
+// Response Only
+type HealthReport struct {
+    Status      string          `json:"status"`
+    Checks      []HealthCheck   `json:"checks"`
+}
+
+func (report *HealthReport) OK() bool {
+    return report.Status == HealthStatusOK
+}
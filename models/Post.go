@@ -2,52 +2,140 @@ package models
 
 import "time"
 
+// 게시물 내용의 작성 형식
+const (
+    ContentFormatHTML       = "html"
+    ContentFormatMarkdown   = "markdown"
+)
+
 type Post struct {
     PostID      int         `json:"postId,omitempty" gorm:"primaryKey;<-:false"`
-    BoardID     int         `json:"boardId"`
+    BoardID     int         `json:"boardId" gorm:"index:idx_posts_board_slug,unique"`
     Title       string      `json:"title"`
+    // 게시판 내에서 고유한 게시물 주소. 비어있을 경우 제목으로부터 생성된다.
+    Slug        string      `json:"slug,omitempty" gorm:"size:191;index:idx_posts_board_slug,unique"`
     Thumbnail   string      `json:"thumbnail"`
     Content     string      `json:"content,omitempty"`
+    // 내용의 작성 형식. 비어있을 경우 html.
+    ContentFormat string    `json:"contentFormat,omitempty" gorm:"size:16;default:html"`
+    // Markdown 형식일 경우 작성된 원문. Content에는 변환된 HTML이 저장되며,
+    // 공개된 게시물 조회 시에는 포함되지 않는다.
+    Source      *string     `json:"source,omitempty"`
+    // 내용으로부터 계산되는 일반 텍스트 요약과 예상 읽기 시간(분)
+    Excerpt     string      `json:"excerpt,omitempty" gorm:"size:1000"`
+    ReadingTime int         `json:"readingTime,omitempty"`
     AddedDate   time.Time   `json:"addedDate,omitempty" gorm:"->"`
+    PublishedDate *time.Time `json:"publishedDate,omitempty"`
+    UpdatedDate *time.Time  `json:"updatedDate,omitempty"`
     Status      bool        `json:"status"`
     Selected    bool        `json:"selected"`
     Views       int         `json:"views"`
 
-    Tags        []PostTag   `json:"tags,omitempty" gorm:"foreignKey:PostID"`
+    // 검색 엔진 및 링크 미리보기용 정보. 비어있을 경우 게시물의 내용으로부터 생성된다.
+    MetaTitle   *string     `json:"metaTitle,omitempty" gorm:"size:255"`
+    MetaDescription *string `json:"metaDescription,omitempty" gorm:"size:500"`
+    CanonicalURL *string    `json:"canonicalUrl,omitempty" gorm:"size:1024"`
+    OGImage     *string     `json:"ogImage,omitempty" gorm:"size:1024"`
+    NoIndex     *bool       `json:"noIndex,omitempty"`
+
+    Tags        []Tag       `json:"tags,omitempty" gorm:"many2many:post_tags;joinForeignKey:PostID;joinReferences:TagID"`
 
     Prev        *PostE      `json:"prev,omitempty" gorm:"-"`
     Next        *PostE      `json:"next,omitempty" gorm:"-"`
+    Related     []RelatedPost `json:"related,omitempty" gorm:"-"`
+    // 응답에 사용된 언어와 게시물을 제공하는 언어 목록
+    Locale      string      `json:"locale,omitempty" gorm:"-"`
+    Locales     []string    `json:"locales,omitempty" gorm:"-"`
+    // 반응 종류별 개수
+    Reactions   map[string]int `json:"reactions,omitempty" gorm:"-"`
+    // 게시물 상세 조회 시 제공되는 목차
+    TOC         []TOCItem   `json:"toc,omitempty" gorm:"-"`
 }
 
-type PostTag struct {
-    TagID       int         `json:"tagId,omitempty" gorm:"primaryKey;<-:false"`
-    Name        string      `json:"name"`
-    PostID      int         `json:"postId"`
+// Response Only
+type TOCItem struct {
+    Level       int         `json:"level"`
+    // 내용에서 제목에 해당하는 요소의 id
+    ID          string      `json:"id"`
+    Text        string      `json:"text"`
+}
+
+// 변경되기 전의 게시물 slug. 이전 주소를 현재 주소로 연결하기 위해 보존한다.
+type PostSlug struct {
+    BoardID     int         `json:"boardId" gorm:"primaryKey;autoIncrement:false"`
+    Slug        string      `json:"slug" gorm:"primaryKey;size:191"`
+    PostID      int         `json:"postId" gorm:"index"`
+    AddedDate   time.Time   `json:"addedDate"`
 }
 
 type PostE struct {
     PostID      string      `json:"postId"`
     Title       string      `json:"title"`
+    Slug        string      `json:"slug,omitempty"`
 }
 
+// Response Only
+type RelatedPost struct {
+    PostID      int         `json:"postId"`
+    BoardID     int         `json:"boardId"`
+    Slug        string      `json:"slug,omitempty"`
+    Title       string      `json:"title"`
+    Thumbnail   string      `json:"thumbnail"`
+    Score       float64     `json:"score"`
+}
 
 // Response Only
 type PostValidationResult struct {
     Title       *string     `json:"title,omitempty"`
+    Slug        *string     `json:"slug,omitempty"`
+    Locale      *string     `json:"locale,omitempty"`
     Thumbnail   *string     `json:"thumbnail,omitempty"`
     Content     *string     `json:"content,omitempty"`
+    ContentFormat *string   `json:"contentFormat,omitempty"`
+    CanonicalURL *string    `json:"canonicalUrl,omitempty"`
+    OGImage     *string     `json:"ogImage,omitempty"`
+    // 저장 시 정제되어 제거된 태그와 속성 (ex. {"content": ["<script>", "<a onclick>"]})
+    // 유효성 검사 실패에는 해당하지 않는다.
+    Sanitized   map[string][]string `json:"sanitized,omitempty"`
+}
+
+// 유효성 검사에 실패한 항목이 없을 경우 true를 반환한다.
+func (result *PostValidationResult) Valid() bool {
+    return result.Title == nil && result.Slug == nil && result.Locale == nil && result.Thumbnail == nil && result.Content == nil &&
+        result.ContentFormat == nil && result.CanonicalURL == nil && result.OGImage == nil
 }
 
 func (result *PostValidationResult) GetOrNil() *PostValidationResult {
-    if result.Title == nil && result.Thumbnail == nil && result.Content == nil {
+    if result.Valid() && len(result.Sanitized) == 0 {
         return nil
     }
     return result
 }
 
+// Response Only
+type PostMeta struct {
+    Title       string      `json:"title"`
+    Description string      `json:"description"`
+    CanonicalURL string     `json:"canonicalUrl"`
+    Image       string      `json:"image,omitempty"`
+    NoIndex     bool        `json:"noIndex"`
+    SiteName    string      `json:"siteName,omitempty"`
+    PublishedTime time.Time `json:"publishedTime"`
+    ModifiedTime time.Time  `json:"modifiedTime"`
+    Tags        []string    `json:"tags,omitempty"`
+    // 메타데이터의 언어와 언어별 게시물 주소
+    Locale      string      `json:"locale"`
+    Alternates  map[string]string `json:"alternates,omitempty"`
+    // schema.org Article
+    JSONLD      interface{} `json:"jsonLd"`
+    // head에 그대로 삽입할 수 있는 title, meta, link, script 태그
+    HTML        string      `json:"html"`
+}
+
 // Response Only
 type Thumbnail struct {
     PostID      int         `json:"postId"`
+    Slug        string      `json:"slug,omitempty"`
     Title       string      `json:"title"`
     Thumbnail   string      `json:"thumbnail"`
 }
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// 게시물의 언어별 번역. 비어있는 항목은 게시물 원문의 값을 사용한다.
+type PostTranslation struct {
+    PostID      int         `json:"postId" gorm:"primaryKey;autoIncrement:false"`
+    // 언어 코드 (ex. en, ja)
+    Locale      string      `json:"locale" gorm:"primaryKey;size:16"`
+    Title       string      `json:"title"`
+    // 게시판 내에서 고유한 번역 게시물 주소. 비어있을 경우 제목으로부터 생성된다.
+    Slug        string      `json:"slug,omitempty" gorm:"size:191;index"`
+    Thumbnail   string      `json:"thumbnail,omitempty"`
+    Content     string      `json:"content,omitempty"`
+    ContentFormat string    `json:"contentFormat,omitempty" gorm:"size:16;default:html"`
+    Source      *string     `json:"source,omitempty"`
+    Excerpt     string      `json:"excerpt,omitempty" gorm:"size:1000"`
+    ReadingTime int         `json:"readingTime,omitempty"`
+    MetaTitle   *string     `json:"metaTitle,omitempty" gorm:"size:255"`
+    MetaDescription *string `json:"metaDescription,omitempty" gorm:"size:500"`
+    OGImage     *string     `json:"ogImage,omitempty" gorm:"size:1024"`
+    UpdatedDate *time.Time  `json:"updatedDate,omitempty"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// 게시물의 일자별 조회수
+type PostView struct {
+    PostID      int         `json:"postId" gorm:"primaryKey;autoIncrement:false"`
+    ViewDate    time.Time   `json:"viewDate" gorm:"primaryKey;type:date"`
+    Count       int         `json:"count"`
+}
+
+// Response Only
+type RankedPost struct {
+    PostID      int         `json:"postId"`
+    BoardID     int         `json:"boardId"`
+    Slug        string      `json:"slug,omitempty"`
+    Title       string      `json:"title"`
+    Thumbnail   string      `json:"thumbnail"`
+    Views       int         `json:"views"`
+    Score       float64     `json:"score"`
+}
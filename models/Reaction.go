@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// 게시물에 대한 방문자의 반응. 방문자는 반응 종류별로 한 번씩만 반응할 수 있다.
+type Reaction struct {
+    PostID      int         `json:"postId" gorm:"primaryKey;autoIncrement:false"`
+    Type        string      `json:"type" gorm:"primaryKey;size:32"`
+    // 방문자를 구분하는 해시값
+    VisitorID   string      `json:"-" gorm:"primaryKey;size:64"`
+    AddedDate   time.Time   `json:"addedDate" gorm:"->"`
+}
+
+// Response Only
+type ReactedPost struct {
+    PostID      int         `json:"postId"`
+    BoardID     int         `json:"boardId"`
+    Slug        string      `json:"slug,omitempty"`
+    Title       string      `json:"title"`
+    Thumbnail   string      `json:"thumbnail"`
+    Reactions   int         `json:"reactions"`
+}
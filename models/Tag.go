@@ -0,0 +1,33 @@
+package models
+
+type Tag struct {
+    TagID       int         `json:"tagId,omitempty" gorm:"primaryKey"`
+    Name        string      `json:"name" gorm:"size:100;not null"`
+    Slug        string      `json:"slug,omitempty" gorm:"size:100;not null;uniqueIndex"`
+}
+
+// posts와 tags의 연결 테이블
+type PostTag struct {
+    PostID      int         `json:"postId" gorm:"primaryKey;autoIncrement:false"`
+    TagID       int         `json:"tagId" gorm:"primaryKey;autoIncrement:false"`
+}
+
+// Response Only
+type TagCount struct {
+    TagID       int         `json:"tagId"`
+    Name        string      `json:"name"`
+    Slug        string      `json:"slug"`
+    PostCount   int         `json:"postCount"`
+}
+
+// Response Only
+type TagValidationResult struct {
+    Name        *string     `json:"name,omitempty"`
+}
+
+func (result *TagValidationResult) GetOrNil() *TagValidationResult {
+    if result.Name == nil {
+        return nil
+    }
+    return result
+}
@@ -0,0 +1,136 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"okra_board2/config"
+	"okra_board2/logging"
+	"okra_board2/metrics"
+	"okra_board2/tracing"
+	"os"
+	"strings"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func dialector(conf *config.DBConfig) (gorm.Dialector, error) {
+    dsn := conf.ToString()
+    switch conf.Driver {
+    case "", config.DriverMySQL:
+        return mysql.Open(dsn), nil
+    case config.DriverPostgres:
+        return postgres.Open(dsn), nil
+    case config.DriverSQLite:
+        return sqlite.Open(dsn), nil
+    }
+    return nil, fmt.Errorf("Unknown DB driver: %s", conf.Driver)
+}
+
+func InitDBConnection(conf *config.Config) (*gorm.DB, error){
+    dialector, err := dialector(&conf.DB)
+    if err != nil { return nil, err }
+    sqlLevel, err := logging.ParseSQLLevel(conf.Log.SQL.Level)
+    if err != nil { return nil, err }
+    slowThreshold := time.Duration(conf.Log.SQL.SlowThreshold) * time.Millisecond
+    if slowThreshold <= 0 {
+        slowThreshold = 200 * time.Millisecond
+    }
+    db, err := gorm.Open(dialector, &gorm.Config{
+        Logger: logging.NewGormLogger(sqlLevel, slowThreshold),
+        NowFunc: func() time.Time {
+            ti, _ := time.LoadLocation("Asia/Seoul")
+            return time.Now().In(ti)
+        },
+    })
+    if err != nil { return nil, err }
+    if err := db.Use(tracing.NewGormPlugin()); err != nil { return nil, err }
+
+    sqlDB, err := db.DB()
+    if err != nil { return nil, err }
+    // SQLite는 한 번에 하나의 연결만 쓸 수 있으며, 메모리 DB는 연결마다 별도의 DB가 생성되므로 연결을 하나만 사용한다.
+    if conf.DB.Driver == config.DriverSQLite {
+        sqlDB.SetMaxOpenConns(1)
+    }
+    metrics.RegisterDB(sqlDB, conf.DB.Database)
+    return db, nil
+}
+
+func InitAwsS3Client(conf *config.Config) (*s3.Client, error) {
+    creds := credentials.NewStaticCredentialsProvider(conf.AWS.AccessKeyID, conf.AWS.SecretKey, "")
+    cfg, err := awsconfig.LoadDefaultConfig(
+        context.TODO(), 
+        awsconfig.WithCredentialsProvider(creds),
+        awsconfig.WithRegion(conf.AWS.Region),
+    )
+    if err != nil { return nil, err }
+
+    return s3.NewFromConfig(cfg, func(o *s3.Options) {
+        o.APIOptions = append(o.APIOptions, metrics.S3Middleware, tracing.S3Middleware)
+    }), nil
+}
+
+// 설정에 따라 교체되는 로그 파일에 기록하는 Logger를 생성한다. 종료 시 반환된 파일을 닫아야 한다.
+func InitLogger(conf *config.Config) (*slog.Logger, io.Closer, error) {
+    level, err := logging.ParseLevel(conf.Log.Level)
+    if err != nil { return nil, nil, err }
+    if conf.Log.Format != "" && conf.Log.Format != logging.FormatJSON && conf.Log.Format != logging.FormatText {
+        return nil, nil, fmt.Errorf("log.format은 json 또는 text여야 합니다: %q", conf.Log.Format)
+    }
+    file := &logging.RotatingFile{
+        Dir: conf.Log.Path,
+        Prefix: strings.TrimSpace(conf.Log.Prefix),
+        TimeFormat: conf.Log.TimeFormat,
+        MaxSize: int64(withDefault(conf.Log.MaxSize, 100)) << 20,
+        Interval: time.Duration(withDefault(conf.Log.RotateInterval, 24)) * time.Hour,
+        MaxAge: time.Duration(withDefault(conf.Log.MaxAge, 30)) * 24 * time.Hour,
+        MaxBackups: conf.Log.MaxBackups,
+    }
+    if file.Prefix == "" {
+        file.Prefix = "okra_board"
+    }
+    if err := file.Open(); err != nil {
+        return nil, nil, err
+    }
+    return logging.New(file, level, conf.Log.Format), file, nil
+}
+
+// 설정에 따라 span을 내보내는 TracerProvider를 전역으로 등록한다.
+// 종료 시 반환된 함수를 호출하여 남은 span을 내보내야 한다.
+func InitTracer(ctx context.Context, conf *config.Config) (func(context.Context) error, error) {
+    exporterName, err := tracing.ParseExporter(conf.Tracing.Exporter)
+    if err != nil { return nil, err }
+    if exporterName == tracing.ExporterNone {
+        tracing.SetGlobal(nil)
+        return func(context.Context) error { return nil }, nil
+    }
+    exporter, err := tracing.NewExporter(ctx, exporterName, conf.Tracing.Endpoint, conf.Tracing.Insecure, os.Stdout)
+    if err != nil { return nil, err }
+
+    serviceName := conf.Tracing.ServiceName
+    if serviceName == "" {
+        serviceName = "okra_board"
+    }
+    sampleRatio := conf.Tracing.SampleRatio
+    if sampleRatio <= 0 {
+        sampleRatio = 1
+    }
+    provider := tracing.NewProvider(exporter, serviceName, sampleRatio)
+    tracing.SetGlobal(provider)
+    return provider.Shutdown, nil
+}
+
+func withDefault(value, defaultValue int) int {
+    if value <= 0 {
+        return defaultValue
+    }
+    return value
+}
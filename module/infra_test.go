@@ -0,0 +1,40 @@
+package module_test
+
+import (
+	"context"
+	"okra_board2/config"
+	"okra_board2/module"
+	"okra_board2/testutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 로그 수준, 로그 형식, trace exporter는 각 Init 함수에서 검증한다.
+func TestInitInvalidValues(t *testing.T) {
+    conf := testutil.NewConfig()
+    conf.Log.Path = t.TempDir()
+
+    conf.Log.Level = "verbose"
+    _, _, err := module.InitLogger(conf)
+    assert.Error(t, err)
+    conf.Log.Level = ""
+    conf.Log.Format = "xml"
+    _, _, err = module.InitLogger(conf)
+    assert.Error(t, err)
+    conf.Log.Format = ""
+
+    logger, file, err := module.InitLogger(conf)
+    if assert.NoError(t, err) {
+        assert.NotNil(t, logger)
+        file.Close()
+    }
+
+    conf.Log.SQL.Level = "all"
+    _, err = module.InitDBConnection(conf)
+    assert.Error(t, err)
+
+    conf.Tracing = config.TracingConfig{ Exporter: "jaeger" }
+    _, err = module.InitTracer(context.Background(), conf)
+    assert.Error(t, err)
+}
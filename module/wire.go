@@ -23,7 +23,7 @@ func InitAdminController(db *gorm.DB) (c controllers.AdminController) {
     return
 }
 
-func InitAuthController(db *gorm.DB) (a controllers.AuthController) {
+func InitAuthController(db *gorm.DB, conf *config.Config) (a controllers.AuthController) {
     wire.Build(
         repositories.NewAuthRepositoryImpl,
         repositories.NewAdminRepositoryImpl,
@@ -34,15 +34,166 @@ func InitAuthController(db *gorm.DB) (a controllers.AuthController) {
     return
 }
 
-func InitPostController(
+func InitPostService(
     db *gorm.DB, 
     conf *config.Config, 
     client *s3.Client,
-) (c controllers.PostController) {
+    relatedService services.RelatedPostService,
+    sitemapService services.SitemapService,
+) (s services.PostService) {
     wire.Build( 
         repositories.NewPostRepositoryImpl,
+        repositories.NewTagRepositoryImpl,
+        services.NewTagServiceImpl,
         services.NewPostServiceImpl,
+    )
+    return
+}
+
+func InitPostController(
+    postService services.PostService,
+    rankingService services.RankingService,
+    relatedService services.RelatedPostService,
+    reactionService services.ReactionService,
+) (c controllers.PostController) {
+    wire.Build( 
         controllers.NewPostControllerImpl,
     )
     return
 }
+
+func InitRankingService(
+    db *gorm.DB,
+    conf *config.Config,
+) (s services.RankingService) {
+    wire.Build(
+        repositories.NewRankingRepositoryImpl,
+        services.NewRankingServiceImpl,
+    )
+    return
+}
+
+func InitRankingController(
+    rankingService services.RankingService,
+) (c controllers.RankingController) {
+    wire.Build(
+        controllers.NewRankingControllerImpl,
+    )
+    return
+}
+
+func InitRelatedPostService(db *gorm.DB) (s services.RelatedPostService) {
+    wire.Build(
+        repositories.NewPostRepositoryImpl,
+        services.NewRelatedPostServiceImpl,
+    )
+    return
+}
+
+func InitTagController(
+    db *gorm.DB,
+    relatedService services.RelatedPostService,
+) (c controllers.TagController) {
+    wire.Build(
+        repositories.NewTagRepositoryImpl,
+        services.NewTagServiceImpl,
+        controllers.NewTagControllerImpl,
+    )
+    return
+}
+
+func InitFeaturedSlotController(db *gorm.DB) (c controllers.FeaturedSlotController) {
+    wire.Build(
+        repositories.NewFeaturedSlotRepositoryImpl,
+        repositories.NewPostRepositoryImpl,
+        services.NewFeaturedSlotServiceImpl,
+        controllers.NewFeaturedSlotControllerImpl,
+    )
+    return
+}
+
+func InitFeedController(db *gorm.DB, conf *config.Config) (c controllers.FeedController) {
+    wire.Build(
+        repositories.NewPostRepositoryImpl,
+        repositories.NewTagRepositoryImpl,
+        services.NewFeedServiceImpl,
+        controllers.NewFeedControllerImpl,
+    )
+    return
+}
+
+func InitSitemapService(db *gorm.DB, conf *config.Config) (s services.SitemapService) {
+    wire.Build(
+        repositories.NewPostRepositoryImpl,
+        services.NewSitemapServiceImpl,
+    )
+    return
+}
+
+func InitSitemapController(
+    sitemapService services.SitemapService,
+) (c controllers.SitemapController) {
+    wire.Build(
+        controllers.NewSitemapControllerImpl,
+    )
+    return
+}
+
+func InitSeoController(db *gorm.DB, conf *config.Config) (c controllers.SeoController) {
+    wire.Build(
+        repositories.NewPostRepositoryImpl,
+        services.NewSeoServiceImpl,
+        controllers.NewSeoControllerImpl,
+    )
+    return
+}
+
+func InitCommentController(db *gorm.DB, conf *config.Config) (c controllers.CommentController) {
+    wire.Build(
+        repositories.NewCommentRepositoryImpl,
+        repositories.NewPostRepositoryImpl,
+        services.NewCommentServiceImpl,
+        controllers.NewCommentControllerImpl,
+    )
+    return
+}
+
+func InitReactionService(db *gorm.DB, conf *config.Config) (s services.ReactionService) {
+    wire.Build(
+        repositories.NewReactionRepositoryImpl,
+        repositories.NewPostRepositoryImpl,
+        services.NewReactionServiceImpl,
+    )
+    return
+}
+
+func InitReactionController(
+    reactionService services.ReactionService,
+) (c controllers.ReactionController) {
+    wire.Build(
+        controllers.NewReactionControllerImpl,
+    )
+    return
+}
+
+func InitHealthService(
+    db *gorm.DB,
+    conf *config.Config,
+    client *s3.Client,
+    rankingService services.RankingService,
+) (s services.HealthService) {
+    wire.Build(
+        repositories.NewHealthRepositoryImpl,
+        services.NewHealthServiceImpl,
+    )
+    return
+}
+
+func InitHealthController(
+    healthService services.HealthService,
+) (c controllers.HealthController) {
+    wire.Build(
+        controllers.NewHealthControllerImpl,
+    )
+    return
+}
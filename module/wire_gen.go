@@ -24,18 +24,113 @@ func InitAdminController(db *gorm.DB) controllers.AdminController {
 	return adminController
 }
 
-func InitAuthController(db *gorm.DB) controllers.AuthController {
+func InitAuthController(db *gorm.DB, conf *config.Config) controllers.AuthController {
 	authRepository := repositories.NewAuthRepositoryImpl(db)
 	adminRepository := repositories.NewAdminRepositoryImpl(db)
 	adminService := services.NewAdminServiceImpl(adminRepository)
-	authService := services.NewAuthServiceImpl(authRepository, adminService)
+	authService := services.NewAuthServiceImpl(authRepository, adminService, conf)
 	authController := controllers.NewAuthControllerImpl(authService, adminService)
 	return authController
 }
 
-func InitPostController(db *gorm.DB, conf *config.Config, client *s3.Client) controllers.PostController {
+func InitPostService(db *gorm.DB, conf *config.Config, client *s3.Client, relatedService services.RelatedPostService, sitemapService services.SitemapService) services.PostService {
 	postRepository := repositories.NewPostRepositoryImpl(db)
-	postService := services.NewPostServiceImpl(postRepository, conf, client)
-	postController := controllers.NewPostControllerImpl(postService)
+	tagRepository := repositories.NewTagRepositoryImpl(db)
+	tagService := services.NewTagServiceImpl(tagRepository, relatedService)
+	postService := services.NewPostServiceImpl(postRepository, tagService, relatedService, sitemapService, conf, client)
+	return postService
+}
+
+func InitPostController(postService services.PostService, rankingService services.RankingService, relatedService services.RelatedPostService, reactionService services.ReactionService) controllers.PostController {
+	postController := controllers.NewPostControllerImpl(postService, rankingService, relatedService, reactionService)
 	return postController
 }
+
+func InitRankingService(db *gorm.DB, conf *config.Config) services.RankingService {
+	rankingRepository := repositories.NewRankingRepositoryImpl(db)
+	rankingService := services.NewRankingServiceImpl(rankingRepository, conf)
+	return rankingService
+}
+
+func InitRankingController(rankingService services.RankingService) controllers.RankingController {
+	rankingController := controllers.NewRankingControllerImpl(rankingService)
+	return rankingController
+}
+
+func InitRelatedPostService(db *gorm.DB) services.RelatedPostService {
+	postRepository := repositories.NewPostRepositoryImpl(db)
+	relatedPostService := services.NewRelatedPostServiceImpl(postRepository)
+	return relatedPostService
+}
+
+func InitTagController(db *gorm.DB, relatedService services.RelatedPostService) controllers.TagController {
+	tagRepository := repositories.NewTagRepositoryImpl(db)
+	tagService := services.NewTagServiceImpl(tagRepository, relatedService)
+	tagController := controllers.NewTagControllerImpl(tagService)
+	return tagController
+}
+
+func InitFeaturedSlotController(db *gorm.DB) controllers.FeaturedSlotController {
+	featuredSlotRepository := repositories.NewFeaturedSlotRepositoryImpl(db)
+	postRepository := repositories.NewPostRepositoryImpl(db)
+	featuredSlotService := services.NewFeaturedSlotServiceImpl(featuredSlotRepository, postRepository)
+	featuredSlotController := controllers.NewFeaturedSlotControllerImpl(featuredSlotService)
+	return featuredSlotController
+}
+
+func InitFeedController(db *gorm.DB, conf *config.Config) controllers.FeedController {
+	postRepository := repositories.NewPostRepositoryImpl(db)
+	tagRepository := repositories.NewTagRepositoryImpl(db)
+	feedService := services.NewFeedServiceImpl(postRepository, tagRepository, conf)
+	feedController := controllers.NewFeedControllerImpl(feedService, conf)
+	return feedController
+}
+
+func InitSitemapService(db *gorm.DB, conf *config.Config) services.SitemapService {
+	postRepository := repositories.NewPostRepositoryImpl(db)
+	sitemapService := services.NewSitemapServiceImpl(postRepository, conf)
+	return sitemapService
+}
+
+func InitSitemapController(sitemapService services.SitemapService) controllers.SitemapController {
+	sitemapController := controllers.NewSitemapControllerImpl(sitemapService)
+	return sitemapController
+}
+
+func InitSeoController(db *gorm.DB, conf *config.Config) controllers.SeoController {
+	postRepository := repositories.NewPostRepositoryImpl(db)
+	seoService := services.NewSeoServiceImpl(postRepository, conf)
+	seoController := controllers.NewSeoControllerImpl(seoService)
+	return seoController
+}
+
+func InitCommentController(db *gorm.DB, conf *config.Config) controllers.CommentController {
+	commentRepository := repositories.NewCommentRepositoryImpl(db)
+	postRepository := repositories.NewPostRepositoryImpl(db)
+	commentService := services.NewCommentServiceImpl(commentRepository, postRepository, conf)
+	commentController := controllers.NewCommentControllerImpl(commentService)
+	return commentController
+}
+
+func InitReactionService(db *gorm.DB, conf *config.Config) services.ReactionService {
+	reactionRepository := repositories.NewReactionRepositoryImpl(db)
+	postRepository := repositories.NewPostRepositoryImpl(db)
+	reactionService := services.NewReactionServiceImpl(reactionRepository, postRepository, conf)
+	return reactionService
+}
+
+func InitReactionController(reactionService services.ReactionService) controllers.ReactionController {
+	reactionController := controllers.NewReactionControllerImpl(reactionService)
+	return reactionController
+}
+
+func InitHealthService(db *gorm.DB, conf *config.Config, client *s3.Client, rankingService services.RankingService) services.HealthService {
+	healthRepository := repositories.NewHealthRepositoryImpl(db)
+	healthService := services.NewHealthServiceImpl(healthRepository, rankingService, conf, client)
+	return healthService
+}
+
+func InitHealthController(healthService services.HealthService) controllers.HealthController {
+	healthController := controllers.NewHealthControllerImpl(healthService)
+	return healthController
+}
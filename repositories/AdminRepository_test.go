@@ -2,22 +2,15 @@ package repositories_test
 
 import (
 	"github.com/stretchr/testify/assert"
-	"okra_board2/config"
 	"okra_board2/models"
 	"okra_board2/repositories"
+	"okra_board2/testutil"
 	"strconv"
 	"testing"
 )
 
 func TestAdminCRUD(t *testing.T) {
-    conf, err := config.LoadConfigTest()
-    if err != nil { assert.Error(t, err) }
-
-    db, err := config.InitDBConnection(conf)
-    if err != nil { assert.Error(t, err) }
-
-    sqlDB, err := db.DB()
-    defer sqlDB.Close()
+    db := testutil.NewDB(t)
 
     r := repositories.NewAdminRepositoryImpl(db)
 
@@ -41,18 +34,19 @@ func TestAdminCRUD(t *testing.T) {
     }
 
     // update
-    err = r.UpdateAdmin(&models.Admin {
+    err := r.UpdateAdmin(&models.Admin {
         ID: "admin1",
         Password: "updated password",
         Name: "강민석",
         Email: "testemail@gmail.com",
         Phone: "010-2284-3820",
     })
+    assert.NoError(t, err)
 
     // check existence
     checkID := r.CheckAdminExists("id", "admin1")
     checkEmail := r.CheckAdminExists("email", "testemail@gmail.com")
-    checkPhone := r.CheckAdminExists("phone", "010-2274-3820")
+    checkPhone := r.CheckAdminExists("phone", "010-2284-3820")
 
     assert.Equal(t, true, checkID)
     assert.Equal(t, true, checkEmail)
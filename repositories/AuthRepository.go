@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"okra_board2/models"
 	"gorm.io/gorm"
 )
@@ -9,16 +10,16 @@ import (
 type AuthRepository interface {
 
     // Select AdminAuth and returns with error
-    GetAdminAuth(uuid string)                       (auth *models.AdminAuth, err error)
+    GetAdminAuth(ctx context.Context, uuid string) (auth *models.AdminAuth, err error)
 
     // Insert AdminAuth and returns error
-    InsertAdminAuth(adminAuth *models.AdminAuth)    (err error)
+    InsertAdminAuth(ctx context.Context, adminAuth *models.AdminAuth) (err error)
 
     // Delete AdminAuth and returns error
-    DeleteAdminAuth(uuid string)                    (err error)
+    DeleteAdminAuth(ctx context.Context, uuid string) (err error)
 
     // Update AdminAuth(Only Access Token) and returns error
-    UpdateAccessToken(uuid, at string)              (err error)
+    UpdateAccessToken(ctx context.Context, uuid, at string) (err error)
 
 }
 
@@ -31,23 +32,23 @@ func NewAuthRepositoryImpl(db *gorm.DB) AuthRepository {
     return &AuthRepositoryImpl{ db: db }
 }
 
-func (rep *AuthRepositoryImpl) InsertAdminAuth(adminAuth *models.AdminAuth) (err error) {
-    err = rep.db.Create(adminAuth).Error
+func (rep *AuthRepositoryImpl) InsertAdminAuth(ctx context.Context, adminAuth *models.AdminAuth) (err error) {
+    err = rep.db.WithContext(ctx).Create(adminAuth).Error
     return
 }
 
-func (rep *AuthRepositoryImpl) GetAdminAuth(uuid string) (adminAuth *models.AdminAuth, err error) {
-    err = rep.db.First(&adminAuth, "uuid = ?", uuid).Error
+func (rep *AuthRepositoryImpl) GetAdminAuth(ctx context.Context, uuid string) (adminAuth *models.AdminAuth, err error) {
+    err = rep.db.WithContext(ctx).First(&adminAuth, "uuid = ?", uuid).Error
     return
 }
 
-func (rep *AuthRepositoryImpl) DeleteAdminAuth(uuid string) (err error) {
-    err = rep.db.Delete(&models.AdminAuth{}, "uuid = ?", uuid).Error
+func (rep *AuthRepositoryImpl) DeleteAdminAuth(ctx context.Context, uuid string) (err error) {
+    err = rep.db.WithContext(ctx).Delete(&models.AdminAuth{}, "uuid = ?", uuid).Error
     return
 }
 
-func (rep *AuthRepositoryImpl) UpdateAccessToken(uuid, at string) (err error) {
-    err = rep.db.Table("admin_auths").
+func (rep *AuthRepositoryImpl) UpdateAccessToken(ctx context.Context, uuid, at string) (err error) {
+    err = rep.db.WithContext(ctx).Table("admin_auths").
         Where("uuid = ?", uuid).
         Update("access_token", at).
         Error
@@ -1,9 +1,10 @@
 package repositories_test
 
 import (
-	"okra_board2/config"
+	"context"
 	"okra_board2/models"
 	"okra_board2/repositories"
+	"okra_board2/testutil"
 	"strconv"
 	"testing"
 
@@ -11,13 +12,8 @@ import (
 )
 
 func TestAuthCRUD(t *testing.T){ 
-    conf, err := config.LoadConfigTest()
-    if err != nil { assert.Error(t, err) }
-    db, err := config.InitDBConnection(conf)
-    if err != nil { assert.Error(t, err) }
-
-    sqlDB, err := db.DB()
-    defer sqlDB.Close()
+    ctx := context.Background()
+    db := testutil.NewDB(t)
 
     r := repositories.NewAuthRepositoryImpl(db)
     auths := make([]models.AdminAuth, 5)
@@ -33,23 +29,23 @@ func TestAuthCRUD(t *testing.T){
 
     // insert
     for i := 0; i < 5; i++ {
-        if err := r.InsertAdminAuth(&auths[i]); err != nil {
+        if err := r.InsertAdminAuth(ctx, &auths[i]); err != nil {
             assert.Error(t, err)
         }
     }
 
     // update
-    err = r.UpdateAccessToken("uuid1", "updated access token")
+    err := r.UpdateAccessToken(ctx, "uuid1", "updated access token")
     if err != nil { assert.Error(t, err) }
 
     // select
-    auth, err := r.GetAdminAuth("uuid1")
+    auth, err := r.GetAdminAuth(ctx, "uuid1")
     if err != nil { assert.Error(t, err) }
     assert.Equal(t, "updated access token", auth.AccessToken)
 
     // delete
     for i := 0; i < 5; i++ {
-        if err := r.DeleteAdminAuth(auths[i].UUID); err != nil {
+        if err := r.DeleteAdminAuth(ctx, auths[i].UUID); err != nil {
             assert.Error(t, err)
         }
     }
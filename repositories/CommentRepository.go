@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"okra_board2/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CommentRepository interface {
+
+    // 댓글을 불러온다. 댓글이 없을 경우 gorm.ErrRecordNotFound를 반환한다.
+    GetComment(ctx context.Context, commentId int) (comment *models.Comment, err error)
+
+    // 게시물의 댓글과 답글을 작성 순으로 불러온다.
+    // status가 nil이 아닐 경우 해당 상태의 댓글만 불러온다.
+    GetComments(ctx context.Context, postId int, status *string) (comments []models.Comment)
+
+    // 관리자 검토를 위해 댓글을 최신순으로 불러온다.
+    // status가 nil이 아닐 경우 해당 상태의 댓글만 불러오며, 전체 댓글의 개수를 함께 반환한다.
+    GetCommentsByStatus(
+        ctx context.Context,
+        status *string,
+        page, size int,
+    )                                               (comments []models.Comment, count int)
+
+    InsertComment(ctx context.Context, comment *models.Comment) (commentId int, err error)
+
+    // 댓글의 닉네임, 내용, 상태를 변경한다.
+    UpdateComment(ctx context.Context, comment *models.Comment) (err error)
+
+    // 댓글들과 그 답글들을 삭제하고 삭제된 댓글의 수를 반환한다.
+    DeleteComments(ctx context.Context, commentIds []int) (deleted int64, err error)
+
+    // 댓글들의 상태를 한 번에 변경하고 변경된 댓글의 수를 반환한다.
+    UpdateCommentStatus(ctx context.Context, commentIds []int, status string) (updated int64, err error)
+
+    // since 이후에 ip에서 작성된 댓글의 수를 반환한다.
+    CountRecentComments(ctx context.Context, ip string, since time.Time) (count int)
+
+    // 게시판에서 댓글을 사용하는지 확인한다. 설정이 없을 경우 true를 반환한다.
+    IsCommentEnabled(ctx context.Context, boardId int) bool
+
+    // 게시판의 댓글 사용 여부를 설정한다.
+    SetCommentEnabled(ctx context.Context, boardId int, enabled bool) (err error)
+
+}
+
+type CommentRepositoryImpl struct {
+    db *gorm.DB
+}
+
+func NewCommentRepositoryImpl(db *gorm.DB) CommentRepository {
+    return &CommentRepositoryImpl{ db: db }
+}
+
+func (r *CommentRepositoryImpl) GetComment(ctx context.Context, commentId int) (comment *models.Comment, err error) {
+    comment = &models.Comment{}
+    err = r.db.WithContext(ctx).Where("comment_id = ?", commentId).First(comment).Error
+    return
+}
+
+func (r *CommentRepositoryImpl) GetComments(ctx context.Context, postId int, status *string) (comments []models.Comment) {
+    query := r.db.WithContext(ctx).Where("post_id = ?", postId)
+    if status != nil {
+        query = query.Where("status = ?", *status)
+    }
+    query.Order("added_date asc").Order("comment_id asc").Find(&comments)
+    return
+}
+
+func (r *CommentRepositoryImpl) GetCommentsByStatus(
+    ctx context.Context,
+    status *string,
+    page, size int,
+) (comments []models.Comment, count int) {
+    query := r.db.WithContext(ctx).Model(&models.Comment{})
+    if status != nil {
+        query = query.Where("status = ?", *status)
+    }
+    var total int64
+    query.Count(&total)
+    query.Order("added_date desc").
+        Order("comment_id desc").
+        Offset((page - 1) * size).
+        Limit(size).
+        Find(&comments)
+    return comments, int(total)
+}
+
+func (r *CommentRepositoryImpl) InsertComment(ctx context.Context, comment *models.Comment) (commentId int, err error) {
+    err = r.db.WithContext(ctx).Create(comment).Error
+    commentId = comment.CommentID
+    return
+}
+
+func (r *CommentRepositoryImpl) UpdateComment(ctx context.Context, comment *models.Comment) (err error) {
+    now := r.db.NowFunc()
+    comment.UpdatedDate = &now
+    return r.db.WithContext(ctx).Model(&models.Comment{}).
+        Where("comment_id = ?", comment.CommentID).
+        UpdateColumns(map[string]interface{} {
+            "nickname": comment.Nickname,
+            "content": comment.Content,
+            "status": comment.Status,
+            "updated_date": now,
+        }).
+        Error
+}
+
+func (r *CommentRepositoryImpl) DeleteComments(ctx context.Context, commentIds []int) (deleted int64, err error) {
+    result := r.db.WithContext(ctx).
+        Where("comment_id IN ? OR parent_id IN ?", commentIds, commentIds).
+        Delete(&models.Comment{})
+    return result.RowsAffected, result.Error
+}
+
+func (r *CommentRepositoryImpl) UpdateCommentStatus(ctx context.Context, commentIds []int, status string) (updated int64, err error) {
+    result := r.db.WithContext(ctx).Model(&models.Comment{}).
+        Where("comment_id IN ?", commentIds).
+        UpdateColumn("status", status)
+    return result.RowsAffected, result.Error
+}
+
+func (r *CommentRepositoryImpl) CountRecentComments(ctx context.Context, ip string, since time.Time) (count int) {
+    var total int64
+    r.db.WithContext(ctx).Model(&models.Comment{}).
+        Where("ip = ? AND added_date >= ?", ip, since).
+        Count(&total)
+    return int(total)
+}
+
+func (r *CommentRepositoryImpl) IsCommentEnabled(ctx context.Context, boardId int) bool {
+    setting := &models.CommentSetting{}
+    if err := r.db.WithContext(ctx).Where("board_id = ?", boardId).First(setting).Error; err != nil {
+        return true
+    }
+    return setting.Enabled
+}
+
+func (r *CommentRepositoryImpl) SetCommentEnabled(ctx context.Context, boardId int, enabled bool) (err error) {
+    return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+        UpdateAll: true,
+    }).Create(&models.CommentSetting{ BoardID: boardId, Enabled: enabled }).Error
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"okra_board2/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 기존 selected 게시물(GetSelectedThumbnails)이 사용하는 슬롯 그룹
+const MainFeaturedGroup = "main"
+
+type FeaturedSlotRepository interface {
+
+    // 그룹의 모든 슬롯을 position 순으로 불러온다.
+    GetSlots(ctx context.Context, group string) (slots []models.FeaturedSlot)
+
+    // now 시점에 노출 중인 슬롯을 position 순으로 불러온다.
+    // 공개된 게시물의 슬롯만을 검색하며,
+    // 슬롯의 제목이 지정되지 않은 경우 게시물의 제목을 사용한다.
+    GetActiveSlots(ctx context.Context, group string, now time.Time) (posts []models.FeaturedPost)
+
+    // 그룹의 슬롯을 주어진 슬롯 목록으로 교체한다.
+    // 각 슬롯의 position은 목록에서의 순서로 설정된다.
+    ReplaceSlots(ctx context.Context, group string, slots []models.FeaturedSlot) (err error)
+
+    // 그룹 내 슬롯의 position을 slotIds의 순서대로 변경한다.
+    ReorderSlots(ctx context.Context, group string, slotIds []int) (err error)
+
+}
+
+type FeaturedSlotRepositoryImpl struct {
+    db *gorm.DB
+}
+
+func NewFeaturedSlotRepositoryImpl(db *gorm.DB) FeaturedSlotRepository {
+    return &FeaturedSlotRepositoryImpl{ db: db }
+}
+
+func (r *FeaturedSlotRepositoryImpl) GetSlots(ctx context.Context, group string) (slots []models.FeaturedSlot) {
+    r.db.WithContext(ctx).Where("group_name = ?", group).
+        Order("position asc").
+        Find(&slots)
+    return
+}
+
+func (r *FeaturedSlotRepositoryImpl) GetActiveSlots(ctx context.Context, group string, now time.Time) (posts []models.FeaturedPost) {
+    activeFeaturedSlots(r.db.WithContext(ctx).Table("featured_slots"), group, now).
+        Select(`featured_slots.slot_id, featured_slots.position, featured_slots.image,
+            posts.post_id, posts.board_id, posts.slug, posts.thumbnail,
+            COALESCE(featured_slots.title, posts.title) as title`).
+        Find(&posts)
+    return
+}
+
+// now 시점에 노출 중인 슬롯을 게시물과 함께 검색하는 쿼리
+func activeFeaturedSlots(query *gorm.DB, group string, now time.Time) *gorm.DB {
+    return query.
+        Joins("INNER JOIN posts on posts.post_id = featured_slots.post_id").
+        Where("featured_slots.group_name = ?", group).
+        Where("posts.status = ?", true).
+        Where("featured_slots.start_date IS NULL OR featured_slots.start_date <= ?", now).
+        Where("featured_slots.end_date IS NULL OR featured_slots.end_date > ?", now).
+        Order("featured_slots.position asc")
+}
+
+func (r *FeaturedSlotRepositoryImpl) ReplaceSlots(ctx context.Context, group string, slots []models.FeaturedSlot) (err error) {
+    return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        return replaceFeaturedSlots(tx, group, slots)
+    })
+}
+
+func replaceFeaturedSlots(tx *gorm.DB, group string, slots []models.FeaturedSlot) error {
+    if err := tx.Delete(&models.FeaturedSlot{}, "group_name = ?", group).Error; err != nil {
+        return err
+    }
+    if len(slots) == 0 {
+        return nil
+    }
+    for i := range slots {
+        slots[i].GroupName = group
+        slots[i].Position = i
+    }
+    return tx.Create(&slots).Error
+}
+
+func (r *FeaturedSlotRepositoryImpl) ReorderSlots(ctx context.Context, group string, slotIds []int) (err error) {
+    return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        for position, slotId := range slotIds {
+            err := tx.Model(&models.FeaturedSlot{}).
+                Where("slot_id = ? AND group_name = ?", slotId, group).
+                UpdateColumn("position", position).
+                Error
+            if err != nil { return err }
+        }
+        return nil
+    })
+}
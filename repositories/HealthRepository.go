@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type HealthRepository interface {
+
+    // db에 연결할 수 있는지 확인한다.
+    Ping(ctx context.Context)       (err error)
+
+}
+
+type HealthRepositoryImpl struct {
+    db *gorm.DB
+}
+
+func NewHealthRepositoryImpl(db *gorm.DB) HealthRepository {
+    return &HealthRepositoryImpl{ db: db }
+}
+
+func (r *HealthRepositoryImpl) Ping(ctx context.Context) error {
+    sqlDB, err := r.db.DB()
+    if err != nil { return err }
+    return sqlDB.PingContext(ctx)
+}
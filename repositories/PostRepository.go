@@ -1,9 +1,12 @@
 package repositories
 
 import (
+	"context"
 	"okra_board2/models"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PostRepository interface {
@@ -13,36 +16,103 @@ type PostRepository interface {
     // status != nil => 지정된 status의 게시물중에서 검색한다. 
     // 조건에 부합하는 게시글을 찾지 못할 경우 err 반환.
     GetPost(
+        ctx context.Context,
         status *bool,
         postId int,
     )                               (post *models.Post, err error)
 
-    // 이전 게시물의 post_id와 title 정보를 검색한다.
+    // 게시판 내에서 slug에 해당하는 게시물을 불러온다.
     // status == nil => 게시물의 status를 구분하지 않고 검색한다.
     // status != nil => 지정된 status의 게시물중에서 검색한다. 
     // 조건에 부합하는 게시글을 찾지 못할 경우 err 반환.
-    GetPrevPostInfo(
+    GetPostBySlug(
+        ctx context.Context,
         status *bool,
+        boardId int,
+        slug string,
+    )                               (post *models.Post, err error)
+
+    // 게시판 내에서 변경되기 전의 slug 기록을 불러온다.
+    // 기록이 존재하지 않을 경우 err 반환.
+    GetSlugHistory(
+        ctx context.Context,
+        boardId int,
+        slug string,
+    )                               (history *models.PostSlug, err error)
+
+    // 게시판 내에서 postId가 아닌 다른 게시물이 
+    // 해당 slug를 현재 사용하거나 이전에 사용했는지, 또는 번역의 slug로 사용하는지 확인한다.
+    CheckSlugExists(
+        ctx context.Context,
+        boardId int,
+        slug string,
         postId int,
-    )                               (prevPost *models.PostE, err error)
+    )                               (exists bool)
+
+    // 게시물들의 번역을 불러온다. locale이 nil이 아닐 경우 해당 언어의 번역만 불러온다.
+    GetTranslations(
+        ctx context.Context,
+        postIds []int,
+        locale *string,
+    )                               (translations []models.PostTranslation)
+
+    // 게시판 내에서 번역의 slug에 해당하는 게시글과 번역의 언어를 불러온다.
+    // 게시글이 존재하지 않을 경우 gorm.ErrRecordNotFound를 반환한다.
+    GetPostByTranslationSlug(
+        ctx context.Context,
+        status *bool,
+        boardId int,
+        slug string,
+    )                               (post *models.Post, locale string, err error)
+
+    // 번역을 저장한다. 이미 같은 언어의 번역이 있을 경우 교체한다.
+    SaveTranslation(ctx context.Context, translation *models.PostTranslation) (err error)
+
+    // 번역을 삭제한다. 번역이 존재하지 않을 경우 gorm.ErrRecordNotFound를 반환한다.
+    DeleteTranslation(ctx context.Context, postId int, locale string) (err error)
 
-    // 다음 게시물의 post_id와 title 정보를 검색한다.
+    // locale의 번역이 없는 게시물들의 post_id, board_id, slug, title, status 정보를 최신순으로 불러온다.
+    GetPostsMissingTranslation(
+        ctx context.Context,
+        locale string,
+        page, size int,
+    )                               (posts []models.Post, count int)
+
+    // slug가 비어있는 게시물들의 post_id, board_id, title 정보를 불러온다.
+    GetPostsWithoutSlug(ctx context.Context) (posts []models.Post)
+
+    // 게시물의 slug를 변경한다. 이전 slug는 기록되지 않는다.
+    UpdateSlug(ctx context.Context, postId int, slug string) (err error)
+
+    // 요약과 읽기 시간이 계산되지 않은 게시물들의 post_id, content를 반환한다.
+    GetPostsWithoutExcerpt(ctx context.Context) (posts []models.Post)
+
+    // 게시물의 요약과 읽기 시간을 변경한다.
+    UpdateExcerpt(ctx context.Context, postId int, excerpt string, readingTime int) (err error)
+
+    // 같은 게시판에서 post의 이전, 다음 게시물의 post_id와 title 정보를 한 번의 쿼리로 검색한다.
+    // 게시 일자(게시되지 않은 게시물은 작성 일자) 순으로 정렬하며, 게시 일자가 같을 경우 post_id 순으로 정렬한다.
     // status == nil => 게시물의 status를 구분하지 않고 검색한다.
     // status != nil => 지정된 status의 게시물중에서 검색한다. 
-    // 조건에 부합하는 게시글을 찾지 못할 경우 err 반환.
-    GetNextPostInfo(
+    // titleKeyword, tagKeyword가 nil이 아닐 경우 GetPosts와 같은 조건의 게시물 중에서 검색한다.
+    // 이전 또는 다음 게시물이 존재하지 않을 경우 nil을 반환한다.
+    GetAdjacentPosts(
+        ctx context.Context,
         status *bool,
-        postId int,
-    )                               (nextPost *models.PostE, err error)
+        post *models.Post,
+        titleKeyword *string,
+        tagKeyword *string,
+    )                               (prevPost, nextPost *models.PostE, err error)
 
     // Insert Post and returns error
-    InsertPost(post *models.Post)   (postId int, err error)
+    InsertPost(ctx context.Context, post *models.Post) (postId int, err error)
 
     // Update Post and returns error
-    UpdatePost(post *models.Post)   (err error)
+    // slug가 변경될 경우 이전 slug를 post_slugs 테이블에 기록한다.
+    UpdatePost(ctx context.Context, post *models.Post) (err error)
 
     // Delete Post and returns error
-    DeletePost(postId int)          (err error)
+    DeletePost(ctx context.Context, postId int) (err error)
 
     // Select posts with pagination, order and optional condition
     // enabled: if true, returns posts which status is true.
@@ -51,6 +121,7 @@ type PostRepository interface {
     // keyword: optional. if nil, select all title posts.
     // orderBy: order.
     GetPosts(
+        ctx context.Context,
         enabled bool,
         selected *bool,
         page, size int,
@@ -61,16 +132,30 @@ type PostRepository interface {
     )                               (posts []models.Post, count int)
 
     // posts 테이블의 모든 게시글 정보를 불러온다.
-    GetAllPosts()                   (posts []models.PostE)
+    GetAllPosts(ctx context.Context) (posts []models.PostE)
+
+    // 공개된 모든 게시글을 내용 및 태그와 함께 불러온다.
+    GetAllPublishedPosts(ctx context.Context) (posts []models.Post)
+
+    // 최근 게시된 순으로 공개된 게시글을 내용 및 태그와 함께 최대 size개 불러온다.
+    // boardId: optional. if nil, select from all boards.
+    // tagId: optional. if nil, select posts regardless of tags.
+    GetRecentPublishedPosts(
+        ctx context.Context,
+        boardId *int,
+        tagId *int,
+        size int,
+    )                               (posts []models.Post)
 
     // 홈페이지의 메인 화면에 썸네일을 출력 할 게시물들을 재설정한다.
-    ResetSelectedPost(ids *[]int)   (err error)
+    // main 슬롯 그룹도 ids의 순서대로 함께 재설정되며, 편집자가 지정한 슬롯의 노출 기간과 제목, 이미지는 유지된다.
+    ResetSelectedPost(ctx context.Context, ids *[]int) (err error)
 
-    // Select selected thumbnails.
-    GetSelectedThumbnails()         (thumbanils []models.Thumbnail)
+    // main 슬롯 그룹에서 현재 노출 중인 게시물의 썸네일을 슬롯 순서대로 불러온다.
+    GetSelectedThumbnails(ctx context.Context) (thumbanils []models.Thumbnail)
 
     // 게시물이 존재하는지 확인한다.
-    CheckPostExists(postId int)     (exists bool)
+    CheckPostExists(ctx context.Context, postId int) (exists bool)
 
 }
 
@@ -79,13 +164,16 @@ type PostRepositoryImpl struct {
 }
 
 func NewPostRepositoryImpl(db *gorm.DB) PostRepository {
+    // Post.PostID는 쓰기가 금지되어 있어 gorm이 생성하는 연결 테이블에서도 post_id가 누락되므로
+    // 쓰기가 가능한 PostTag를 연결 테이블로 지정한다.
+    db.SetupJoinTable(&models.Post{}, "Tags", &models.PostTag{})
     return &PostRepositoryImpl{ db: db }
 }
 
-func (r *PostRepositoryImpl) GetPost(status *bool, postId int) (post *models.Post, err error) {
+func (r *PostRepositoryImpl) GetPost(ctx context.Context, status *bool, postId int) (post *models.Post, err error) {
     post = &models.Post{}
-    query := r.db.Model(&models.Post{}).Preload("Tags", func(db *gorm.DB) *gorm.DB {
-        return db.Order("post_tags.name ASC")
+    query := r.db.WithContext(ctx).Model(&models.Post{}).Preload("Tags", func(db *gorm.DB) *gorm.DB {
+        return db.Order("tags.name ASC")
     })
     if status != nil {
         query = query.Where("status = ?", *status)
@@ -94,60 +182,303 @@ func (r *PostRepositoryImpl) GetPost(status *bool, postId int) (post *models.Pos
     return
 }
 
-func (r *PostRepositoryImpl) GetPrevPostInfo(
+func (r *PostRepositoryImpl) GetPostBySlug(
+    ctx context.Context,
     status *bool,
-    postId int,
-) (prevPost *models.PostE, err error) {
-    prevPost = &models.PostE{}
-    query := r.db.Model(&models.Post{})    
+    boardId int,
+    slug string,
+) (post *models.Post, err error) {
+    post = &models.Post{}
+    query := r.db.WithContext(ctx).Model(&models.Post{}).Preload("Tags", func(db *gorm.DB) *gorm.DB {
+        return db.Order("tags.name ASC")
+    })
     if status != nil {
         query = query.Where("status = ?", *status)
     }
-    err = query.Where("post_id < ?", postId).Order("post_id desc").First(prevPost).Error
+    err = query.Where("board_id = ? AND slug = ?", boardId, slug).First(post).Error
+    return
+}
+
+func (r *PostRepositoryImpl) GetSlugHistory(ctx context.Context, boardId int, slug string) (history *models.PostSlug, err error) {
+    history = &models.PostSlug{}
+    err = r.db.WithContext(ctx).First(history, "board_id = ? AND slug = ?", boardId, slug).Error
+    return
+}
+
+func (r *PostRepositoryImpl) CheckSlugExists(ctx context.Context, boardId int, slug string, postId int) (exists bool) {
+    r.db.WithContext(ctx).Raw(
+        "SELECT (?) + (?) + (?) > 0",
+        r.db.WithContext(ctx).Model(&models.Post{}).
+            Select("count(*)").
+            Where("board_id = ? AND slug = ? AND post_id <> ?", boardId, slug, postId),
+        r.db.WithContext(ctx).Model(&models.PostSlug{}).
+            Select("count(*)").
+            Where("board_id = ? AND slug = ? AND post_id <> ?", boardId, slug, postId),
+        r.db.WithContext(ctx).Model(&models.PostTranslation{}).
+            Select("count(*)").
+            Joins("INNER JOIN posts on posts.post_id = post_translations.post_id").
+            Where("posts.board_id = ? AND post_translations.slug = ? AND post_translations.post_id <> ?", boardId, slug, postId),
+    ).Scan(&exists)
+    return
+}
+
+func (r *PostRepositoryImpl) GetTranslations(ctx context.Context, postIds []int, locale *string) (translations []models.PostTranslation) {
+    if len(postIds) == 0 { return }
+    query := r.db.WithContext(ctx).Where("post_id IN ?", postIds)
+    if locale != nil {
+        query = query.Where("locale = ?", *locale)
+    }
+    query.Order("post_id asc").Order("locale asc").Find(&translations)
     return
 }
 
-func (r *PostRepositoryImpl) GetNextPostInfo(
+func (r *PostRepositoryImpl) GetPostByTranslationSlug(
+    ctx context.Context,
     status *bool,
-    postId int,
-) (nextPost *models.PostE, err error) {
-    nextPost = &models.PostE{}
-    query := r.db.Model(&models.Post{}).Select("post_id, title")
-    if status != nil {
-        query = query.Where("status = ?", *status)
+    boardId int,
+    slug string,
+) (post *models.Post, locale string, err error) {
+    translation := &models.PostTranslation{}
+    err = r.db.WithContext(ctx).Model(&models.PostTranslation{}).
+        Select("post_translations.post_id, post_translations.locale").
+        Joins("INNER JOIN posts on posts.post_id = post_translations.post_id").
+        Where("posts.board_id = ? AND post_translations.slug = ?", boardId, slug).
+        First(translation).
+        Error
+    if err != nil { return }
+    post, err = r.GetPost(ctx, status, translation.PostID)
+    return post, translation.Locale, err
+}
+
+func (r *PostRepositoryImpl) SaveTranslation(ctx context.Context, translation *models.PostTranslation) (err error) {
+    now := r.db.NowFunc()
+    translation.UpdatedDate = &now
+    return r.db.WithContext(ctx).Clauses(clause.OnConflict{ UpdateAll: true }).Create(translation).Error
+}
+
+func (r *PostRepositoryImpl) DeleteTranslation(ctx context.Context, postId int, locale string) (err error) {
+    result := r.db.WithContext(ctx).Delete(&models.PostTranslation{}, "post_id = ? AND locale = ?", postId, locale)
+    if result.Error == nil && result.RowsAffected == 0 {
+        return gorm.ErrRecordNotFound
     }
-    err = query.Where("post_id > ?", postId).Order("post_id asc").First(nextPost).Error
+    return result.Error
+}
+
+func (r *PostRepositoryImpl) GetPostsMissingTranslation(
+    ctx context.Context,
+    locale string,
+    page, size int,
+) (posts []models.Post, count int) {
+    query := r.db.WithContext(ctx).Model(&models.Post{}).
+        Where("NOT EXISTS (?)", r.db.WithContext(ctx).Model(&models.PostTranslation{}).
+            Select("1").
+            Where("post_translations.post_id = posts.post_id AND post_translations.locale = ?", locale),
+        )
+    var total int64
+    query.Count(&total)
+    query.Select("post_id, board_id, slug, title, status").
+        Order(postPublishedDate + " desc").
+        Order("post_id desc").
+        Offset((page - 1) * size).
+        Limit(size).
+        Find(&posts)
+    return posts, int(total)
+}
+
+func (r *PostRepositoryImpl) GetPostsWithoutSlug(ctx context.Context) (posts []models.Post) {
+    r.db.WithContext(ctx).Model(&models.Post{}).
+        Select("post_id, board_id, title").
+        Where("slug IS NULL OR slug = ?", "").
+        Find(&posts)
     return
 }
 
-func (r *PostRepositoryImpl) InsertPost(post *models.Post) (postId int, err error) {
-    err = r.db.Create(post).Error
-    postId = post.PostID
+func (r *PostRepositoryImpl) UpdateSlug(ctx context.Context, postId int, slug string) (err error) {
+    return r.db.WithContext(ctx).Model(&models.Post{}).
+        Where("post_id = ?", postId).
+        UpdateColumn("slug", slug).
+        Error
+}
+
+func (r *PostRepositoryImpl) GetPostsWithoutExcerpt(ctx context.Context) (posts []models.Post) {
+    r.db.WithContext(ctx).Model(&models.Post{}).
+        Select("post_id, content").
+        Where("reading_time IS NULL OR reading_time = ?", 0).
+        Find(&posts)
     return
 }
 
-func (r *PostRepositoryImpl) UpdatePost(post *models.Post) (err error) {
-    return r.db.Transaction(func(tx *gorm.DB) error {
-        if err := r.db.Delete(&models.PostTag{}, "post_id = ?", post.PostID).Error; err != nil {
-            return err
+func (r *PostRepositoryImpl) UpdateExcerpt(ctx context.Context, postId int, excerpt string, readingTime int) (err error) {
+    return r.db.WithContext(ctx).Model(&models.Post{}).
+        Where("post_id = ?", postId).
+        UpdateColumns(map[string]interface{} {
+            "excerpt": excerpt,
+            "reading_time": readingTime,
+        }).
+        Error
+}
+
+// 게시물의 정렬 기준이 되는 게시 일자
+const postPublishedDate = "COALESCE(posts.published_date, posts.added_date)"
+
+func (r *PostRepositoryImpl) GetAdjacentPosts(
+    ctx context.Context,
+    status *bool,
+    post *models.Post,
+    titleKeyword *string,
+    tagKeyword *string,
+) (prevPost, nextPost *models.PostE, err error) {
+    publishedDate := post.AddedDate
+    if post.PublishedDate != nil {
+        publishedDate = *post.PublishedDate
+    }
+
+    query := func(direction, operator, order string) *gorm.DB {
+        query := r.db.WithContext(ctx).Model(&models.Post{}).
+            Select("'" + direction + "' as direction, posts.post_id, posts.title, posts.slug").
+            Where("posts.board_id = ?", post.BoardID)
+        if status != nil {
+            query = query.Where("posts.status = ?", *status)
+        }
+        if titleKeyword != nil {
+            query = query.Where(likeIgnoreCase("posts.title"), containsPattern(*titleKeyword))
+        }
+        if tagKeyword != nil {
+            query = query.Where("posts.post_id IN (?)", r.db.WithContext(ctx).Table("post_tags").
+                Select("post_tags.post_id").
+                Joins("INNER JOIN tags on tags.tag_id = post_tags.tag_id").
+                Where(likeIgnoreCase("tags.name"), containsPattern(*tagKeyword)))
         }
-        if len(post.Tags) > 0  {
-            if err := r.db.Create(post.Tags).Error; err != nil {
+        return query.
+            Where(
+                "(" + postPublishedDate + " " + operator + " ? OR (" + postPublishedDate + " = ? AND posts.post_id " + operator + " ?))",
+                publishedDate, publishedDate, post.PostID,
+            ).
+            Order(postPublishedDate + " " + order).
+            Order("posts.post_id " + order).
+            Limit(1)
+    }
+
+    var rows []struct {
+        Direction   string
+        models.PostE
+    }
+    err = r.db.WithContext(ctx).Raw(
+        "SELECT * FROM (?) AS prev_post UNION ALL SELECT * FROM (?) AS next_post",
+        query("prev", "<", "desc"),
+        query("next", ">", "asc"),
+    ).Scan(&rows).Error
+    if err != nil { return }
+
+    for i := range rows {
+        if rows[i].Direction == "prev" {
+            prevPost = &rows[i].PostE
+        } else {
+            nextPost = &rows[i].PostE
+        }
+    }
+    return
+}
+
+func (r *PostRepositoryImpl) InsertPost(ctx context.Context, post *models.Post) (postId int, err error) {
+    now := r.db.NowFunc()
+    if post.Status && post.PublishedDate == nil {
+        post.PublishedDate = &now
+    }
+    post.UpdatedDate = &now
+    err = r.db.WithContext(ctx).Create(post).Error
+    postId = post.PostID
+    return
+}
+
+func (r *PostRepositoryImpl) UpdatePost(ctx context.Context, post *models.Post) (err error) {
+    now := r.db.NowFunc()
+    post.UpdatedDate = &now
+    return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        if post.Slug != "" {
+            if err := r.recordSlugHistory(tx, post, now); err != nil {
                 return err
             }
         }
-        if err := r.db.UpdateColumns(post).Error; err != nil {
+        if err := tx.Model(post).Association("Tags").Replace(post.Tags); err != nil {
+            return err
+        }
+        if err := tx.Omit("Tags").UpdateColumns(post).Error; err != nil {
             return err
         }
+        // 처음 게시되는 경우에만 게시 일자를 기록한다.
+        if post.Status {
+            return tx.Model(&models.Post{}).
+                Where("post_id = ? AND published_date IS NULL", post.PostID).
+                UpdateColumn("published_date", now).
+                Error
+        }
         return nil
     })
 }
 
-func (r *PostRepositoryImpl) DeletePost(postId int) (err error) {
-    return r.db.Delete(&models.Post{}, "post_id = ?", postId).Error
+// 게시물의 slug 또는 게시판이 변경될 경우 이전 slug를 기록한다.
+// 새로운 slug가 이전에 다른 게시물의 slug였을 경우 해당 기록은 삭제된다.
+func (r *PostRepositoryImpl) recordSlugHistory(tx *gorm.DB, post *models.Post, now time.Time) error {
+    prev := &models.Post{}
+    err := tx.Model(&models.Post{}).
+        Select("board_id, slug").
+        Where("post_id = ?", post.PostID).
+        First(prev).
+        Error
+    if err != nil { return err }
+
+    boardId := post.BoardID
+    if boardId == 0 { boardId = prev.BoardID }
+    if prev.Slug == post.Slug && prev.BoardID == boardId {
+        return nil
+    }
+
+    err = tx.Delete(&models.PostSlug{}, "board_id = ? AND slug = ?", boardId, post.Slug).Error
+    if err != nil { return err }
+    if prev.Slug == "" {
+        return nil
+    }
+    return tx.Clauses(clause.OnConflict{
+        Columns:    []clause.Column{{ Name: "board_id" }, { Name: "slug" }},
+        DoUpdates:  clause.AssignmentColumns([]string{ "post_id", "added_date" }),
+    }).Create(&models.PostSlug{
+        BoardID: prev.BoardID,
+        Slug: prev.Slug,
+        PostID: post.PostID,
+        AddedDate: now,
+    }).Error
+}
+
+func (r *PostRepositoryImpl) DeletePost(ctx context.Context, postId int) (err error) {
+    return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        if err := tx.Delete(&models.PostTag{}, "post_id = ?", postId).Error; err != nil {
+            return err
+        }
+        if err := tx.Delete(&models.PostSlug{}, "post_id = ?", postId).Error; err != nil {
+            return err
+        }
+        if err := tx.Delete(&models.Comment{}, "post_id = ?", postId).Error; err != nil {
+            return err
+        }
+        if err := tx.Delete(&models.Reaction{}, "post_id = ?", postId).Error; err != nil {
+            return err
+        }
+        if err := tx.Delete(&models.PostTranslation{}, "post_id = ?", postId).Error; err != nil {
+            return err
+        }
+        if err := tx.Delete(&models.FeaturedSlot{}, "post_id = ?", postId).Error; err != nil {
+            return err
+        }
+        if err := tx.Delete(&models.PostView{}, "post_id = ?", postId).Error; err != nil {
+            return err
+        }
+        return tx.Delete(&models.Post{}, "post_id = ?", postId).Error
+    })
 }
 
 func (r *PostRepositoryImpl) GetPosts(
+    ctx context.Context,
     enabled bool,
     selected *bool,
     page, size int,
@@ -156,9 +487,9 @@ func (r *PostRepositoryImpl) GetPosts(
     tagKeyword *string,
     orderBy ... string,
 ) (posts[]models.Post, count int) {
-    query := r.db.Model(&models.Post{}).Preload("Tags", func(db *gorm.DB) *gorm.DB {
-        return db.Order("post_tags.name ASC")
-    }).Omit("Content")
+    query := r.db.WithContext(ctx).Model(&models.Post{}).Preload("Tags", func(db *gorm.DB) *gorm.DB {
+        return db.Order("tags.name ASC")
+    }).Omit("Content", "Source")
     if enabled { 
         query = query.Where("status = ?", true) 
     }
@@ -169,14 +500,15 @@ func (r *PostRepositoryImpl) GetPosts(
         query = query.Where("board_id = ?", boardId) 
     }
     if titleKeyword != nil { 
-        query = query.Where("title like ?", "%"+*titleKeyword+"%") 
+        query = query.Where(likeIgnoreCase("title"), containsPattern(*titleKeyword))
     }
     if tagKeyword != nil {
-        query = query.Joins("INNER JOIN post_tags on post_tags.post_id = posts.post_id").
-            Group("posts.post_id").
-            Where("post_tags.name like ?", "%"+*tagKeyword+"%")
+        query = query.Where("posts.post_id IN (?)", r.db.WithContext(ctx).Table("post_tags").
+            Select("post_tags.post_id").
+            Joins("INNER JOIN tags on tags.tag_id = post_tags.tag_id").
+            Where(likeIgnoreCase("tags.name"), containsPattern(*tagKeyword)))
     }
-    r.db.Table("(?) as a", query).Select("count(*)").Find(&count)
+    r.db.WithContext(ctx).Table("(?) as a", query).Select("count(*)").Find(&count)
     for _, order := range orderBy {
         query = query.Order(order)
     }
@@ -184,28 +516,98 @@ func (r *PostRepositoryImpl) GetPosts(
     return
 }
 
-func (r *PostRepositoryImpl) GetAllPosts() (posts []models.PostE){
-    r.db.Model(&models.Post{}).Find(&posts)
+func (r *PostRepositoryImpl) GetAllPosts(ctx context.Context) (posts []models.PostE){
+    r.db.WithContext(ctx).Model(&models.Post{}).Find(&posts)
+    return
+}
+
+func (r *PostRepositoryImpl) GetAllPublishedPosts(ctx context.Context) (posts []models.Post) {
+    r.db.WithContext(ctx).Model(&models.Post{}).
+        Preload("Tags").
+        Where("status = ?", true).
+        Find(&posts)
+    return
+}
+
+func (r *PostRepositoryImpl) GetRecentPublishedPosts(
+    ctx context.Context,
+    boardId *int,
+    tagId *int,
+    size int,
+) (posts []models.Post) {
+    query := r.db.WithContext(ctx).Model(&models.Post{}).Preload("Tags", func(db *gorm.DB) *gorm.DB {
+        return db.Order("tags.name ASC")
+    }).Where("posts.status = ?", true)
+    if boardId != nil {
+        query = query.Where("posts.board_id = ?", *boardId)
+    }
+    if tagId != nil {
+        query = query.Where("posts.post_id IN (?)", r.db.WithContext(ctx).Table("post_tags").
+            Select("post_id").
+            Where("tag_id = ?", *tagId))
+    }
+    query.Order(postPublishedDate + " desc").
+        Order("posts.post_id desc").
+        Limit(size).
+        Find(&posts)
     return
 }
 
-func (r *PostRepositoryImpl) ResetSelectedPost(ids *[]int) (err error) {
-    return r.db.Transaction(func(tx *gorm.DB) (err error) {
+func (r *PostRepositoryImpl) ResetSelectedPost(ctx context.Context, ids *[]int) (err error) {
+    return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
         err = tx.Model(&models.Post{}).Where("selected = ?", true).Update("selected", false).Error
         if err != nil { return }
         err = tx.Model(&models.Post{}).Where(ids).Update("selected", true).Error
         if err != nil { return }
-        return nil
+
+        var existing []models.FeaturedSlot
+        err = tx.Where("group_name = ?", MainFeaturedGroup).Order("position asc").Find(&existing).Error
+        if err != nil { return }
+        return replaceFeaturedSlots(tx, MainFeaturedGroup, selectedFeaturedSlots(existing, *ids))
     })
 }
 
-func (r *PostRepositoryImpl) GetSelectedThumbnails() (thumbnails []models.Thumbnail) {
-    r.db.Table("posts").Where("selected = ? ", true).Order("post_id desc").Find(&thumbnails)
+// ids의 순서대로 main 그룹의 슬롯 목록을 만든다.
+// 이미 슬롯이 있는 게시물은 기존 슬롯의 노출 기간과 제목, 이미지를 유지하며,
+// ids에 없는 게시물의 슬롯 중 노출 기간이나 제목, 이미지가 지정된 슬롯은 기존 순서대로 뒤에 유지된다.
+func selectedFeaturedSlots(existing []models.FeaturedSlot, ids []int) []models.FeaturedSlot {
+    selected := make(map[int]bool, len(ids))
+    for _, id := range ids {
+        selected[id] = true
+    }
+    reused := make(map[int]models.FeaturedSlot)
+    kept := []models.FeaturedSlot{}
+    for _, slot := range existing {
+        slot.SlotID = 0
+        if _, ok := reused[slot.PostID]; selected[slot.PostID] && !ok {
+            reused[slot.PostID] = slot
+            continue
+        }
+        if slot.StartDate != nil || slot.EndDate != nil || slot.Title != nil || slot.Image != nil {
+            kept = append(kept, slot)
+        }
+    }
+
+    slots := make([]models.FeaturedSlot, 0, len(ids) + len(kept))
+    for _, id := range ids {
+        slot, ok := reused[id]
+        if !ok {
+            slot = models.FeaturedSlot{ PostID: id }
+        }
+        slots = append(slots, slot)
+    }
+    return append(slots, kept...)
+}
+
+func (r *PostRepositoryImpl) GetSelectedThumbnails(ctx context.Context) (thumbnails []models.Thumbnail) {
+    activeFeaturedSlots(r.db.WithContext(ctx).Table("featured_slots"), MainFeaturedGroup, r.db.NowFunc()).
+        Select("posts.post_id, posts.slug, posts.thumbnail, COALESCE(featured_slots.title, posts.title) as title").
+        Find(&thumbnails)
     return
 }
 
-func (r *PostRepositoryImpl) CheckPostExists(postId int) (exists bool) {
-    r.db.Model(&models.Post{}).
+func (r *PostRepositoryImpl) CheckPostExists(ctx context.Context, postId int) (exists bool) {
+    r.db.WithContext(ctx).Model(&models.Post{}).
         Select("count(*) > 0").
         Where("post_id = ?", postId).
         Find(&exists)
@@ -1,28 +1,32 @@
 package repositories_test
 
 import (
+	"context"
 	"errors"
-	"okra_board2/config"
 	"okra_board2/models"
 	"okra_board2/repositories"
+	"okra_board2/testutil"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 )
 
 func TestPostCRUD(t *testing.T) {
+    ctx := context.Background()
 
-    conf, err := config.LoadConfigTest()
-    if err != nil { assert.Error(t, err) }
-
-    db, err := config.InitDBConnection(conf)
-    if err != nil { assert.Error(t, err) }
+    db := testutil.NewDB(t)
     r := repositories.NewPostRepositoryImpl(db)
+    tagRepo := repositories.NewTagRepositoryImpl(db)
 
-    sqlDB, err := db.DB()
-    defer sqlDB.Close()
+    tags, err := tagRepo.FindOrCreateTags(context.Background(), []models.Tag {
+        { Name: "Tag test 1", Slug: "tag-test-1" },
+        { Name: "Tag test 2", Slug: "tag-test-2" },
+        { Name: "Tag test 3", Slug: "tag-test-3" },
+    })
+    if err != nil { t.Error(err) }
 
     posts := make([]models.Post, 5)
     for i := 0; i < 5; i++ {
@@ -30,25 +34,22 @@ func TestPostCRUD(t *testing.T) {
         posts[i] = models.Post {
             BoardID: 1,
             Title: "test title " + index,
+            Slug: "test-title-" + index,
             Thumbnail: "test thumbnail " + index,
             Content: "test content " + index,
-            Tags: []models.PostTag {
-                { Name: "Tag test 1" },
-                { Name: "Tag test 2" },
-                { Name: "Tag test 3" },
-            },
+            Tags: tags,
         }
     }
 
     // insert
     for i := 0; i < len(posts); i++ {
-        if _, err := r.InsertPost(&posts[i]); err != nil {
+        if _, err := r.InsertPost(ctx, &posts[i]); err != nil {
             assert.Error(t, err)
         }
     }
 
     // update
-    err = r.UpdatePost(&models.Post {
+    err = r.UpdatePost(ctx, &models.Post {
         PostID: posts[0].PostID,
         Title: "updated title",
         Status: true,
@@ -58,12 +59,12 @@ func TestPostCRUD(t *testing.T) {
 
     enabled := true
     // select one
-    post, err := r.GetPost(nil, posts[0].PostID)
+    post, err := r.GetPost(ctx, nil, posts[0].PostID)
     if err != nil { t.Error(err) } 
 
     assert.Equal(t, post.Title, "updated title")
 
-    post, err = r.GetPost(&enabled, posts[1].PostID)
+    post, err = r.GetPost(ctx, &enabled, posts[1].PostID)
     if err != nil {
         if !errors.Is(err, gorm.ErrRecordNotFound) {
             assert.Error(t, errors.New("GetEnabledPost doesn't work correctly"))
@@ -73,38 +74,114 @@ func TestPostCRUD(t *testing.T) {
     }
 
     // check exists
-    check := r.CheckPostExists(posts[0].PostID)
+    check := r.CheckPostExists(ctx, posts[0].PostID)
     assert.Equal(t, true, check)
 
     // select many
     keyword := "test title 2"
     boardId := 1
-    searchResult, count := r.GetPosts(false, nil, 1, 5, &boardId, &keyword)
+    searchResult, count := r.GetPosts(ctx, false, nil, 1, 5, &boardId, &keyword, nil)
     assert.Equal(t, 1, count)
     assert.Equal(t, 1, len(searchResult))
 
     keyword = "test title"
-    searchResult, count = r.GetPosts(false, nil, 1, 5, nil, &keyword)
+    searchResult, count = r.GetPosts(ctx, false, nil, 1, 5, nil, &keyword, nil)
     assert.Equal(t, 4, count)
     assert.Equal(t, 4, len(searchResult))
 
     keyword = "updated"
-    searchResult, count = r.GetPosts(true, nil, 1, 5, nil, &keyword)
+    searchResult, count = r.GetPosts(ctx, true, nil, 1, 5, nil, &keyword, nil)
     assert.Equal(t, 1, count)
     assert.Equal(t, 1, len(searchResult))
 
      // update selected post
-//    err = r.ResetSelectedPost(&[]int{posts[0].PostID, posts[1].PostID, posts[2].PostID})
+//    err = r.ResetSelectedPost(ctx, &[]int{posts[0].PostID, posts[1].PostID, posts[2].PostID})
 //
-//    thumbnails := r.GetSelectedThumbnails()
+//    thumbnails := r.GetSelectedThumbnails(ctx)
 //    assert.Equal(t, 3, len(thumbnails))
 //    assert.Equal(t, "test thumbnail 2", thumbnails[1].Thumbnail)
 
     // delete all posts
     for i := 0; i < len(posts); i++ {
-        if err := r.DeletePost(posts[i].PostID); err != nil {
+        if err := r.DeletePost(ctx, posts[i].PostID); err != nil {
             t.Error(err)
         }
     }
 
 }
+
+func TestResetSelectedPost(t *testing.T) {
+    ctx := context.Background()
+    db := testutil.NewDB(t)
+    r := repositories.NewPostRepositoryImpl(db)
+    slotRepo := repositories.NewFeaturedSlotRepositoryImpl(db)
+
+    posts := make([]models.Post, 4)
+    for i := range posts {
+        posts[i] = testutil.CreatePost(t, db)
+    }
+    title := "override title"
+    start := time.Now().Add(-time.Hour)
+    err := slotRepo.ReplaceSlots(context.Background(), repositories.MainFeaturedGroup, []models.FeaturedSlot{
+        { PostID: posts[0].PostID, Title: &title },
+        { PostID: posts[1].PostID },
+        { PostID: posts[2].PostID, StartDate: &start },
+    })
+    assert.Nil(t, err)
+
+    // 기존 슬롯의 제목은 유지되며, 예약된 슬롯은 선택되지 않아도 삭제되지 않는다.
+    err = r.ResetSelectedPost(ctx, &[]int{ posts[3].PostID, posts[0].PostID })
+    assert.Nil(t, err)
+
+    slots := slotRepo.GetSlots(context.Background(), repositories.MainFeaturedGroup)
+    if assert.Len(t, slots, 3) {
+        assert.Equal(t, posts[3].PostID, slots[0].PostID)
+        assert.Equal(t, posts[0].PostID, slots[1].PostID)
+        assert.Equal(t, &title, slots[1].Title)
+        assert.Equal(t, posts[2].PostID, slots[2].PostID)
+        assert.NotNil(t, slots[2].StartDate)
+        assert.Equal(t, 2, slots[2].Position)
+    }
+
+    thumbnails := r.GetSelectedThumbnails(ctx)
+    if assert.Len(t, thumbnails, 3) {
+        assert.Equal(t, title, thumbnails[1].Title)
+    }
+}
+
+func TestDeletePostSlots(t *testing.T) {
+    ctx := context.Background()
+    db := testutil.NewDB(t)
+    r := repositories.NewPostRepositoryImpl(db)
+    slotRepo := repositories.NewFeaturedSlotRepositoryImpl(db)
+
+    deleted, kept := testutil.CreatePost(t, db), testutil.CreatePost(t, db)
+    err := slotRepo.ReplaceSlots(context.Background(), "sidebar", []models.FeaturedSlot{{ PostID: deleted.PostID }, { PostID: kept.PostID }})
+    assert.Nil(t, err)
+
+    // 삭제된 게시물의 슬롯은 함께 삭제된다.
+    assert.Nil(t, r.DeletePost(ctx, deleted.PostID))
+    slots := slotRepo.GetSlots(context.Background(), "sidebar")
+    if assert.Len(t, slots, 1) {
+        assert.Equal(t, kept.PostID, slots[0].PostID)
+    }
+}
+
+func TestDeletePostViews(t *testing.T) {
+    ctx := context.Background()
+    db := testutil.NewDB(t)
+    r := repositories.NewPostRepositoryImpl(db)
+    rankingRepo := repositories.NewRankingRepositoryImpl(db)
+
+    deleted, kept := testutil.CreatePost(t, db), testutil.CreatePost(t, db)
+    today := time.Now().Truncate(24 * time.Hour)
+    assert.Nil(t, rankingRepo.InsertView(context.Background(), deleted.PostID, today))
+    assert.Nil(t, rankingRepo.InsertView(context.Background(), kept.PostID, today))
+
+    // 삭제된 게시물의 조회수는 함께 삭제된다.
+    assert.Nil(t, r.DeletePost(ctx, deleted.PostID))
+    views := rankingRepo.GetDailyViews(context.Background(), today.AddDate(0, 0, -1))
+    if assert.Len(t, views, 1) {
+        assert.Equal(t, kept.PostID, views[0].PostID)
+    }
+}
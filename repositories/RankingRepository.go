@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"okra_board2/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RankingRepository interface {
+
+    // 게시물의 누적 조회수를 1 증가시키고, 
+    // viewDate에 해당하는 일자별 조회 기록을 갱신한다.
+    InsertView(ctx context.Context, postId int, viewDate time.Time) (err error)
+
+    // since 이후의 일자별 조회 기록을 불러온다.
+    // 공개된(status == true) 게시물의 기록만을 검색한다.
+    GetDailyViews(ctx context.Context, since time.Time) (views []models.PostView)
+
+    // 공개된 게시물들의 제목, 썸네일 및 누적 조회수 정보를 불러온다.
+    GetPublishedPostsInfo(ctx context.Context) (posts []models.RankedPost)
+
+}
+
+type RankingRepositoryImpl struct {
+    db *gorm.DB
+}
+
+func NewRankingRepositoryImpl(db *gorm.DB) RankingRepository {
+    return &RankingRepositoryImpl{ db: db }
+}
+
+func (r *RankingRepositoryImpl) InsertView(ctx context.Context, postId int, viewDate time.Time) (err error) {
+    return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        err := tx.Model(&models.Post{}).
+            Where("post_id = ?", postId).
+            UpdateColumn("views", gorm.Expr("views + ?", 1)).
+            Error
+        if err != nil { return err }
+
+        view := &models.PostView{ PostID: postId, ViewDate: viewDate, Count: 1 }
+        return tx.Clauses(clause.OnConflict{
+            Columns:    []clause.Column{{ Name: "post_id" }, { Name: "view_date" }},
+            DoUpdates:  clause.Assignments(map[string]interface{}{
+                "count": gorm.Expr("post_views.count + ?", 1),
+            }),
+        }).Create(view).Error
+    })
+}
+
+func (r *RankingRepositoryImpl) GetDailyViews(ctx context.Context, since time.Time) (views []models.PostView) {
+    r.db.WithContext(ctx).Model(&models.PostView{}).
+        Select("post_views.post_id, post_views.view_date, post_views.count").
+        Joins("INNER JOIN posts on posts.post_id = post_views.post_id").
+        Where("posts.status = ?", true).
+        Where("post_views.view_date >= ?", since).
+        Find(&views)
+    return
+}
+
+func (r *RankingRepositoryImpl) GetPublishedPostsInfo(ctx context.Context) (posts []models.RankedPost) {
+    r.db.WithContext(ctx).Table("posts").
+        Select("post_id, board_id, slug, title, thumbnail, views").
+        Where("status = ?", true).
+        Find(&posts)
+    return
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"okra_board2/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReactionRepository interface {
+
+    // 반응을 추가한다. 방문자가 이미 같은 반응을 남긴 경우 추가하지 않으며 false를 반환한다.
+    AddReaction(ctx context.Context, reaction *models.Reaction) (added bool, err error)
+
+    // 방문자가 남긴 반응을 취소한다.
+    RemoveReaction(ctx context.Context, reaction *models.Reaction) (err error)
+
+    // 게시물별, 반응 종류별 반응 수를 반환한다. 반응이 없는 게시물은 포함되지 않는다.
+    GetReactionCounts(ctx context.Context, postIds []int) (counts map[int]map[string]int)
+
+    // 반응이 많은 공개된 게시물을 순서대로 불러온다.
+    // reactionType이 nil이 아닐 경우 해당 종류의 반응만, since가 nil이 아닐 경우 since 이후의 반응만 센다.
+    GetReactionRanking(
+        ctx context.Context,
+        reactionType *string,
+        since *time.Time,
+        size int,
+    )                                               (posts []models.ReactedPost)
+
+}
+
+type ReactionRepositoryImpl struct {
+    db *gorm.DB
+}
+
+func NewReactionRepositoryImpl(db *gorm.DB) ReactionRepository {
+    return &ReactionRepositoryImpl{ db: db }
+}
+
+func (r *ReactionRepositoryImpl) AddReaction(ctx context.Context, reaction *models.Reaction) (added bool, err error) {
+    result := r.db.WithContext(ctx).Clauses(clause.OnConflict{ DoNothing: true }).Create(reaction)
+    return result.RowsAffected > 0, result.Error
+}
+
+func (r *ReactionRepositoryImpl) RemoveReaction(ctx context.Context, reaction *models.Reaction) (err error) {
+    return r.db.WithContext(ctx).
+        Where("post_id = ? AND type = ? AND visitor_id = ?", reaction.PostID, reaction.Type, reaction.VisitorID).
+        Delete(&models.Reaction{}).
+        Error
+}
+
+func (r *ReactionRepositoryImpl) GetReactionCounts(ctx context.Context, postIds []int) (counts map[int]map[string]int) {
+    counts = make(map[int]map[string]int)
+    if len(postIds) == 0 { return }
+
+    rows := []struct {
+        PostID  int
+        Type    string
+        Count   int
+    }{}
+    r.db.WithContext(ctx).Model(&models.Reaction{}).
+        Select("post_id, type, count(*) as count").
+        Where("post_id IN ?", postIds).
+        Group("post_id, type").
+        Find(&rows)
+    for _, row := range rows {
+        if counts[row.PostID] == nil {
+            counts[row.PostID] = make(map[string]int)
+        }
+        counts[row.PostID][row.Type] = row.Count
+    }
+    return
+}
+
+func (r *ReactionRepositoryImpl) GetReactionRanking(
+    ctx context.Context,
+    reactionType *string,
+    since *time.Time,
+    size int,
+) (posts []models.ReactedPost) {
+    query := r.db.WithContext(ctx).Table("reactions").
+        Select("posts.post_id, posts.board_id, posts.slug, posts.title, posts.thumbnail, count(*) as reactions").
+        Joins("INNER JOIN posts on posts.post_id = reactions.post_id").
+        Where("posts.status = ?", true)
+    if reactionType != nil {
+        query = query.Where("reactions.type = ?", *reactionType)
+    }
+    if since != nil {
+        query = query.Where("reactions.added_date >= ?", *since)
+    }
+    query.Group("posts.post_id, posts.board_id, posts.slug, posts.title, posts.thumbnail").
+        Order("reactions desc").
+        Order("posts.post_id desc").
+        Limit(size).
+        Find(&posts)
+    return
+}
@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"okra_board2/models"
+	"okra_board2/utils/slug"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository interface {
+
+    // slug가 같은 태그가 존재하면 해당 태그를, 존재하지 않으면 새로 생성한 태그를 반환한다.
+    // tags의 각 원소는 정규화된 Name과 Slug를 가지고 있어야 한다.
+    FindOrCreateTags(ctx context.Context, tags []models.Tag) (result []models.Tag, err error)
+
+    // tagId에 해당하는 태그를 불러온다.
+    // 조건에 부합하는 태그를 찾지 못할 경우 err 반환.
+    GetTag(ctx context.Context, tagId int) (tag *models.Tag, err error)
+
+    // slug에 해당하는 태그를 불러온다.
+    // 조건에 부합하는 태그를 찾지 못할 경우 err 반환.
+    GetTagBySlug(ctx context.Context, slug string) (tag *models.Tag, err error)
+
+    // 해당 slug를 사용하는 태그가 존재하는지 확인한다.
+    CheckSlugExists(ctx context.Context, slug string) (exists bool)
+
+    // 모든 태그를 게시물 수와 함께 불러온다. 게시물 수가 많은 순으로 정렬된다.
+    // enabled: true일 경우 공개된 게시물만 세며, 공개된 게시물이 없는 태그는 제외한다.
+    GetTagCounts(ctx context.Context, enabled bool) (tags []models.TagCount)
+
+    // 이름 또는 slug가 keyword로 시작하는 태그를 최대 size개 불러온다.
+    SearchTags(ctx context.Context, keyword string, size int) (tags []models.Tag)
+
+    // 태그의 이름과 slug를 변경한다.
+    UpdateTag(ctx context.Context, tag *models.Tag) (err error)
+
+    // source 태그가 달린 게시물에 target 태그를 달고, source 태그를 삭제한다.
+    MergeTags(ctx context.Context, sourceId, targetId int) (err error)
+
+    // 게시물에 연결되지 않은 태그를 모두 삭제하고, 삭제된 태그의 수를 반환한다.
+    DeleteUnusedTags(ctx context.Context) (count int64, err error)
+
+}
+
+type TagRepositoryImpl struct {
+    db *gorm.DB
+}
+
+func NewTagRepositoryImpl(db *gorm.DB) TagRepository {
+    return &TagRepositoryImpl{ db: db }
+}
+
+func findOrCreateTag(tx *gorm.DB, tag models.Tag) (models.Tag, error) {
+    result := models.Tag{}
+    err := tx.Where(models.Tag{ Slug: tag.Slug }).
+        Attrs(models.Tag{ Name: tag.Name }).
+        FirstOrCreate(&result).
+        Error
+    return result, err
+}
+
+func (r *TagRepositoryImpl) FindOrCreateTags(ctx context.Context, tags []models.Tag) (result []models.Tag, err error) {
+    err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        for _, tag := range tags {
+            found, err := findOrCreateTag(tx, tag)
+            if err != nil { return err }
+            result = append(result, found)
+        }
+        return nil
+    })
+    return
+}
+
+func (r *TagRepositoryImpl) GetTag(ctx context.Context, tagId int) (tag *models.Tag, err error) {
+    tag = &models.Tag{}
+    err = r.db.WithContext(ctx).First(tag, "tag_id = ?", tagId).Error
+    return
+}
+
+func (r *TagRepositoryImpl) GetTagBySlug(ctx context.Context, slug string) (tag *models.Tag, err error) {
+    tag = &models.Tag{}
+    err = r.db.WithContext(ctx).First(tag, "slug = ?", slug).Error
+    return
+}
+
+func (r *TagRepositoryImpl) CheckSlugExists(ctx context.Context, slug string) (exists bool) {
+    r.db.WithContext(ctx).Model(&models.Tag{}).
+        Select("count(*) > 0").
+        Where("slug = ?", slug).
+        Find(&exists)
+    return
+}
+
+func (r *TagRepositoryImpl) GetTagCounts(ctx context.Context, enabled bool) (tags []models.TagCount) {
+    query := r.db.WithContext(ctx).Table("tags").
+        Select("tags.tag_id, tags.name, tags.slug, count(posts.post_id) as post_count").
+        Joins("LEFT JOIN post_tags on post_tags.tag_id = tags.tag_id")
+    if enabled {
+        query = query.
+            Joins("LEFT JOIN posts on posts.post_id = post_tags.post_id AND posts.status = ?", true).
+            Having("count(posts.post_id) > 0")
+    } else {
+        query = query.Joins("LEFT JOIN posts on posts.post_id = post_tags.post_id")
+    }
+    query.Group("tags.tag_id, tags.name, tags.slug").
+        Order("post_count desc").
+        Order("tags.name asc").
+        Find(&tags)
+    return
+}
+
+func (r *TagRepositoryImpl) SearchTags(ctx context.Context, keyword string, size int) (tags []models.Tag) {
+    r.db.WithContext(ctx).Model(&models.Tag{}).
+        Where(likeIgnoreCase("name") + " OR slug LIKE ?", prefixPattern(keyword), slug.MakeTag(keyword)+"%").
+        Order("name asc").
+        Limit(size).
+        Find(&tags)
+    return
+}
+
+func (r *TagRepositoryImpl) UpdateTag(ctx context.Context, tag *models.Tag) (err error) {
+    return r.db.WithContext(ctx).Model(tag).
+        Select("name", "slug").
+        Updates(tag).
+        Error
+}
+
+func (r *TagRepositoryImpl) MergeTags(ctx context.Context, sourceId, targetId int) (err error) {
+    return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        var postIds []int
+        err := tx.Model(&models.PostTag{}).
+            Where("tag_id = ?", sourceId).
+            Pluck("post_id", &postIds).
+            Error
+        if err != nil { return err }
+
+        if len(postIds) > 0 {
+            postTags := make([]models.PostTag, len(postIds))
+            for i, postId := range postIds {
+                postTags[i] = models.PostTag{ PostID: postId, TagID: targetId }
+            }
+            err = tx.Clauses(clause.OnConflict{ DoNothing: true }).Create(&postTags).Error
+            if err != nil { return err }
+        }
+
+        if err := tx.Delete(&models.PostTag{}, "tag_id = ?", sourceId).Error; err != nil {
+            return err
+        }
+        return tx.Delete(&models.Tag{}, "tag_id = ?", sourceId).Error
+    })
+}
+
+func (r *TagRepositoryImpl) DeleteUnusedTags(ctx context.Context) (count int64, err error) {
+    result := r.db.WithContext(ctx).
+        Where("tag_id NOT IN (?)", r.db.WithContext(ctx).Model(&models.PostTag{}).Select("tag_id")).
+        Delete(&models.Tag{})
+    return result.RowsAffected, result.Error
+}
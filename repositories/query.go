@@ -0,0 +1,20 @@
+package repositories
+
+import "strings"
+
+// 대소문자를 구분하지 않는 LIKE 조건.
+// PostgreSQL의 LIKE는 대소문자를 구분하므로 MySQL의 기본 collation과 같은 결과를 얻기 위해 양쪽을 소문자로 비교한다.
+// 검색어는 containsPattern 또는 prefixPattern으로 변환하여 전달한다.
+func likeIgnoreCase(column string) string {
+    return "LOWER(" + column + ") LIKE ?"
+}
+
+// keyword를 포함하는 문자열의 LIKE 패턴
+func containsPattern(keyword string) string {
+    return "%" + strings.ToLower(keyword) + "%"
+}
+
+// keyword로 시작하는 문자열의 LIKE 패턴
+func prefixPattern(keyword string) string {
+    return strings.ToLower(keyword) + "%"
+}
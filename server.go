@@ -0,0 +1,346 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"okra_board2/apierror"
+	"okra_board2/config"
+	"okra_board2/controllers"
+	"okra_board2/logging"
+	"okra_board2/metrics"
+	"okra_board2/module"
+	"okra_board2/services"
+	"okra_board2/tracing"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/acme/autocert"
+	"gorm.io/gorm"
+)
+
+// 라우터와 서버 시작 시 실행되는 작업에서 사용하는 서비스
+type server struct {
+    conf            *config.Config
+    route           *gin.Engine
+    rankingService  services.RankingService
+    relatedService  services.RelatedPostService
+    sitemapService  services.SitemapService
+    postService     services.PostService
+    healthService   services.HealthService
+}
+
+// 서비스와 컨트롤러를 생성하고 모든 경로를 등록한다.
+func newServer(conf *config.Config, db *gorm.DB, s3 *s3.Client) *server {
+    route := gin.New()
+    // 신뢰하는 프록시가 전달한 헤더만 c.ClientIP()에 반영한다. 기본값은 모든 프록시를 신뢰하므로 반드시 설정한다.
+    // 주소 형식은 설정 검증에서 확인한다.
+    route.SetTrustedProxies(conf.Server.TrustedProxies)
+    route.Use(logging.Middleware("/", "/healthz", "/readyz"))
+    route.Use(tracing.Middleware("/", "/healthz", "/readyz", "/metrics"))
+    // panic으로 인한 500 응답도 지표에 기록되도록 Recovery보다 먼저 등록한다.
+    route.Use(metrics.Middleware())
+    route.Use(apierror.Recovery())
+    route.Use(limitBody(conf.Server.MaxBodyBytes))
+    route.Use(cors.New(cors.Config {
+        AllowAllOrigins:    true,
+        AllowMethods:       []string{"GET", "POST", "PUT", "DELETE"},
+        AllowHeaders:       []string{"Content-Type", "Authorization", logging.RequestIDHeader},
+        ExposeHeaders:      []string{"Authorization", logging.RequestIDHeader},
+        AllowCredentials:   true,
+        MaxAge: 12 * time.Hour,
+    }))
+
+    route.Static("/images", "./public/images")
+
+    rankingService := module.InitRankingService(db, conf)
+
+    relatedService := module.InitRelatedPostService(db)
+    sitemapService := module.InitSitemapService(db, conf)
+    postService := module.InitPostService(db, conf, s3, relatedService, sitemapService)
+
+    reactionService := module.InitReactionService(db, conf)
+    healthService := module.InitHealthService(db, conf, s3, rankingService)
+
+    authController := module.InitAuthController(db, conf)
+    adminController := module.InitAdminController(db)
+    postController := module.InitPostController(postService, rankingService, relatedService, reactionService)
+    rankingController := module.InitRankingController(rankingService)
+    tagController := module.InitTagController(db, relatedService)
+    featuredSlotController := module.InitFeaturedSlotController(db)
+    feedController := module.InitFeedController(db, conf)
+    sitemapController := module.InitSitemapController(sitemapService)
+    seoController := module.InitSeoController(db, conf)
+    commentController := module.InitCommentController(db, conf)
+    reactionController := module.InitReactionController(reactionService)
+    healthController := module.InitHealthController(healthService)
+    //imageController := controllers.NewImageControllerImpl(conf)
+    imageController := controllers.NewImageControllerImpl2(conf, s3)
+
+    // Route for health check
+    route.NoRoute(apierror.NotFound)
+    route.GET("/", func(c *gin.Context) {
+        c.Status(200)
+    })
+    route.GET("/healthz", healthController.Live)
+    route.GET("/readyz", healthController.Ready)
+
+    // 별도의 주소가 설정되지 않았을 경우 Prometheus 지표를 토큰으로 보호하여 함께 제공한다.
+    if conf.Metrics.Enabled() && conf.Metrics.Addr == "" {
+        route.GET("/metrics", gin.WrapH(metrics.Handler(conf.Metrics.Token)))
+    }
+
+    // 공개된 게시물의 RSS, Atom, JSON 피드
+    feeds := map[string]string {
+        "rss": controllers.FeedFormatRSS,
+        "atom": controllers.FeedFormatAtom,
+        "json": controllers.FeedFormatJSON,
+    }
+    for ext, format := range feeds {
+        route.GET("/feed." + ext, feedController.GetFeed(format))
+        route.GET("/boards/:boardId/feed." + ext, feedController.GetFeed(format))
+        route.GET("/tags/:tag/feed." + ext, feedController.GetFeed(format))
+    }
+
+    route.GET("/sitemap.xml", sitemapController.GetSitemap)
+    route.GET("/sitemaps/:file", sitemapController.GetSitemapPage)
+
+    v1 := route.Group("/api/v1")
+    {
+        v1.GET("/posts_enabled", postController.GetPosts(true))
+        v1.GET("/posts_enabled/:postId", postController.GetPost(true))
+        v1.GET("/posts_enabled/:postId/meta", seoController.GetPostMeta)
+        v1.GET("/boards/:boardId/posts_enabled/:slug", postController.GetPostBySlug(true))
+        v1.GET("/thumbnails", postController.GetSelectedThumbnails)
+        v1.GET("/posts_ranking", rankingController.GetRanking)
+
+        v1.GET("/posts", authController.Auth, postController.GetPosts(false))
+        v1.GET("/posts/:postId", authController.Auth, postController.GetPost(false))
+        v1.GET("/boards/:boardId/posts/:slug", authController.Auth, postController.GetPostBySlug(false))
+
+        v1.POST("/posts", authController.Auth, postController.WritePost)
+        v1.PUT("/posts/:postId", authController.Auth, postController.UpdatePost)
+        v1.DELETE("/posts/:postId", authController.Auth, postController.DeletePost)
+        v1.POST("/posts/selected", authController.Auth, postController.ResetSelectedPosts)
+        v1.GET("/posts/:postId/translations", authController.Auth, postController.GetTranslations)
+        v1.PUT("/posts/:postId/translations/:locale", authController.Auth, postController.SaveTranslation)
+        v1.DELETE("/posts/:postId/translations/:locale", authController.Auth, postController.DeleteTranslation)
+        v1.GET("/translations/missing", authController.Auth, postController.GetPostsMissingTranslation)
+
+        v1.GET("/featured/:group", featuredSlotController.GetFeaturedPosts)
+        v1.GET("/featured_slots/:group", authController.Auth, featuredSlotController.GetSlots)
+        v1.PUT("/featured_slots/:group", authController.Auth, featuredSlotController.ReplaceSlots)
+        v1.PUT("/featured_slots/:group/order", authController.Auth, featuredSlotController.ReorderSlots)
+
+        v1.GET("/tags_enabled", tagController.GetTags(true))
+        v1.GET("/tags", authController.Auth, tagController.GetTags(false))
+        v1.GET("/tags/search", authController.Auth, tagController.SearchTags)
+        v1.PUT("/tags/:tagId", authController.Auth, tagController.RenameTag)
+        v1.POST("/tags/:tagId/merge", authController.Auth, tagController.MergeTags)
+        v1.DELETE("/tags/unused", authController.Auth, tagController.DeleteUnusedTags)
+
+        v1.GET("/posts_enabled/:postId/comments", commentController.GetComments)
+        v1.POST("/posts_enabled/:postId/comments", commentController.WriteComment)
+        v1.PUT("/posts_enabled/:postId/comments/:commentId", commentController.UpdateComment)
+        v1.DELETE("/posts_enabled/:postId/comments/:commentId", commentController.DeleteComment)
+        v1.GET("/reactions", reactionController.GetReactionTypes)
+        v1.POST("/posts_enabled/:postId/reactions", reactionController.React)
+        v1.DELETE("/posts_enabled/:postId/reactions", reactionController.Unreact)
+        v1.GET("/reactions_ranking", authController.Auth, reactionController.GetReactionRanking)
+
+        v1.GET("/comments", authController.Auth, commentController.GetModerationQueue)
+        v1.PUT("/comments/status", authController.Auth, commentController.ModerateComments)
+        v1.DELETE("/comments", authController.Auth, commentController.DeleteComments)
+        v1.PUT("/boards/:boardId/comment_setting", authController.Auth, commentController.SetCommentEnabled)
+
+        // TODO
+        v1.POST("/admin", authController.Auth, adminController.Register)
+        v1.PUT("/admin/:id", authController.Auth, adminController.Update)
+        v1.DELETE("/admin/:id", authController.Auth, adminController.Delete)
+        v1.POST("/admin/login", authController.Login)
+        v1.POST("/admin/logout", authController.Logout)
+        v1.POST("/admin/auth", authController.ReissueAccessToken)
+
+        v1.POST("/image/upload", authController.Auth, imageController.UploadImage) 
+        v1.POST("/image/delete", authController.Auth, imageController.DeleteImage)
+    }
+    return &server{
+        conf: conf,
+        route: route,
+        rankingService: rankingService,
+        relatedService: relatedService,
+        sitemapService: sitemapService,
+        postService: postService,
+        healthService: healthService,
+    }
+}
+
+// slug, 요약이 없는 기존 게시글의 slug와 요약을 생성한 뒤 연관 게시글과 사이트맵을 생성한다.
+func (s *server) prepare() {
+    ctx, span := tracing.Start(context.Background(), "server.prepare")
+    defer span.End()
+    if err := s.postService.GenerateMissingSlugs(ctx); err != nil {
+        slog.Error("slug 생성에 실패했습니다.", "error", err)
+    }
+    if err := s.postService.GenerateMissingExcerpts(ctx); err != nil {
+        slog.Error("요약 생성에 실패했습니다.", "error", err)
+    }
+    if err := s.relatedService.Rebuild(ctx); err != nil {
+        slog.Error("연관 게시글 생성에 실패했습니다.", "error", err)
+    }
+    if err := s.sitemapService.Regenerate(ctx); err != nil {
+        slog.Error("사이트맵 생성에 실패했습니다.", "error", err)
+    }
+}
+
+const (
+    defaultAddr             = ":3000"
+    defaultMaxHeaderBytes   = 1 << 20
+    defaultMaxBodyBytes     = 32 << 20
+    defaultAutocertCacheDir = "./certs"
+)
+
+// 설정 값(초)을 Duration으로 변환한다. 0 이하일 경우 기본값을 사용한다.
+func seconds(value int, defaultValue time.Duration) time.Duration {
+    if value <= 0 {
+        return defaultValue
+    }
+    return time.Duration(value) * time.Second
+}
+
+// 요청 본문의 크기를 제한한다. Content-Length가 제한을 넘는 요청은 읽지 않고 body_too_large를 응답한다.
+func limitBody(limit int64) gin.HandlerFunc {
+    if limit <= 0 {
+        limit = defaultMaxBodyBytes
+    }
+    return func(c *gin.Context) {
+        if c.Request.ContentLength > limit {
+            apierror.Abort(c, apierror.New(apierror.CodeBodyTooLarge))
+            return
+        }
+        c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+        c.Next()
+    }
+}
+
+func (s *server) httpServer() *http.Server {
+    conf := s.conf.Server
+    addr := conf.Addr
+    if addr == "" {
+        addr = defaultAddr
+    }
+    maxHeaderBytes := conf.MaxHeaderBytes
+    if maxHeaderBytes <= 0 {
+        maxHeaderBytes = defaultMaxHeaderBytes
+    }
+    return &http.Server{
+        Addr: addr,
+        Handler: s.route,
+        ReadTimeout: seconds(conf.ReadTimeout, 30 * time.Second),
+        ReadHeaderTimeout: seconds(conf.ReadTimeout, 30 * time.Second),
+        WriteTimeout: seconds(conf.WriteTimeout, 60 * time.Second),
+        IdleTimeout: seconds(conf.IdleTimeout, 120 * time.Second),
+        MaxHeaderBytes: maxHeaderBytes,
+        ErrorLog: slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+    }
+}
+
+// 요청을 처리하고 백그라운드 작업을 실행한다. 
+// ctx가 취소되면 새로운 연결을 받지 않고 처리 중인 요청이 끝나기를 최대 ShutdownTimeout까지 기다린 뒤, 
+// 백그라운드 작업을 멈추고 반환한다.
+func (s *server) serve(ctx context.Context) error {
+    srv := s.httpServer()
+    servers := []*http.Server{ srv }
+    listen := srv.ListenAndServe
+
+    tls := s.conf.Server.TLS
+    if tls.CertFile != "" {
+        listen = func() error { return srv.ListenAndServeTLS(tls.CertFile, tls.KeyFile) }
+    } else if tls.Autocert.Enabled() {
+        cacheDir := tls.Autocert.CacheDir
+        if cacheDir == "" {
+            cacheDir = defaultAutocertCacheDir
+        }
+        manager := &autocert.Manager{
+            Prompt: autocert.AcceptTOS,
+            HostPolicy: autocert.HostWhitelist(tls.Autocert.Hosts...),
+            Cache: autocert.DirCache(cacheDir),
+            Email: tls.Autocert.Email,
+        }
+        srv.TLSConfig = manager.TLSConfig()
+        listen = func() error { return srv.ListenAndServeTLS("", "") }
+        if tls.Autocert.HTTPAddr != "" {
+            servers = append(servers, &http.Server{
+                Addr: tls.Autocert.HTTPAddr,
+                Handler: manager.HTTPHandler(nil),
+                ReadHeaderTimeout: srv.ReadHeaderTimeout,
+                ErrorLog: srv.ErrorLog,
+            })
+        }
+    }
+
+    if s.conf.Metrics.Addr != "" {
+        mux := http.NewServeMux()
+        mux.Handle("/metrics", metrics.Handler(s.conf.Metrics.Token))
+        servers = append(servers, &http.Server{
+            Addr: s.conf.Metrics.Addr,
+            Handler: mux,
+            ReadHeaderTimeout: srv.ReadHeaderTimeout,
+            ErrorLog: srv.ErrorLog,
+        })
+    }
+
+    workerCtx, stopWorkers := context.WithCancel(context.Background())
+    var workers sync.WaitGroup
+    workers.Add(1)
+    go func() {
+        defer workers.Done()
+        s.rankingService.Run(workerCtx)
+    }()
+    workers.Add(1)
+    go func() {
+        defer workers.Done()
+        s.relatedService.Run(workerCtx)
+    }()
+    workers.Add(1)
+    go func() {
+        defer workers.Done()
+        s.sitemapService.Run(workerCtx)
+    }()
+    defer func() {
+        stopWorkers()
+        workers.Wait()
+    }()
+
+    errs := make(chan error, len(servers))
+    go func() { errs <- listen() }()
+    for _, extra := range servers[1:] {
+        go func(extra *http.Server) { errs <- extra.ListenAndServe() }(extra)
+    }
+    slog.Info("요청을 기다립니다.", "addr", srv.Addr)
+
+    var err error
+    select {
+    case err = <-errs:
+    case <-ctx.Done():
+        // 로드밸런서가 준비 상태의 변화를 감지할 때까지 기다린 뒤 연결을 닫는다.
+        s.healthService.Shutdown()
+        time.Sleep(time.Duration(s.conf.Server.ShutdownDelay) * time.Second)
+    }
+
+    shutdownCtx, cancel := context.WithTimeout(context.Background(), seconds(s.conf.Server.ShutdownTimeout, 30 * time.Second))
+    defer cancel()
+    for _, server := range servers {
+        if shutdownErr := server.Shutdown(shutdownCtx); shutdownErr != nil && err == nil {
+            err = shutdownErr
+        }
+    }
+    if errors.Is(err, http.ErrServerClosed) {
+        err = nil
+    }
+    return err
+}
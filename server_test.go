@@ -0,0 +1,429 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"okra_board2/models"
+	"okra_board2/testutil"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type routeTest struct {
+    srv     *server
+    token   string
+}
+
+// 요청을 보내고 응답을 반환한다. body가 nil이 아닐 경우 JSON으로 전송한다.
+func (r *routeTest) do(t *testing.T, method, path string, body interface{}, auth bool) *httptest.ResponseRecorder {
+    t.Helper()
+    var reader *bytes.Reader
+    if body != nil {
+        encoded, err := json.Marshal(body)
+        if err != nil { t.Fatal(err) }
+        reader = bytes.NewReader(encoded)
+    } else {
+        reader = bytes.NewReader(nil)
+    }
+    req := httptest.NewRequest(method, path, reader)
+    if body != nil {
+        req.Header.Set("Content-Type", "application/json")
+    }
+    if auth {
+        req.Header.Set("Authorization", r.token)
+    }
+    w := httptest.NewRecorder()
+    r.srv.route.ServeHTTP(w, req)
+    return w
+}
+
+func TestRoutes(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    conf := testutil.NewConfig()
+    conf.Site.Locales = []string{"en"}
+    conf.Comment.AutoApprove = true
+    conf.Metrics.Token = "metrics-token"
+    db := testutil.NewDB(t)
+    s3, fakeS3 := testutil.NewS3(t)
+
+    testutil.CreateAdmin(t, db, "administrator11")
+    first := testutil.CreatePost(t, db, func(post *models.Post) {
+        post.Tags = []models.Tag{{ Name: "Go", Slug: "go" }}
+    })
+    second := testutil.CreatePost(t, db, func(post *models.Post) {
+        post.Tags = []models.Tag{{ Name: "Go", Slug: "go" }, { Name: "Gin", Slug: "gin" }}
+    })
+    draft := testutil.CreatePost(t, db, func(post *models.Post) {
+        post.Status = false
+    })
+    unused := testutil.CreateTag(t, db, "Unused", "unused")
+
+    r := &routeTest{ srv: newServer(conf, db, s3) }
+    r.srv.prepare()
+    firstId, secondId := strconv.Itoa(first.PostID), strconv.Itoa(second.PostID)
+
+    // 로그인하지 않으면 관리자 경로에 접근할 수 없다.
+    assert.Equal(t, 401, r.do(t, "GET", "/api/v1/posts", nil, false).Code)
+    assert.Equal(t, 401, r.do(t, "POST", "/api/v1/admin/login", models.Admin{ ID: "administrator11", Password: "wrong" }, false).Code)
+
+    w := r.do(t, "POST", "/api/v1/admin/login", models.Admin{ ID: "administrator11", Password: testutil.AdminPassword }, false)
+    assert.Equal(t, 200, w.Code)
+    r.token = w.Header().Get("Authorization")
+    assert.Len(t, strings.Split(r.token, " "), 2)
+
+    public := []struct {
+        path    string
+        code    int
+    }{
+        { "/", 200 },
+        { "/feed.rss", 200 },
+        { "/feed.atom", 200 },
+        { "/feed.json", 200 },
+        { "/boards/1/feed.rss", 200 },
+        { "/tags/go/feed.atom", 200 },
+        { "/sitemap.xml", 200 },
+        { "/sitemaps/sitemap-1.xml", 200 },
+        { "/sitemaps/unknown.xml", 404 },
+        { "/unknown", 404 },
+        { "/healthz", 200 },
+        // 순위 갱신 작업은 serve에서 실행된다.
+        { "/readyz", 503 },
+        { "/api/v1/posts_enabled", 200 },
+        { "/api/v1/posts_enabled?tag=gin", 200 },
+        { "/api/v1/posts_enabled/" + firstId, 200 },
+        { "/api/v1/posts_enabled/" + strconv.Itoa(draft.PostID), 404 },
+        { "/api/v1/posts_enabled/" + firstId + "/meta", 200 },
+        { "/api/v1/boards/1/posts_enabled/" + second.Slug, 200 },
+        { "/api/v1/thumbnails", 200 },
+        { "/api/v1/posts_ranking", 200 },
+        { "/api/v1/posts_ranking?size=-1", 400 },
+        { "/api/v1/posts_ranking?size=1000", 400 },
+        { "/api/v1/featured/main", 200 },
+        { "/api/v1/tags_enabled", 200 },
+        { "/api/v1/reactions", 200 },
+        { "/api/v1/posts_enabled/" + firstId + "/comments", 200 },
+    }
+    for _, test := range public {
+        assert.Equal(t, test.code, r.do(t, "GET", test.path, nil, false).Code, test.path)
+    }
+
+    // 에러는 코드와 메시지를 포함한 공통 형식으로 응답한다.
+    errorResponse := models.ErrorResponse{}
+    json.Unmarshal(r.do(t, "GET", "/unknown", nil, false).Body.Bytes(), &errorResponse)
+    assert.Equal(t, "not_found", errorResponse.Error.Code)
+    assert.Equal(t, 404, errorResponse.Error.Status)
+    assert.NotEmpty(t, errorResponse.Error.Message)
+    errorResponse = models.ErrorResponse{}
+    json.Unmarshal(r.do(t, "POST", "/api/v1/posts", models.Post{ BoardID: 1 }, true).Body.Bytes(), &errorResponse)
+    assert.Equal(t, "validation_failed", errorResponse.Error.Code)
+    assert.NotEmpty(t, errorResponse.Error.Fields)
+
+    w = r.do(t, "GET", "/api/v1/posts_enabled?tag=gin", nil, false)
+    page := struct { Posts []models.Post `json:"posts"` }{}
+    json.Unmarshal(w.Body.Bytes(), &page)
+    assert.Len(t, page.Posts, 1)
+
+    admin := []struct {
+        method  string
+        path    string
+        body    interface{}
+        code    int
+    }{
+        { "GET", "/api/v1/posts", nil, 200 },
+        { "GET", "/api/v1/posts/" + strconv.Itoa(draft.PostID), nil, 200 },
+        { "GET", "/api/v1/boards/1/posts/" + draft.Slug, nil, 200 },
+        { "POST", "/api/v1/posts", models.Post{ BoardID: 1, Title: "new post", Thumbnail: first.Thumbnail, Content: "<p>new content</p>" }, 200 },
+        { "POST", "/api/v1/posts", models.Post{ BoardID: 1 }, 422 },
+        { "PUT", "/api/v1/posts/" + firstId, models.Post{ BoardID: 1, Title: "updated title", Thumbnail: first.Thumbnail, Content: first.Content, Status: true }, 200 },
+        { "PUT", "/api/v1/posts/9999", models.Post{ BoardID: 1, Title: "updated title", Thumbnail: first.Thumbnail, Content: first.Content }, 404 },
+        { "POST", "/api/v1/posts/selected", []int{ first.PostID, second.PostID }, 200 },
+        { "PUT", "/api/v1/posts/" + firstId + "/translations/en", models.PostTranslation{ Title: "English title", Content: "<p>English</p>" }, 200 },
+        { "GET", "/api/v1/posts/" + firstId + "/translations", nil, 200 },
+        { "GET", "/api/v1/translations/missing?locale=en", nil, 200 },
+        { "GET", "/api/v1/translations/missing", nil, 400 },
+        { "DELETE", "/api/v1/posts/" + firstId + "/translations/en", nil, 200 },
+        { "GET", "/api/v1/featured_slots/main", nil, 200 },
+        { "PUT", "/api/v1/featured_slots/main", []models.FeaturedSlot{{ PostID: first.PostID }, { PostID: second.PostID }}, 200 },
+        { "GET", "/api/v1/tags", nil, 200 },
+        { "GET", "/api/v1/tags/search?keyword=g", nil, 200 },
+        { "GET", "/api/v1/tags/search?keyword=g&size=-1", nil, 400 },
+        { "PUT", "/api/v1/tags/" + strconv.Itoa(unused.TagID), gin.H{ "name": "Renamed" }, 200 },
+        { "PUT", "/api/v1/tags/9999", gin.H{ "name": "Renamed" }, 404 },
+        { "POST", "/api/v1/tags/" + strconv.Itoa(second.Tags[1].TagID) + "/merge", gin.H{ "targetId": second.Tags[0].TagID }, 200 },
+        { "DELETE", "/api/v1/tags/unused", nil, 200 },
+        { "POST", "/api/v1/posts_enabled/" + firstId + "/comments", models.Comment{ Nickname: "okra", Password: "1234", Content: "hello" }, 200 },
+        { "GET", "/api/v1/comments", nil, 200 },
+        { "PUT", "/api/v1/boards/2/comment_setting", models.CommentSetting{ Enabled: false }, 200 },
+        { "POST", "/api/v1/posts_enabled/" + firstId + "/reactions", gin.H{ "type": "like" }, 200 },
+        { "POST", "/api/v1/posts_enabled/" + firstId + "/reactions", gin.H{ "type": "unknown" }, 400 },
+        { "GET", "/api/v1/reactions_ranking", nil, 200 },
+        { "GET", "/api/v1/reactions_ranking?size=-1", nil, 400 },
+        { "DELETE", "/api/v1/posts_enabled/" + firstId + "/reactions", gin.H{ "type": "like" }, 200 },
+        { "POST", "/api/v1/admin", models.Admin{ ID: "administrator12", Password: "@@Test123456", Name: "홍길동", Email: "admin12@example.com", Phone: "010-1111-2222" }, 200 },
+        { "PUT", "/api/v1/admin/administrator12", models.Admin{ ID: "administrator12", Password: "@@Test1234567", Name: "홍길동", Email: "admin12@example.com", Phone: "010-1111-2222" }, 200 },
+        { "DELETE", "/api/v1/admin/administrator12", nil, 200 },
+        { "POST", "/api/v1/image/delete", []string{ "thumbnail.png" }, 200 },
+    }
+    for _, test := range admin {
+        w := r.do(t, test.method, test.path, test.body, true)
+        assert.Equal(t, test.code, w.Code, test.method + " " + test.path + " " + w.Body.String())
+        // 인증이 필요한 경로는 토큰 없이 접근할 수 없다.
+        if strings.HasPrefix(test.path, "/api/v1/posts_enabled/") { continue }
+        assert.Equal(t, 401, r.do(t, test.method, test.path, test.body, false).Code, test.method + " " + test.path)
+    }
+
+    // 댓글 수정, 삭제, 검토
+    comments := []models.Comment{}
+    json.Unmarshal(r.do(t, "GET", "/api/v1/posts_enabled/" + firstId + "/comments", nil, false).Body.Bytes(), &comments)
+    if assert.Len(t, comments, 1) {
+        commentPath := "/api/v1/posts_enabled/" + firstId + "/comments/" + strconv.Itoa(comments[0].CommentID)
+        assert.Equal(t, 200, r.do(t, "PUT", commentPath, models.Comment{ Nickname: "okra", Password: "1234", Content: "edited" }, false).Code)
+        assert.Equal(t, 403, r.do(t, "DELETE", commentPath, gin.H{ "password": "wrong" }, false).Code)
+        assert.Equal(t, 200, r.do(t, "PUT", "/api/v1/comments/status", gin.H{
+            "commentIds": []int{ comments[0].CommentID }, "status": models.CommentStatusRejected,
+        }, true).Code)
+        assert.Equal(t, 200, r.do(t, "DELETE", "/api/v1/comments", []int{ comments[0].CommentID }, true).Code)
+    }
+
+    // 슬롯 순서 변경
+    slots := []models.FeaturedSlot{}
+    json.Unmarshal(r.do(t, "GET", "/api/v1/featured_slots/main", nil, true).Body.Bytes(), &slots)
+    if assert.Len(t, slots, 2) {
+        order := []int{ slots[1].SlotID, slots[0].SlotID }
+        assert.Equal(t, 200, r.do(t, "PUT", "/api/v1/featured_slots/main/order", order, true).Code)
+    }
+
+    // 게시물 삭제. 삭제된 게시물의 슬롯도 함께 삭제된다.
+    assert.Equal(t, 200, r.do(t, "DELETE", "/api/v1/posts/" + secondId, nil, true).Code)
+    assert.Equal(t, 401, r.do(t, "DELETE", "/api/v1/posts/" + secondId, nil, false).Code)
+    assert.Equal(t, 404, r.do(t, "DELETE", "/api/v1/posts/" + secondId, nil, true).Code)
+    json.Unmarshal(r.do(t, "GET", "/api/v1/featured_slots/main", nil, true).Body.Bytes(), &slots)
+    assert.Len(t, slots, 1)
+
+    // 이미지 업로드
+    var body bytes.Buffer
+    form := multipart.NewWriter(&body)
+    part, _ := form.CreateFormFile("file", "image.png")
+    part.Write([]byte("png"))
+    form.Close()
+    req := httptest.NewRequest("POST", "/api/v1/image/upload", &body)
+    req.Header.Set("Content-Type", form.FormDataContentType())
+    req.Header.Set("Authorization", r.token)
+    w = httptest.NewRecorder()
+    r.srv.route.ServeHTTP(w, req)
+    assert.Equal(t, 200, w.Code, w.Body.String())
+    uploaded := struct { File string `json:"file"` }{}
+    json.Unmarshal(w.Body.Bytes(), &uploaded)
+    assert.Contains(t, fakeS3.Keys(conf.AWS.Bucket), "images/" + uploaded.File)
+
+    // 토큰 재발급과 로그아웃
+    w = r.do(t, "POST", "/api/v1/admin/auth", nil, true)
+    assert.Equal(t, http.StatusOK, w.Code)
+    refreshToken := strings.Split(r.token, " ")[1]
+    r.token = strings.Split(w.Header().Get("Authorization"), " ")[0] + " " + refreshToken
+    assert.Equal(t, 200, r.do(t, "POST", "/api/v1/admin/logout", nil, true).Code)
+    assert.Equal(t, 401, r.do(t, "POST", "/api/v1/admin/auth", nil, true).Code)
+
+    // panic이 발생한 요청도 500으로 기록된다.
+    r.srv.route.GET("/panic", func(c *gin.Context) { panic("test panic") })
+    assert.Equal(t, 500, r.do(t, "GET", "/panic", nil, false).Code)
+
+    // Prometheus 지표
+    assert.Equal(t, 401, r.do(t, "GET", "/metrics", nil, false).Code)
+    r.token = "Bearer metrics-token"
+    w = r.do(t, "GET", "/metrics", nil, true)
+    assert.Equal(t, 200, w.Code)
+    for _, metric := range []string{
+        `okra_http_requests_total{method="GET",route="/api/v1/posts_enabled/:postId",status="200"}`,
+        `okra_http_requests_total{method="GET",route="unmatched",status="404"}`,
+        `okra_http_requests_total{method="GET",route="/panic",status="500"}`,
+        `okra_admin_logins_total{result="success"}`,
+        `okra_admin_logins_total{result="failure"}`,
+        `okra_storage_operations_total{operation="PutObject",result="success"}`,
+        `okra_job_runs_total{job="sitemap_regenerate",result="success"}`,
+        `go_sql_open_connections{db_name=":memory:"}`,
+    } {
+        // 응답 전체가 출력되지 않도록 Contains 대신 사용한다.
+        assert.True(t, strings.Contains(w.Body.String(), metric), metric)
+    }
+}
+
+func TestServe(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    conf := testutil.NewConfig()
+    conf.Server.MaxBodyBytes = 64
+    // 순위 갱신 작업이 시작되기 전의 /readyz 결과를 재사용하지 않는다.
+    conf.Server.ReadyCacheTTL = 1
+    db := testutil.NewDB(t)
+    s3, _ := testutil.NewS3(t)
+    srv := newServer(conf, db, s3)
+
+    // 본문 크기 제한
+    large := models.Comment{ Nickname: "okra", Password: "1234", Content: strings.Repeat("a", 100) }
+    r := &routeTest{ srv: srv }
+    assert.Equal(t, http.StatusRequestEntityTooLarge, r.do(t, "POST", "/api/v1/posts_enabled/1/comments", large, false).Code)
+
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil { t.Fatal(err) }
+    conf.Server.Addr = listener.Addr().String()
+    listener.Close()
+
+    // 종료 요청을 받아도 처리 중인 요청은 끝까지 처리한다.
+    started := make(chan struct{})
+    srv.route.GET("/slow", func(c *gin.Context) {
+        close(started)
+        time.Sleep(200 * time.Millisecond)
+        c.Status(200)
+    })
+
+    ctx, cancel := context.WithCancel(context.Background())
+    done := make(chan error)
+    go func() { done <- srv.serve(ctx) }()
+
+    url := "http://" + conf.Server.Addr
+    for i := 0; i < 50; i++ {
+        if resp, err := http.Get(url + "/"); err == nil {
+            resp.Body.Close()
+            break
+        }
+        time.Sleep(20 * time.Millisecond)
+    }
+
+    assert.Eventually(t, func() bool {
+        resp, err := http.Get(url + "/readyz")
+        if err != nil { return false }
+        resp.Body.Close()
+        return resp.StatusCode == 200
+    }, time.Second, 20 * time.Millisecond)
+
+    result := make(chan int)
+    go func() {
+        resp, err := http.Get(url + "/slow")
+        if err != nil {
+            result <- 0
+            return
+        }
+        resp.Body.Close()
+        result <- resp.StatusCode
+    }()
+    <-started
+    cancel()
+
+    assert.Equal(t, 200, <-result)
+    assert.NoError(t, <-done)
+    _, err = http.Get(url + "/")
+    assert.Error(t, err)
+}
+
+// DB 에러는 panic 없이 internal_error로 응답한다.
+func TestRoutesDBError(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    conf := testutil.NewConfig()
+    db := testutil.NewDB(t)
+    s3, _ := testutil.NewS3(t)
+    post := testutil.CreatePost(t, db)
+    r := &routeTest{ srv: newServer(conf, db, s3) }
+
+    sqlDB, err := db.DB()
+    if err != nil { t.Fatal(err) }
+    sqlDB.Close()
+
+    w := r.do(t, "GET", "/api/v1/posts_enabled/" + strconv.Itoa(post.PostID), nil, false)
+    assert.Equal(t, 500, w.Code)
+    errorResponse := models.ErrorResponse{}
+    json.Unmarshal(w.Body.Bytes(), &errorResponse)
+    assert.Equal(t, "internal_error", errorResponse.Error.Code)
+    assert.False(t, strings.Contains(w.Body.String(), "closed"))
+}
+
+func TestClientIP(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    conf := testutil.NewConfig()
+    conf.Comment.AutoApprove = true
+    conf.Comment.RateLimit = 1
+    db := testutil.NewDB(t)
+    s3, _ := testutil.NewS3(t)
+    post := testutil.CreatePost(t, db)
+    path := "/api/v1/posts_enabled/" + strconv.Itoa(post.PostID) + "/comments"
+
+    // 192.0.2.1(httptest의 연결 주소)에서 X-Forwarded-For 헤더와 함께 댓글을 작성한다.
+    comment := func(r *routeTest, forwardedFor string) int {
+        body, _ := json.Marshal(models.Comment{ Nickname: "okra", Password: "1234", Content: "hello" })
+        req := httptest.NewRequest("POST", path, bytes.NewReader(body))
+        req.Header.Set("Content-Type", "application/json")
+        req.Header.Set("X-Forwarded-For", forwardedFor)
+        w := httptest.NewRecorder()
+        r.srv.route.ServeHTTP(w, req)
+        return w.Code
+    }
+
+    // 신뢰하는 프록시가 없으면 헤더를 바꾸어도 작성 제한을 피할 수 없다.
+    r := &routeTest{ srv: newServer(conf, db, s3) }
+    assert.Equal(t, 200, comment(r, "203.0.113.1"))
+    assert.Equal(t, 429, comment(r, "203.0.113.2"))
+
+    // 헤더를 바꾸어도 같은 방문자의 반응으로 센다.
+    react := func(r *routeTest, forwardedFor string) map[string]int {
+        req := httptest.NewRequest("POST", "/api/v1/posts_enabled/" + strconv.Itoa(post.PostID) + "/reactions", strings.NewReader(`{"type":"like"}`))
+        req.Header.Set("Content-Type", "application/json")
+        req.Header.Set("X-Forwarded-For", forwardedFor)
+        w := httptest.NewRecorder()
+        r.srv.route.ServeHTTP(w, req)
+        counts := map[string]int{}
+        json.Unmarshal(w.Body.Bytes(), &counts)
+        return counts
+    }
+    assert.Equal(t, 1, react(r, "203.0.113.1")["like"])
+    assert.Equal(t, 1, react(r, "203.0.113.2")["like"])
+
+    // 신뢰하는 프록시가 전달한 헤더의 IP로 제한한다.
+    conf.Server.TrustedProxies = []string{ "192.0.2.0/24" }
+    r = &routeTest{ srv: newServer(conf, db, s3) }
+    assert.Equal(t, 200, comment(r, "203.0.113.3"))
+    assert.Equal(t, 429, comment(r, "203.0.113.3"))
+}
+
+func TestFeedCache(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    conf := testutil.NewConfig()
+    db := testutil.NewDB(t)
+    s3, _ := testutil.NewS3(t)
+    testutil.CreatePost(t, db)
+    r := &routeTest{ srv: newServer(conf, db, s3) }
+
+    get := func(header map[string]string) *httptest.ResponseRecorder {
+        req := httptest.NewRequest("GET", "/feed.atom", nil)
+        for key, value := range header {
+            req.Header.Set(key, value)
+        }
+        w := httptest.NewRecorder()
+        r.srv.route.ServeHTTP(w, req)
+        return w
+    }
+
+    // 피드 자신의 주소는 요청 헤더와 관계없이 사이트 주소로 만든다.
+    req := httptest.NewRequest("GET", "/feed.atom", nil)
+    req.Host = "evil.example.org"
+    req.Header.Set("X-Forwarded-Proto", "javascript")
+    w := httptest.NewRecorder()
+    r.srv.route.ServeHTTP(w, req)
+    assert.Equal(t, 200, w.Code)
+    assert.Contains(t, w.Body.String(), "https://example.com/feed.atom")
+    assert.NotContains(t, w.Body.String(), "evil.example.org")
+
+    etag := w.Header().Get("ETag")
+    assert.Equal(t, 304, get(map[string]string{ "If-None-Match": etag }).Code)
+    assert.Equal(t, 304, get(map[string]string{ "If-None-Match": `"other", W/` + etag }).Code)
+    assert.Equal(t, 304, get(map[string]string{ "If-None-Match": "*" }).Code)
+    assert.Equal(t, 200, get(map[string]string{ "If-None-Match": `"other", W/"another"` }).Code)
+}
@@ -1,7 +1,8 @@
 package services
 
 import (
-	"fmt"
+	"context"
+	"okra_board2/logging"
 	"okra_board2/models"
 	"okra_board2/repositories"
 	"okra_board2/utils/encryption"
@@ -13,7 +14,7 @@ type AdminService interface {
     
     // db상에 저장된 관리자의 계정 정보와 입력된 정보를 비교하여
     // 일치할경우 true, 그렇지 않을 경우 false를 반환한다.
-    Login(*models.Admin)        bool
+    Login(ctx context.Context, admin *models.Admin) bool
 
     // 입력된 신규 관리자 정보의 유효성을 검증하고 db에 저장한다.
     // 유효하지 않은 정보: return (false, 유효성 검사 결과)
@@ -161,11 +162,11 @@ func (s *AdminServiceImpl) adminUpdateValidation(admin *models.Admin) (result *m
     return result.GetOrNil()
 }
 
-func (s *AdminServiceImpl) Login(admin *models.Admin) bool {
+func (s *AdminServiceImpl) Login(ctx context.Context, admin *models.Admin) bool {
     insertedPassword := admin.Password
     adminDetail, err := s.adminRepo.GetAdmin(admin.ID)
     if err != nil {
-        fmt.Println(err)
+        logging.FromContext(ctx).Warn("관리자를 조회하지 못했습니다.", "admin_id", admin.ID, "error", err)
         return false
     }
     return encryption.EncryptSHA256(insertedPassword) == adminDetail.Password
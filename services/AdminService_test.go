@@ -1,21 +1,18 @@
 package services_test
 
 import (
-	"okra_board2/config"
+	"context"
 	"okra_board2/models"
 	"okra_board2/repositories"
 	"okra_board2/services"
+	"okra_board2/testutil"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestAdminService(t *testing.T) {
-    conf, err := config.LoadConfigTest()
-    if err != nil { assert.Error(t, err) }
-
-    db, err := config.InitDBConnection(conf)
-    if err != nil { assert.Error(t, err) }
+    db := testutil.NewDB(t)
     adminRepo := repositories.NewAdminRepositoryImpl(db)
     s := services.NewAdminServiceImpl(adminRepo)
 
@@ -118,7 +115,7 @@ func TestAdminService(t *testing.T) {
         ID: "administrator11",
         Password: "@@Test123456",
     }
-    ok = s.Login(&loginInfo)
+    ok = s.Login(context.Background(), &loginInfo)
     assert.Equal(t, ok, false)
 
     // login case 2: correct
@@ -126,7 +123,7 @@ func TestAdminService(t *testing.T) {
         ID: "administrator11",
         Password: "@@Test1234567",
     }
-    ok = s.Login(&loginInfo)
+    ok = s.Login(context.Background(), &loginInfo)
     assert.Equal(t, ok, true)
 
     // delete
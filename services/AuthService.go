@@ -1,10 +1,12 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"okra_board2/config"
 	"okra_board2/models"
 	"okra_board2/repositories"
-	"os"
+	"okra_board2/tracing"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -14,16 +16,16 @@ import (
 type AuthService interface {
 
     // 관리자 id를 통해 새로운 Access Token, Refresh Token 쌍을 발급하고, db에 저장한다.
-    CreateTokenPair(string)             (auth *models.AdminAuth, err error)
+    CreateTokenPair(ctx context.Context, id string) (auth *models.AdminAuth, err error)
 
     // 이미 존재 하는 토큰 쌍의 uuid와 관리자 id 정보를 가지고 Access Token을 새롭게 발급한다.
     // 재발급된 Access Token은 db상에서 업데이트된다.
-    CreateAccessToken(uuid, id string)   (at string, err error)
+    CreateAccessToken(ctx context.Context, uuid, id string) (at string, err error)
 
     // Refresh Token에서 추출한 uuid로 db상의 토큰 쌍을 검색하여
     // 주어진 토큰 쌍과 일치하는지 검증한다.
     // 검증에 실패할 경우 error를 반환한다.
-    VerifyTokenPair(at, rt string)      (uuid string, err error)
+    VerifyTokenPair(ctx context.Context, at, rt string) (uuid string, err error)
 
     // Access Token의 유효성을 검증하고, claim과 error를 반환한다.
     VerifyAccessToken(string)           (claims jwt.MapClaims, err error)
@@ -32,26 +34,30 @@ type AuthService interface {
     VerifyRefreshToken(string)          (claims jwt.MapClaims, err error)
 
     // Access Token, Refresh Token 쌍을 db에서 삭제한다.
-    DeleteTokenPair(uuid string)        (err error)
+    DeleteTokenPair(ctx context.Context, uuid string) (err error)
 }
 
 type AuthServiceImpl struct {
     authRepo repositories.AuthRepository
     adminService AdminService
+    conf *config.Config
 }
 
 func NewAuthServiceImpl(
     authRepo repositories.AuthRepository,
     adminService AdminService,
+    conf *config.Config,
 ) AuthService {
     return &AuthServiceImpl{ 
         authRepo: authRepo,
         adminService: adminService,
+        conf: conf,
     }
 }
 
-func (s *AuthServiceImpl) CreateTokenPair(id string) (*models.AdminAuth, error) {
-    var err error
+func (s *AuthServiceImpl) CreateTokenPair(ctx context.Context, id string) (_ *models.AdminAuth, err error) {
+    ctx, span := tracing.Start(ctx, "AuthService.CreateTokenPair")
+    defer func() { tracing.End(span, err) }()
 
     adminAuth := &models.AdminAuth{
         UUID: uuid.NewString(),
@@ -69,7 +75,7 @@ func (s *AuthServiceImpl) CreateTokenPair(id string) (*models.AdminAuth, error)
     atClaims["exp"] = time.Now().Add(time.Hour * 1).Unix()
     
     at := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims)
-    adminAuth.AccessToken, err = at.SignedString([]byte(os.Getenv("ACCESS_SECRET")))
+    adminAuth.AccessToken, err = at.SignedString([]byte(s.conf.AccessSecret))
     if err != nil {
         return nil, err
     }
@@ -80,18 +86,20 @@ func (s *AuthServiceImpl) CreateTokenPair(id string) (*models.AdminAuth, error)
     rtClaims["name"] = admin.Name
     rtClaims["exp"] = time.Now().Add(time.Hour * 24 * 3).Unix()
     rt := jwt.NewWithClaims(jwt.SigningMethodHS256, rtClaims)
-    adminAuth.RefreshToken, err = rt.SignedString([]byte(os.Getenv("REFRESH_SECRET")))
+    adminAuth.RefreshToken, err = rt.SignedString([]byte(s.conf.RefreshSecret))
     if err != nil {
         return nil, err
     }
-    err = s.authRepo.InsertAdminAuth(adminAuth)
+    err = s.authRepo.InsertAdminAuth(ctx, adminAuth)
     if err != nil {
         return nil, err
     }
     return adminAuth, nil
 }
 
-func (s *AuthServiceImpl) CreateAccessToken(uuid, id string) (string, error) {
+func (s *AuthServiceImpl) CreateAccessToken(ctx context.Context, uuid, id string) (_ string, err error) {
+    ctx, span := tracing.Start(ctx, "AuthService.CreateAccessToken")
+    defer func() { tracing.End(span, err) }()
     atClaims := jwt.MapClaims{}
 
     admin, err := s.adminService.GetAdmin(id)
@@ -103,24 +111,26 @@ func (s *AuthServiceImpl) CreateAccessToken(uuid, id string) (string, error) {
     atClaims["name"] = admin.Name
     atClaims["exp"] = time.Now().Add(time.Hour * 1).Unix()
     at := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims)
-    token, err := at.SignedString([]byte(os.Getenv("ACCESS_SECRET")))
+    token, err := at.SignedString([]byte(s.conf.AccessSecret))
     if err != nil {
         return "", err
     }
-    if err := s.authRepo.UpdateAccessToken(uuid, token); err != nil {
+    if err := s.authRepo.UpdateAccessToken(ctx, uuid, token); err != nil {
         return "", err
     }
     return token, nil
 }
 
-func (s *AuthServiceImpl) VerifyTokenPair(at, rt string) (string, error) {
+func (s *AuthServiceImpl) VerifyTokenPair(ctx context.Context, at, rt string) (_ string, err error) {
+    ctx, span := tracing.Start(ctx, "AuthService.VerifyTokenPair")
+    defer func() { tracing.End(span, err) }()
     rtClaims, err := s.VerifyRefreshToken(rt)
     if err != nil {
         return "", err
     }
     uuid := rtClaims["uuid"].(string)
     adminAuth := &models.AdminAuth{}
-    adminAuth, err = s.authRepo.GetAdminAuth(uuid)
+    adminAuth, err = s.authRepo.GetAdminAuth(ctx, uuid)
     if err != nil {
         return "", err
     }
@@ -138,7 +148,7 @@ func (s *AuthServiceImpl) VerifyAccessToken(token string) (jwt.MapClaims, error)
         if token.Method != jwt.SigningMethodHS256 {
             return nil, errors.New("Unexpected Signing Method")
         }
-        return []byte(os.Getenv("ACCESS_SECRET")), nil
+        return []byte(s.conf.AccessSecret), nil
     }
     _, err := jwt.ParseWithClaims(token, &claims, verifying)
     return claims, err
@@ -150,12 +160,14 @@ func (s *AuthServiceImpl) VerifyRefreshToken(token string) (jwt.MapClaims, error
         if token.Method != jwt.SigningMethodHS256 {
             return nil, errors.New("Unexpected Signing Method")
         }
-        return []byte(os.Getenv("REFRESH_SECRET")), nil
+        return []byte(s.conf.RefreshSecret), nil
     }
     _, err := jwt.ParseWithClaims(token, &claims, verifying)
     return claims, err
 }
 
-func (s *AuthServiceImpl) DeleteTokenPair(uuid string) error {
-    return s.authRepo.DeleteAdminAuth(uuid)
+func (s *AuthServiceImpl) DeleteTokenPair(ctx context.Context, uuid string) (err error) {
+    ctx, span := tracing.Start(ctx, "AuthService.DeleteTokenPair")
+    defer func() { tracing.End(span, err) }()
+    return s.authRepo.DeleteAdminAuth(ctx, uuid)
 }
@@ -1,10 +1,11 @@
 package services_test
 
 import (
-	"okra_board2/config"
+	"context"
 	"okra_board2/models"
 	"okra_board2/repositories"
 	"okra_board2/services"
+	"okra_board2/testutil"
 	"testing"
 	"time"
 
@@ -12,16 +13,13 @@ import (
 )
 
 func TestAuthService(t *testing.T) {
+    ctx := context.Background()
 
-    conf, err := config.LoadConfigTest()
-    if err != nil { assert.Error(t, err) }
-
-    db, err := config.InitDBConnection(conf)
-    if err != nil { assert.Error(t, err) }
+    db := testutil.NewDB(t)
     authRepo := repositories.NewAuthRepositoryImpl(db)
     adminRepo := repositories.NewAdminRepositoryImpl(db)
     adminService := services.NewAdminServiceImpl(adminRepo)
-    authService := services.NewAuthServiceImpl(authRepo, adminService)
+    authService := services.NewAuthServiceImpl(authRepo, adminService, testutil.NewConfig())
 
     admin := models.Admin {
         ID: "administrator11",
@@ -32,14 +30,14 @@ func TestAuthService(t *testing.T) {
     }
     adminService.Register(&admin)
 
-    auth, err := authService.CreateTokenPair("administrator11")
+    auth, err := authService.CreateTokenPair(ctx, "administrator11")
     if err != nil { assert.Error(t, err) }
     atClaims, err := authService.VerifyAccessToken(auth.AccessToken)
     if err != nil { assert.Error(t, err) }
     rtClaims, err := authService.VerifyRefreshToken(auth.RefreshToken)
     if err != nil { assert.Error(t, err) }
 
-    uuid, err := authService.VerifyTokenPair(auth.AccessToken, auth.RefreshToken)
+    uuid, err := authService.VerifyTokenPair(ctx, auth.AccessToken, auth.RefreshToken)
     assert.Equal(t, "administrator11", atClaims["id"].(string))
     assert.Equal(t, "강민석", atClaims["name"].(string))
     assert.Equal(t, "administrator11", rtClaims["id"].(string))
@@ -48,7 +46,7 @@ func TestAuthService(t *testing.T) {
 
     time.Sleep(time.Second * 1)
 
-    at, err := authService.CreateAccessToken(auth.UUID, auth.AdminID)
+    at, err := authService.CreateAccessToken(ctx, auth.UUID, auth.AdminID)
     atClaims, err = authService.VerifyAccessToken(at)
     assert.Equal(t, err, nil)
     assert.Equal(t, "administrator11", atClaims["id"].(string))
@@ -57,9 +55,9 @@ func TestAuthService(t *testing.T) {
 
     assert.NotEqual(t, at, auth.AccessToken)
 
-    _, err = authService.VerifyTokenPair(at, auth.RefreshToken)
+    _, err = authService.VerifyTokenPair(ctx, at, auth.RefreshToken)
     assert.Equal(t, err, nil)
-    _, err = authService.VerifyTokenPair(auth.AccessToken, auth.RefreshToken)
+    _, err = authService.VerifyTokenPair(ctx, auth.AccessToken, auth.RefreshToken)
     assert.EqualError(t, err, "Invalid Token Pair.")
     
 
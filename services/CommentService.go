@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"okra_board2/config"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"okra_board2/utils/encryption"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+var (
+    ErrCommentsDisabled     = errors.New("Comments are disabled on this board.")
+    ErrCommentRateLimited   = errors.New("Too many comments. Try again later.")
+    ErrWrongPassword        = errors.New("Password does not match.")
+)
+
+type CommentService interface {
+
+    // 공개된 게시물의 승인된 댓글을 답글과 함께 작성 순으로 불러온다.
+    // 게시물이 없거나 공개되지 않은 경우 gorm.ErrRecordNotFound를 반환한다.
+    GetComments(ctx context.Context, postId int) (comments []models.Comment, err error)
+
+    // 공개된 게시물에 댓글을 작성하고 commentId와 유효성 검사 결과 및 에러를 반환한다.
+    // 스팸으로 분류된 댓글은 spam 상태로, 그 외의 댓글은 설정에 따라 approved 또는 pending 상태로 저장된다.
+    // 게시판의 댓글이 비활성화된 경우 ErrCommentsDisabled를,
+    // 같은 IP에서 짧은 시간에 너무 많은 댓글을 작성한 경우 ErrCommentRateLimited를 반환한다.
+    WriteComment(ctx context.Context, comment *models.Comment) (commentId int, result *models.CommentValidationResult, err error)
+
+    // 비밀번호를 확인한 후 댓글의 닉네임과 내용을 수정한다.
+    // 수정된 댓글은 작성할 때와 같은 기준으로 다시 분류되며, 거절되거나 스팸으로 분류된 댓글은 상태가 유지된다.
+    // 비밀번호가 일치하지 않을 경우 ErrWrongPassword를 반환한다.
+    UpdateComment(ctx context.Context, comment *models.Comment) (result *models.CommentValidationResult, err error)
+
+    // 비밀번호를 확인한 후 댓글과 답글을 삭제한다.
+    // 비밀번호가 일치하지 않을 경우 ErrWrongPassword를 반환한다.
+    DeleteComment(ctx context.Context, postId, commentId int, password string) (err error)
+
+    // 관리자 검토를 위해 댓글을 최신순으로 불러온다. status가 nil일 경우 모든 댓글을 불러온다.
+    GetModerationQueue(
+        ctx context.Context,
+        status *string,
+        page, size int,
+    )                               (comments []models.Comment, count int)
+
+    // 댓글들의 상태를 한 번에 변경하고 변경된 댓글의 수를 반환한다.
+    ModerateComments(
+        ctx context.Context,
+        commentIds []int,
+        status string,
+    )                               (updated int64, result *models.CommentValidationResult, err error)
+
+    // 댓글들과 그 답글들을 한 번에 삭제하고 삭제된 댓글의 수를 반환한다.
+    DeleteComments(ctx context.Context, commentIds []int) (deleted int64, err error)
+
+    // 게시판의 댓글 사용 여부를 설정한다.
+    SetCommentEnabled(ctx context.Context, boardId int, enabled bool) (err error)
+
+}
+
+type CommentServiceImpl struct {
+    commentRepo repositories.CommentRepository
+    postRepo    repositories.PostRepository
+    conf        *config.Config
+}
+
+func NewCommentServiceImpl(
+    commentRepo repositories.CommentRepository,
+    postRepo repositories.PostRepository,
+    conf *config.Config,
+) CommentService {
+    return &CommentServiceImpl{
+        commentRepo: commentRepo,
+        postRepo: postRepo,
+        conf: conf,
+    }
+}
+
+var linkPattern = regexp.MustCompile(`(?i)https?://|www\.`)
+
+func (s *CommentServiceImpl) rateLimit() int {
+    if s.conf.Comment.RateLimit <= 0 {
+        return 5
+    }
+    return s.conf.Comment.RateLimit
+}
+
+func (s *CommentServiceImpl) rateWindow() time.Duration {
+    if s.conf.Comment.RateWindow <= 0 {
+        return time.Minute
+    }
+    return time.Duration(s.conf.Comment.RateWindow) * time.Second
+}
+
+func (s *CommentServiceImpl) maxLinks() int {
+    if s.conf.Comment.MaxLinks <= 0 {
+        return 2
+    }
+    return s.conf.Comment.MaxLinks
+}
+
+// 링크가 너무 많거나 차단된 단어가 포함된 댓글을 스팸으로 분류한다.
+func (s *CommentServiceImpl) isSpam(comment *models.Comment) bool {
+    text := comment.Nickname + " " + comment.Content
+    if len(linkPattern.FindAllStringIndex(text, -1)) > s.maxLinks() {
+        return true
+    }
+    lower := strings.ToLower(text)
+    for _, keyword := range s.conf.Comment.BlockedKeywords {
+        if keyword != "" && strings.Contains(lower, strings.ToLower(keyword)) {
+            return true
+        }
+    }
+    return false
+}
+
+// 새로 작성되거나 수정된 댓글의 상태를 결정한다.
+func (s *CommentServiceImpl) classify(comment *models.Comment) string {
+    if s.isSpam(comment) {
+        return models.CommentStatusSpam
+    }
+    if s.conf.Comment.AutoApprove {
+        return models.CommentStatusApproved
+    }
+    return models.CommentStatusPending
+}
+
+// Validate comment nickname. If valid, it returns nil.
+func (s *CommentServiceImpl) checkNickname(nickname string) *string {
+    var msg string
+    if length := utf8.RuneCountInString(nickname); length < 1 || length > 30 {
+        msg = "닉네임은 1~30자로 입력하세요."
+    } else {
+        return nil
+    }
+    return &msg
+}
+
+// Validate comment password. If valid, it returns nil.
+func (s *CommentServiceImpl) checkPassword(password string) *string {
+    var msg string
+    if length := utf8.RuneCountInString(password); length < 4 || length > 64 {
+        msg = "비밀번호는 4~64자로 입력하세요."
+    } else {
+        return nil
+    }
+    return &msg
+}
+
+// Validate comment content. If valid, it returns nil.
+func (s *CommentServiceImpl) checkContent(content string) *string {
+    var msg string
+    if length := utf8.RuneCountInString(content); length < 1 || length > 1000 {
+        msg = "댓글은 1~1000자로 입력하세요."
+    } else {
+        return nil
+    }
+    return &msg
+}
+
+// 답글의 원 댓글을 검사한다. 원 댓글은 같은 게시물의 승인된 댓글이어야 하며, 답글이 아니어야 한다.
+func (s *CommentServiceImpl) checkParent(ctx context.Context, postId int, parentId *int) *string {
+    var msg string
+    if parentId == nil {
+        return nil
+    }
+    parent, err := s.commentRepo.GetComment(ctx, *parentId)
+    if err != nil || parent.PostID != postId || parent.Status != models.CommentStatusApproved {
+        msg = "답글을 달 댓글이 존재하지 않습니다."
+    } else if parent.ParentID != nil {
+        msg = "답글에는 답글을 달 수 없습니다."
+    } else {
+        return nil
+    }
+    return &msg
+}
+
+func (s *CommentServiceImpl) checkStatus(status string) *string {
+    var msg string
+    switch status {
+    case models.CommentStatusPending, models.CommentStatusApproved,
+        models.CommentStatusRejected, models.CommentStatusSpam:
+        return nil
+    default:
+        msg = "pending, approved, rejected, spam 중 하나를 입력하세요."
+    }
+    return &msg
+}
+
+// 댓글을 작성할 수 있는 공개된 게시물인지 확인한다.
+func (s *CommentServiceImpl) checkPost(ctx context.Context, postId int) error {
+    status := true
+    post, err := s.postRepo.GetPost(ctx, &status, postId)
+    if err != nil { return err }
+    if !s.commentRepo.IsCommentEnabled(ctx, post.BoardID) {
+        return ErrCommentsDisabled
+    }
+    return nil
+}
+
+func (s *CommentServiceImpl) GetComments(ctx context.Context, postId int) (comments []models.Comment, err error) {
+    status := true
+    if _, err = s.postRepo.GetPost(ctx, &status, postId); err != nil { return }
+
+    approved := models.CommentStatusApproved
+    all := s.commentRepo.GetComments(ctx, postId, &approved)
+
+    comments = make([]models.Comment, 0)
+    index := make(map[int]int)
+    for _, comment := range all {
+        comment.Password = ""
+        if comment.ParentID == nil {
+            index[comment.CommentID] = len(comments)
+            comments = append(comments, comment)
+        }
+    }
+    for _, comment := range all {
+        if comment.ParentID == nil { continue }
+        if i, ok := index[*comment.ParentID]; ok {
+            comment.Password = ""
+            comments[i].Replies = append(comments[i].Replies, comment)
+        }
+    }
+    return
+}
+
+func (s *CommentServiceImpl) WriteComment(
+    ctx context.Context,
+    comment *models.Comment,
+) (commentId int, result *models.CommentValidationResult, err error) {
+    if err = s.checkPost(ctx, comment.PostID); err != nil { return }
+
+    comment.Nickname = strings.TrimSpace(comment.Nickname)
+    comment.Content = strings.TrimSpace(comment.Content)
+    result = (&models.CommentValidationResult {
+        ParentID: s.checkParent(ctx, comment.PostID, comment.ParentID),
+        Nickname: s.checkNickname(comment.Nickname),
+        Password: s.checkPassword(comment.Password),
+        Content: s.checkContent(comment.Content),
+    }).GetOrNil()
+    if result != nil { return }
+
+    if s.commentRepo.CountRecentComments(ctx, comment.IP, time.Now().Add(-s.rateWindow())) >= s.rateLimit() {
+        return 0, nil, ErrCommentRateLimited
+    }
+
+    comment.Password = encryption.EncryptSHA256(comment.Password)
+    comment.Status = s.classify(comment)
+    commentId, err = s.commentRepo.InsertComment(ctx, comment)
+    comment.Password = ""
+    return
+}
+
+// 게시물의 댓글을 불러와 비밀번호를 확인한다.
+func (s *CommentServiceImpl) authorize(ctx context.Context, postId, commentId int, password string) (*models.Comment, error) {
+    comment, err := s.commentRepo.GetComment(ctx, commentId)
+    if err != nil { return nil, err }
+    if comment.PostID != postId {
+        return nil, gorm.ErrRecordNotFound
+    }
+    if comment.Password != encryption.EncryptSHA256(password) {
+        return nil, ErrWrongPassword
+    }
+    return comment, nil
+}
+
+func (s *CommentServiceImpl) UpdateComment(
+    ctx context.Context,
+    comment *models.Comment,
+) (result *models.CommentValidationResult, err error) {
+    if err = s.checkPost(ctx, comment.PostID); err != nil { return }
+
+    prev, err := s.authorize(ctx, comment.PostID, comment.CommentID, comment.Password)
+    if err != nil { return }
+
+    comment.Nickname = strings.TrimSpace(comment.Nickname)
+    comment.Content = strings.TrimSpace(comment.Content)
+    if comment.Nickname == "" {
+        comment.Nickname = prev.Nickname
+    }
+    result = (&models.CommentValidationResult {
+        Nickname: s.checkNickname(comment.Nickname),
+        Content: s.checkContent(comment.Content),
+    }).GetOrNil()
+    if result != nil { return }
+
+    // 거절되거나 스팸으로 분류된 댓글은 수정하더라도 상태가 유지된다.
+    comment.Status = s.classify(comment)
+    if prev.Status == models.CommentStatusRejected || prev.Status == models.CommentStatusSpam {
+        comment.Status = prev.Status
+    }
+    err = s.commentRepo.UpdateComment(ctx, comment)
+    comment.Password = ""
+    return
+}
+
+func (s *CommentServiceImpl) DeleteComment(ctx context.Context, postId, commentId int, password string) (err error) {
+    if _, err = s.authorize(ctx, postId, commentId, password); err != nil { return }
+    _, err = s.commentRepo.DeleteComments(ctx, []int{commentId})
+    return
+}
+
+func (s *CommentServiceImpl) GetModerationQueue(
+    ctx context.Context,
+    status *string,
+    page, size int,
+) (comments []models.Comment, count int) {
+    comments, count = s.commentRepo.GetCommentsByStatus(ctx, status, page, size)
+    for i := range comments {
+        comments[i].Password = ""
+    }
+    return
+}
+
+func (s *CommentServiceImpl) ModerateComments(
+    ctx context.Context,
+    commentIds []int,
+    status string,
+) (updated int64, result *models.CommentValidationResult, err error) {
+    result = (&models.CommentValidationResult {
+        Status: s.checkStatus(status),
+    }).GetOrNil()
+    if result != nil || len(commentIds) == 0 { return }
+
+    updated, err = s.commentRepo.UpdateCommentStatus(ctx, commentIds, status)
+    return
+}
+
+func (s *CommentServiceImpl) DeleteComments(ctx context.Context, commentIds []int) (deleted int64, err error) {
+    if len(commentIds) == 0 { return }
+    return s.commentRepo.DeleteComments(ctx, commentIds)
+}
+
+func (s *CommentServiceImpl) SetCommentEnabled(ctx context.Context, boardId int, enabled bool) (err error) {
+    return s.commentRepo.SetCommentEnabled(ctx, boardId, enabled)
+}
@@ -0,0 +1,156 @@
+package services_test
+
+import (
+	"context"
+	"okra_board2/config"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"okra_board2/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type commentRepositoryStub struct {
+    repositories.CommentRepository
+    comments []models.Comment
+    disabled map[int]bool
+}
+
+func (r *commentRepositoryStub) GetComment(ctx context.Context, commentId int) (*models.Comment, error) {
+    for i := range r.comments {
+        if r.comments[i].CommentID == commentId {
+            comment := r.comments[i]
+            return &comment, nil
+        }
+    }
+    return nil, gorm.ErrRecordNotFound
+}
+
+func (r *commentRepositoryStub) GetComments(ctx context.Context, postId int, status *string) (comments []models.Comment) {
+    for _, comment := range r.comments {
+        if comment.PostID == postId && (status == nil || comment.Status == *status) {
+            comments = append(comments, comment)
+        }
+    }
+    return
+}
+
+func (r *commentRepositoryStub) InsertComment(ctx context.Context, comment *models.Comment) (int, error) {
+    comment.CommentID = len(r.comments) + 1
+    comment.AddedDate = time.Now()
+    r.comments = append(r.comments, *comment)
+    return comment.CommentID, nil
+}
+
+func (r *commentRepositoryStub) UpdateComment(ctx context.Context, comment *models.Comment) error {
+    for i := range r.comments {
+        if r.comments[i].CommentID == comment.CommentID {
+            r.comments[i].Nickname = comment.Nickname
+            r.comments[i].Content = comment.Content
+            r.comments[i].Status = comment.Status
+        }
+    }
+    return nil
+}
+
+func (r *commentRepositoryStub) UpdateCommentStatus(ctx context.Context, commentIds []int, status string) (updated int64, err error) {
+    for _, id := range commentIds {
+        for i := range r.comments {
+            if r.comments[i].CommentID == id {
+                r.comments[i].Status = status
+                updated++
+            }
+        }
+    }
+    return
+}
+
+func (r *commentRepositoryStub) CountRecentComments(ctx context.Context, ip string, since time.Time) (count int) {
+    for _, comment := range r.comments {
+        if comment.IP == ip && !comment.AddedDate.Before(since) { count++ }
+    }
+    return
+}
+
+func (r *commentRepositoryStub) IsCommentEnabled(ctx context.Context, boardId int) bool {
+    return !r.disabled[boardId]
+}
+
+func TestCommentService(t *testing.T) {
+    repo := &commentRepositoryStub{ disabled: map[int]bool{ 2: true } }
+    postRepo := &singlePostRepositoryStub{ post: &models.Post{ PostID: 1, BoardID: 1, Status: true } }
+    conf := &config.Config{ Comment: config.CommentConfig{
+        RateLimit: 3, BlockedKeywords: []string{"casino"},
+    }}
+    s := services.NewCommentServiceImpl(repo, postRepo, conf)
+
+    write := func(comment models.Comment) (int, *models.CommentValidationResult, error) {
+        comment.PostID = 1
+        comment.IP = "127.0.0.1"
+        return s.WriteComment(context.Background(), &comment)
+    }
+
+    // 새 댓글은 검토 대기 상태로 저장된다.
+    commentId, result, err := write(models.Comment{ Nickname: "okra", Password: "1234", Content: "좋은 글이네요" })
+    assert.NoError(t, err)
+    assert.Nil(t, result)
+    assert.Equal(t, models.CommentStatusPending, repo.comments[0].Status)
+    assert.NotEqual(t, "1234", repo.comments[0].Password)
+
+    // 링크가 많거나 차단된 단어가 포함된 댓글은 스팸으로 분류된다.
+    write(models.Comment{ Nickname: "bot", Password: "1234", Content: "http://a.com http://b.com http://c.com" })
+    write(models.Comment{ Nickname: "bot", Password: "1234", Content: "Best CASINO" })
+    assert.Equal(t, models.CommentStatusSpam, repo.comments[1].Status)
+    assert.Equal(t, models.CommentStatusSpam, repo.comments[2].Status)
+
+    // 같은 IP에서 너무 많은 댓글을 작성할 수 없다.
+    _, _, err = write(models.Comment{ Nickname: "okra", Password: "1234", Content: "again" })
+    assert.Equal(t, services.ErrCommentRateLimited, err)
+    repo.comments[1].IP, repo.comments[2].IP = "", ""
+
+    // 승인되지 않은 댓글에는 답글을 달 수 없다.
+    _, result, _ = write(models.Comment{ ParentID: &commentId, Nickname: "okra", Password: "1234", Content: "reply" })
+    assert.NotNil(t, result.ParentID)
+
+    updated, result, err := s.ModerateComments(context.Background(), []int{commentId}, models.CommentStatusApproved)
+    assert.NoError(t, err)
+    assert.Nil(t, result)
+    assert.Equal(t, int64(1), updated)
+    _, result, _ = s.ModerateComments(context.Background(), []int{commentId}, "deleted")
+    assert.NotNil(t, result.Status)
+
+    replyId, result, err := write(models.Comment{ ParentID: &commentId, Nickname: "reply", Password: "1234", Content: "reply" })
+    assert.NoError(t, err)
+    assert.Nil(t, result)
+    s.ModerateComments(context.Background(), []int{replyId}, models.CommentStatusApproved)
+
+    // 답글에는 답글을 달 수 없다.
+    repo.comments[0].IP = ""
+    _, result, _ = write(models.Comment{ ParentID: &replyId, Nickname: "okra", Password: "1234", Content: "reply" })
+    assert.NotNil(t, result.ParentID)
+
+    // 승인된 댓글만 답글과 함께 공개된다.
+    comments, err := s.GetComments(context.Background(), 1)
+    assert.NoError(t, err)
+    assert.Len(t, comments, 1)
+    assert.Len(t, comments[0].Replies, 1)
+    assert.Empty(t, comments[0].Password)
+
+    // 수정, 삭제 시 비밀번호를 확인한다.
+    _, err = s.UpdateComment(context.Background(), &models.Comment{ PostID: 1, CommentID: commentId, Password: "wrong", Content: "edited" })
+    assert.Equal(t, services.ErrWrongPassword, err)
+    _, err = s.UpdateComment(context.Background(), &models.Comment{ PostID: 1, CommentID: commentId, Password: "1234", Content: "edited" })
+    assert.NoError(t, err)
+    assert.Equal(t, "edited", repo.comments[0].Content)
+    assert.Equal(t, "okra", repo.comments[0].Nickname)
+    assert.Equal(t, models.CommentStatusPending, repo.comments[0].Status)
+    assert.Equal(t, services.ErrWrongPassword, s.DeleteComment(context.Background(), 1, commentId, "wrong"))
+
+    // 댓글이 비활성화된 게시판에는 댓글을 작성할 수 없다.
+    postRepo.post.BoardID = 2
+    _, _, err = write(models.Comment{ Nickname: "okra", Password: "1234", Content: "hello" })
+    assert.Equal(t, services.ErrCommentsDisabled, err)
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"regexp"
+	"time"
+)
+
+type FeaturedSlotService interface {
+
+    // 현재 노출 중인 게시물들을 슬롯 순서대로 불러온다.
+    GetFeaturedPosts(ctx context.Context, group string) (posts []models.FeaturedPost)
+
+    // 예약된 슬롯을 포함하여 그룹의 모든 슬롯을 순서대로 불러온다.
+    GetSlots(ctx context.Context, group string) (slots []models.FeaturedSlot)
+
+    // 그룹의 슬롯 목록을 유효성 검사 후 한 번에 교체한다.
+    // 슬롯의 순서는 목록에서의 순서를 따른다.
+    // 유효하지 않은 슬롯이 있을 경우 유효성 검사 결과를 반환하며, 슬롯은 변경되지 않는다.
+    ReplaceSlots(
+        ctx context.Context,
+        group string,
+        slots []models.FeaturedSlot,
+    )                                   (result *models.FeaturedSlotValidationResult, err error)
+
+    // 그룹 내 슬롯의 순서를 slotIds의 순서대로 한 번에 변경한다.
+    // slotIds는 그룹의 모든 슬롯을 빠짐없이 포함해야 한다.
+    ReorderSlots(
+        ctx context.Context,
+        group string,
+        slotIds []int,
+    )                                   (result *models.FeaturedSlotValidationResult, err error)
+
+}
+
+type FeaturedSlotServiceImpl struct {
+    slotRepo    repositories.FeaturedSlotRepository
+    postRepo    repositories.PostRepository
+}
+
+func NewFeaturedSlotServiceImpl(
+    slotRepo repositories.FeaturedSlotRepository,
+    postRepo repositories.PostRepository,
+) FeaturedSlotService {
+    return &FeaturedSlotServiceImpl{
+        slotRepo: slotRepo,
+        postRepo: postRepo,
+    }
+}
+
+// Validate slot group name. If valid, it returns nil.
+func (s *FeaturedSlotServiceImpl) checkGroup(group string) *string {
+    var msg string
+    if match, _ := regexp.MatchString("^[a-z0-9_-]{1,50}$", group); !match {
+        msg = "슬롯 그룹 이름은 50자 이하의 영소문자, 숫자, '-', '_'로 이루어져야 합니다."
+    } else {
+        return nil
+    }
+    return &msg
+}
+
+// Validate slot. If valid, it returns nil.
+func (s *FeaturedSlotServiceImpl) checkSlot(ctx context.Context, slot *models.FeaturedSlot) *string {
+    var msg string
+    if !s.postRepo.CheckPostExists(ctx, slot.PostID) {
+        msg = "존재하지 않는 게시물입니다."
+    } else if slot.StartDate != nil && slot.EndDate != nil && !slot.EndDate.After(*slot.StartDate) {
+        msg = "노출 종료 시각은 시작 시각 이후여야 합니다."
+    } else {
+        return nil
+    }
+    return &msg
+}
+
+func (s *FeaturedSlotServiceImpl) GetFeaturedPosts(ctx context.Context, group string) []models.FeaturedPost {
+    return s.slotRepo.GetActiveSlots(ctx, group, time.Now())
+}
+
+func (s *FeaturedSlotServiceImpl) GetSlots(ctx context.Context, group string) []models.FeaturedSlot {
+    return s.slotRepo.GetSlots(ctx, group)
+}
+
+func (s *FeaturedSlotServiceImpl) ReplaceSlots(
+    ctx context.Context,
+    group string,
+    slots []models.FeaturedSlot,
+) (result *models.FeaturedSlotValidationResult, err error) {
+    result = &models.FeaturedSlotValidationResult{
+        Group: s.checkGroup(group),
+        Slots: make(map[int]string),
+    }
+    for i := range slots {
+        if msg := s.checkSlot(ctx, &slots[i]); msg != nil {
+            result.Slots[i] = *msg
+        }
+    }
+    if result = result.GetOrNil(); result != nil {
+        return
+    }
+    err = s.slotRepo.ReplaceSlots(ctx, group, slots)
+    return
+}
+
+func (s *FeaturedSlotServiceImpl) ReorderSlots(
+    ctx context.Context,
+    group string,
+    slotIds []int,
+) (result *models.FeaturedSlotValidationResult, err error) {
+    result = &models.FeaturedSlotValidationResult{
+        Group: s.checkGroup(group),
+        Slots: make(map[int]string),
+    }
+
+    existing := make(map[int]bool)
+    for _, slot := range s.slotRepo.GetSlots(ctx, group) {
+        existing[slot.SlotID] = false
+    }
+    for i, slotId := range slotIds {
+        if ordered, ok := existing[slotId]; !ok {
+            result.Slots[i] = "그룹에 존재하지 않는 슬롯입니다."
+        } else if ordered {
+            result.Slots[i] = "중복된 슬롯입니다."
+        } else {
+            existing[slotId] = true
+        }
+    }
+    if result.Group == nil && len(result.Slots) == 0 && len(slotIds) != len(existing) {
+        msg := "그룹의 모든 슬롯의 순서를 지정해야 합니다."
+        result.Group = &msg
+    }
+    if result = result.GetOrNil(); result != nil {
+        return
+    }
+    err = s.slotRepo.ReorderSlots(ctx, group, slotIds)
+    return
+}
@@ -0,0 +1,85 @@
+package services_test
+
+import (
+	"context"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"okra_board2/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type featuredSlotRepositoryStub struct {
+    repositories.FeaturedSlotRepository
+    slots []models.FeaturedSlot
+}
+
+func (r *featuredSlotRepositoryStub) GetSlots(ctx context.Context, group string) []models.FeaturedSlot {
+    return r.slots
+}
+
+func (r *featuredSlotRepositoryStub) ReplaceSlots(ctx context.Context, group string, slots []models.FeaturedSlot) error {
+    r.slots = slots
+    for i := range r.slots {
+        r.slots[i].SlotID = i + 1
+        r.slots[i].Position = i
+    }
+    return nil
+}
+
+func (r *featuredSlotRepositoryStub) ReorderSlots(ctx context.Context, group string, slotIds []int) error {
+    for position, slotId := range slotIds {
+        r.slots[slotId - 1].Position = position
+    }
+    return nil
+}
+
+type existingPostRepositoryStub struct {
+    repositories.PostRepository
+}
+
+func (r *existingPostRepositoryStub) CheckPostExists(ctx context.Context, postId int) bool {
+    return postId < 100
+}
+
+func TestFeaturedSlotService(t *testing.T) {
+    slotRepo := &featuredSlotRepositoryStub{}
+    s := services.NewFeaturedSlotServiceImpl(slotRepo, &existingPostRepositoryStub{})
+
+    start := time.Now()
+    end := start.Add(-time.Hour)
+
+    // invalid group, post and schedule
+    result, err := s.ReplaceSlots(context.Background(), "Main!", []models.FeaturedSlot {
+        { PostID: 1 },
+        { PostID: 100 },
+        { PostID: 2, StartDate: &start, EndDate: &end },
+    })
+    assert.Nil(t, err)
+    assert.NotNil(t, result.Group)
+    assert.Equal(t, 2, len(result.Slots))
+    assert.Equal(t, 0, len(slotRepo.slots))
+
+    result, err = s.ReplaceSlots(context.Background(), "main", []models.FeaturedSlot {
+        { PostID: 3 }, { PostID: 1 }, { PostID: 2 },
+    })
+    assert.Nil(t, err)
+    assert.Nil(t, result)
+    assert.Equal(t, 3, len(slotRepo.slots))
+
+    // 모든 슬롯을 빠짐없이 한 번씩 지정해야 한다.
+    result, _ = s.ReorderSlots(context.Background(), "main", []int{3, 1})
+    assert.NotNil(t, result.Group)
+    result, _ = s.ReorderSlots(context.Background(), "main", []int{3, 1, 1})
+    assert.Equal(t, 1, len(result.Slots))
+    result, _ = s.ReorderSlots(context.Background(), "main", []int{3, 1, 4})
+    assert.Equal(t, 1, len(result.Slots))
+
+    result, err = s.ReorderSlots(context.Background(), "main", []int{3, 1, 2})
+    assert.Nil(t, err)
+    assert.Nil(t, result)
+    assert.Equal(t, 0, slotRepo.slots[2].Position)
+    assert.Equal(t, 1, slotRepo.slots[0].Position)
+}
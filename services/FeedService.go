@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"okra_board2/config"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"okra_board2/utils/feed"
+	"okra_board2/utils/htmltext"
+	"strconv"
+	"time"
+)
+
+type FeedService interface {
+
+    // 최근 게시된 공개 게시물들로 피드를 생성한다.
+    // boardId 속성이 nil이 아닐 경우 해당 게시판의 게시물만을 포함한다.
+    // tagSlug 속성이 nil이 아닐 경우 해당 태그가 달린 게시물만을 포함하며,
+    // 존재하지 않는 태그일 경우 gorm.ErrRecordNotFound를 반환한다.
+    // full 속성이 true일 경우 게시물의 본문을 포함한다.
+    // feedURL은 피드 자신의 주소이다.
+    GetFeed(
+        ctx context.Context,
+        boardId *int,
+        tagSlug *string,
+        full bool,
+        feedURL string,
+    )                               (f *feed.Feed, err error)
+
+}
+
+type FeedServiceImpl struct {
+    postRepo    repositories.PostRepository
+    tagRepo     repositories.TagRepository
+    conf        *config.Config
+}
+
+func NewFeedServiceImpl(
+    postRepo repositories.PostRepository,
+    tagRepo repositories.TagRepository,
+    conf *config.Config,
+) FeedService {
+    return &FeedServiceImpl{
+        postRepo: postRepo,
+        tagRepo: tagRepo,
+        conf: conf,
+    }
+}
+
+func (s *FeedServiceImpl) size() int {
+    if s.conf.Feed.Size <= 0 {
+        return 20
+    }
+    return s.conf.Feed.Size
+}
+
+func (s *FeedServiceImpl) summaryLength() int {
+    if s.conf.Feed.SummaryLength <= 0 {
+        return 200
+    }
+    return s.conf.Feed.SummaryLength
+}
+
+// 게시물을 피드 항목으로 변환한다.
+func (s *FeedServiceImpl) feedItem(post *models.Post, full bool) feed.Item {
+    published := post.AddedDate
+    if post.PublishedDate != nil {
+        published = *post.PublishedDate
+    }
+    updated := published
+    if post.UpdatedDate != nil && post.UpdatedDate.After(published) {
+        updated = *post.UpdatedDate
+    }
+
+    item := feed.Item{
+        ID: strconv.Itoa(post.PostID),
+        Title: post.Title,
+        Link: postURL(s.conf, post),
+        Summary: htmltext.Truncate(htmltext.ExtractText(post.Content), s.summaryLength()),
+        Published: published,
+        Updated: updated,
+        Image: htmltext.FirstImageSrc(post.Thumbnail),
+    }
+    if full {
+        item.Content = post.Content
+    }
+    for _, tag := range post.Tags {
+        item.Categories = append(item.Categories, tag.Name)
+    }
+    return item
+}
+
+func (s *FeedServiceImpl) GetFeed(
+    ctx context.Context,
+    boardId *int,
+    tagSlug *string,
+    full bool,
+    feedURL string,
+) (f *feed.Feed, err error) {
+    f = &feed.Feed{
+        Title: s.conf.Site.Title,
+        Description: s.conf.Site.Description,
+        Link: s.conf.Site.URL,
+        FeedURL: feedURL,
+        Language: s.conf.Site.Language,
+    }
+
+    var tagId *int
+    if tagSlug != nil {
+        tag, err := s.tagRepo.GetTagBySlug(ctx, *tagSlug)
+        if err != nil { return nil, err }
+        tagId = &tag.TagID
+        f.Title = fmt.Sprintf("%s - #%s", f.Title, tag.Name)
+    }
+    if boardId != nil {
+        boardName, ok := s.conf.Site.BoardNames[*boardId]
+        if !ok {
+            boardName = strconv.Itoa(*boardId)
+        }
+        f.Title = fmt.Sprintf("%s - %s", f.Title, boardName)
+    }
+
+    posts := s.postRepo.GetRecentPublishedPosts(ctx, boardId, tagId, s.size())
+    for i := range posts {
+        item := s.feedItem(&posts[i], full)
+        if item.Updated.After(f.Updated) {
+            f.Updated = item.Updated
+        }
+        f.Items = append(f.Items, item)
+    }
+    if f.Updated.IsZero() {
+        f.Updated = time.Unix(0, 0)
+    }
+    return
+}
@@ -0,0 +1,87 @@
+package services_test
+
+import (
+	"context"
+	"okra_board2/config"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"okra_board2/services"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type recentPostRepositoryStub struct {
+    repositories.PostRepository
+    posts []models.Post
+}
+
+func (r *recentPostRepositoryStub) GetRecentPublishedPosts(ctx context.Context, boardId *int, tagId *int, size int) (posts []models.Post) {
+    for _, post := range r.posts {
+        if boardId != nil && post.BoardID != *boardId { continue }
+        posts = append(posts, post)
+    }
+    return
+}
+
+type slugTagRepositoryStub struct {
+    repositories.TagRepository
+}
+
+func (r *slugTagRepositoryStub) GetTagBySlug(ctx context.Context, slug string) (*models.Tag, error) {
+    if slug == "okra" {
+        return &models.Tag{ TagID: 1, Name: "Okra", Slug: "okra" }, nil
+    }
+    return nil, gorm.ErrRecordNotFound
+}
+
+func TestFeedService(t *testing.T) {
+    published := time.Date(2022, 6, 1, 9, 0, 0, 0, time.UTC)
+    updated := published.Add(time.Hour)
+    postRepo := &recentPostRepositoryStub{
+        posts: []models.Post {
+            {
+                PostID: 2, BoardID: 1, Title: "second",
+                Thumbnail: `<p><img src="https://cdn.okraseoul.com/images/a.jpg"/></p>`,
+                Content: "<p>" + strings.Repeat("오크라 ", 100) + "</p>",
+                PublishedDate: &published, UpdatedDate: &updated,
+                Tags: []models.Tag{{ Name: "Okra" }},
+            },
+            { PostID: 1, BoardID: 2, Title: "first", Content: "<p>hello</p>", AddedDate: published },
+        },
+    }
+    conf := &config.Config{
+        Site: config.SiteConfig{ Title: "Okra", URL: "https://okraseoul.com/", PostPath: "/posts/{postId}" },
+        Feed: config.FeedConfig{ SummaryLength: 20 },
+    }
+    s := services.NewFeedServiceImpl(postRepo, &slugTagRepositoryStub{}, conf)
+
+    f, err := s.GetFeed(context.Background(), nil, nil, false, "https://api.okraseoul.com/feed.rss")
+    assert.Nil(t, err)
+    assert.Equal(t, 2, len(f.Items))
+    assert.Equal(t, updated, f.Updated)
+
+    item := f.Items[0]
+    assert.Equal(t, "https://okraseoul.com/posts/2", item.Link)
+    assert.Equal(t, "https://cdn.okraseoul.com/images/a.jpg", item.Image)
+    assert.Equal(t, []string{"Okra"}, item.Categories)
+    assert.True(t, strings.HasSuffix(item.Summary, "…"))
+    assert.Equal(t, "", item.Content)
+
+    f, _ = s.GetFeed(context.Background(), nil, nil, true, "")
+    assert.Equal(t, "<p>hello</p>", f.Items[1].Content)
+
+    boardId := 2
+    slug := "okra"
+    f, err = s.GetFeed(context.Background(), &boardId, &slug, false, "")
+    assert.Nil(t, err)
+    assert.Equal(t, "Okra - #Okra - 2", f.Title)
+    assert.Equal(t, 1, len(f.Items))
+
+    slug = "unknown"
+    _, err = s.GetFeed(context.Background(), nil, &slug, false, "")
+    assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"okra_board2/config"
+	"okra_board2/logging"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// 준비 상태 확인 시 이미지 저장소에 기록하는 객체
+const healthCheckKey = "healthcheck/readyz"
+
+// 각 확인 항목의 최대 대기 시간
+const healthCheckTimeout = 3 * time.Second
+
+// 확인 결과를 재사용하는 기본 시간
+const defaultReadyCacheTTL = 5 * time.Second
+
+type HealthService interface {
+
+    // db 연결, 이미지 저장소의 쓰기, 백그라운드 작업 상태를 동시에 확인한다.
+    // 하나라도 실패하거나 종료가 시작된 경우 report.Status는 ok가 아니다.
+    // 확인 결과는 conf.Server.ReadyCacheTTL 동안 재사용되며, 실패한 항목의 에러는 응답에 포함되지 않고 로그에만 기록된다.
+    Ready(ctx context.Context)      (report *models.HealthReport)
+
+    // 종료가 시작되었음을 기록한다. 이후 Ready는 항상 실패한다.
+    Shutdown()
+
+}
+
+type HealthServiceImpl struct {
+    healthRepo      repositories.HealthRepository
+    rankingService  RankingService
+    conf            *config.Config
+    client          *s3.Client
+    shuttingDown    atomic.Bool
+
+    // 마지막 확인 결과와 확인한 시각
+    mutex           sync.Mutex
+    checks          []models.HealthCheck
+    checkedAt       time.Time
+}
+
+func NewHealthServiceImpl(
+    healthRepo repositories.HealthRepository,
+    rankingService RankingService,
+    conf *config.Config,
+    client *s3.Client,
+) HealthService {
+    return &HealthServiceImpl{
+        healthRepo: healthRepo,
+        rankingService: rankingService,
+        conf: conf,
+        client: client,
+    }
+}
+
+func (s *HealthServiceImpl) Shutdown() {
+    s.shuttingDown.Store(true)
+}
+
+func (s *HealthServiceImpl) Ready(ctx context.Context) *models.HealthReport {
+    report := &models.HealthReport{
+        Status: models.HealthStatusOK,
+        Checks: s.runChecks(ctx),
+    }
+    for _, check := range report.Checks {
+        if check.Status != models.HealthStatusOK {
+            report.Status = models.HealthStatusFail
+        }
+    }
+    if s.shuttingDown.Load() {
+        report.Status = models.HealthStatusShutdown
+    }
+    return report
+}
+
+// 모든 항목을 동시에 확인한다. 마지막 확인 후 cacheTTL이 지나지 않았을 경우 이전 결과를 반환한다.
+// 동시에 들어온 요청은 하나의 확인 결과를 함께 사용하므로, 확인은 요청의 ctx가 아닌 별도의 ctx에서 실행하고
+// 실패한 항목은 확인을 시작한 요청의 Logger로 기록한다.
+func (s *HealthServiceImpl) runChecks(ctx context.Context) []models.HealthCheck {
+    s.mutex.Lock()
+    defer s.mutex.Unlock()
+    if s.checks != nil && time.Since(s.checkedAt) < s.cacheTTL() {
+        return s.checks
+    }
+
+    checks := []struct {
+        name    string
+        check   func(ctx context.Context) error
+    }{
+        { "database", s.healthRepo.Ping },
+        { "storage", s.checkStorage },
+        { "ranking_worker", func(context.Context) error { return s.rankingService.Health() } },
+    }
+
+    results := make([]models.HealthCheck, len(checks))
+    var wg sync.WaitGroup
+    for i, check := range checks {
+        wg.Add(1)
+        go func(i int, name string, check func(ctx context.Context) error) {
+            defer wg.Done()
+            checkCtx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+            defer cancel()
+            start := time.Now()
+            err := check(checkCtx)
+            result := models.HealthCheck{
+                Name: name,
+                Status: models.HealthStatusOK,
+                LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+            }
+            if err != nil {
+                result.Status = models.HealthStatusFail
+                logging.FromContext(ctx).Warn("준비 상태 확인에 실패했습니다.", "check", name, "error", err)
+            }
+            results[i] = result
+        }(i, check.name, check.check)
+    }
+    wg.Wait()
+
+    s.checks = results
+    s.checkedAt = time.Now()
+    return results
+}
+
+func (s *HealthServiceImpl) cacheTTL() time.Duration {
+    if s.conf.Server.ReadyCacheTTL == 0 {
+        return defaultReadyCacheTTL
+    }
+    return time.Duration(s.conf.Server.ReadyCacheTTL) * time.Millisecond
+}
+
+// 이미지 저장소에 작은 객체를 기록하여 연결과 쓰기 권한을 확인한다.
+func (s *HealthServiceImpl) checkStorage(ctx context.Context) error {
+    _, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+        Bucket: aws.String(s.conf.AWS.Bucket),
+        Key:    aws.String(healthCheckKey),
+        Body:   bytes.NewReader([]byte(time.Now().UTC().Format(time.RFC3339))),
+    })
+    return err
+}
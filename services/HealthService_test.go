@@ -0,0 +1,84 @@
+package services_test
+
+import (
+	"context"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"okra_board2/services"
+	"okra_board2/testutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthService(t *testing.T) {
+    conf := testutil.NewConfig()
+    conf.Server.ReadyCacheTTL = 1
+    db := testutil.NewDB(t)
+    client, fakeS3 := testutil.NewS3(t)
+    rankingService := services.NewRankingServiceImpl(&rankingRepositoryStub{}, conf)
+    healthService := services.NewHealthServiceImpl(repositories.NewHealthRepositoryImpl(db), rankingService, conf, client)
+
+    // 순위 갱신 작업이 실행되지 않았다.
+    report := healthService.Ready(context.Background())
+    assert.Equal(t, models.HealthStatusFail, report.Status)
+    if assert.Len(t, report.Checks, 3) {
+        assert.Equal(t, models.HealthStatusOK, report.Checks[0].Status)
+        assert.Equal(t, models.HealthStatusOK, report.Checks[1].Status)
+        assert.Equal(t, models.HealthStatusFail, report.Checks[2].Status)
+    }
+    assert.Len(t, fakeS3.Keys(conf.AWS.Bucket), 1)
+
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    go rankingService.Run(ctx)
+    assert.Eventually(t, func() bool {
+        return healthService.Ready(context.Background()).OK()
+    }, time.Second, 10 * time.Millisecond)
+
+    // 저장소에 연결할 수 없다.
+    fakeS3.Server.Close()
+    time.Sleep(time.Millisecond)
+    report = healthService.Ready(context.Background())
+    assert.Equal(t, models.HealthStatusFail, report.Status)
+    assert.Equal(t, models.HealthStatusFail, report.Checks[1].Status)
+
+    healthService.Shutdown()
+    assert.Equal(t, models.HealthStatusShutdown, healthService.Ready(context.Background()).Status)
+}
+
+func TestHealthServiceCache(t *testing.T) {
+    conf := testutil.NewConfig()
+    db := testutil.NewDB(t)
+    client, fakeS3 := testutil.NewS3(t)
+    rankingService := services.NewRankingServiceImpl(&rankingRepositoryStub{}, conf)
+    healthService := services.NewHealthServiceImpl(repositories.NewHealthRepositoryImpl(db), rankingService, conf, client)
+
+    report := healthService.Ready(context.Background())
+    assert.Equal(t, models.HealthStatusOK, report.Checks[1].Status)
+
+    // 재사용 시간 동안에는 저장소에 다시 기록하지 않는다.
+    fakeS3.Server.Close()
+    report = healthService.Ready(context.Background())
+    assert.Equal(t, models.HealthStatusOK, report.Checks[1].Status)
+
+    // 종료 상태는 확인 결과와 관계없이 바로 반영된다.
+    healthService.Shutdown()
+    assert.Equal(t, models.HealthStatusShutdown, healthService.Ready(context.Background()).Status)
+}
+
+func TestHealthServiceCanceledRequest(t *testing.T) {
+    conf := testutil.NewConfig()
+    db := testutil.NewDB(t)
+    client, _ := testutil.NewS3(t)
+    rankingService := services.NewRankingServiceImpl(&rankingRepositoryStub{}, conf)
+    healthService := services.NewHealthServiceImpl(repositories.NewHealthRepositoryImpl(db), rankingService, conf, client)
+
+    // 확인을 시작한 요청이 취소되어도 다른 요청과 함께 사용하는 결과는 실패로 기록되지 않는다.
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+    report := healthService.Ready(ctx)
+    assert.Equal(t, models.HealthStatusOK, report.Checks[0].Status)
+    assert.Equal(t, models.HealthStatusOK, report.Checks[1].Status)
+}
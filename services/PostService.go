@@ -2,13 +2,23 @@ package services
 
 import (
     "context"
+	"errors"
 	"fmt"
-	"log"
+	"math"
 	"okra_board2/config"
+	"okra_board2/logging"
+	"okra_board2/metrics"
 	"okra_board2/models"
 	"okra_board2/repositories"
-	"os"
+	"okra_board2/tracing"
+	"okra_board2/utils/htmltext"
+	"okra_board2/utils/locale"
+	"okra_board2/utils/markdown"
+	"okra_board2/utils/sanitize"
+	"okra_board2/utils/slug"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/PuerkitoBio/goquery"
@@ -21,28 +31,101 @@ type PostService interface {
 
     // 게시물을 작성하고 postId와 유효성 검사 결과 및 에러를 반환한다.
     // post.Thumbnail이 비어있을 경우 "default_thumbnail.png"로 설정한다.
-    WritePost(post *models.Post)    (postId int, result *models.PostValidationResult, err error)
+    // post.Slug가 비어있을 경우 제목으로부터 게시판 내에서 고유한 slug를 생성한다.
+    // 태그는 정규화되어 기존 태그와 연결되며, 없는 태그는 새로 생성된다.
+    // Markdown 형식일 경우 post.Source를 HTML로 변환하여 post.Content에 저장한다.
+    // 내용으로부터 요약과 예상 읽기 시간을 계산하여 함께 저장한다.
+    // 내용과 썸네일은 허용 목록에 따라 정제되며, 제거된 항목은 result.Sanitized로 반환된다.
+    // result.Valid()가 false일 경우에만 게시물이 저장되지 않는다.
+    WritePost(ctx context.Context, post *models.Post) (postId int, result *models.PostValidationResult, err error)
 
     // 게시물을 업데이트하고 유효성 검사 결과와 에러를 반환한다.
     // post.Thumbnail이 비어있을 경우 "default_thumbnail.png"로 설정한다.
-    UpdatePost(post *models.Post)   (result *models.PostValidationResult, err error)
+    // slug가 변경될 경우 이전 slug는 현재 게시물로 연결되도록 기록된다.
+    // 태그는 정규화되어 기존 태그와 연결되며, 없는 태그는 새로 생성된다.
+    // Markdown 형식일 경우 post.Source를 HTML로 변환하여 post.Content에 저장한다.
+    // 내용으로부터 요약과 예상 읽기 시간을 계산하여 함께 저장한다.
+    // 내용과 썸네일은 허용 목록에 따라 정제되며, 제거된 항목은 result.Sanitized로 반환된다.
+    // result.Valid()가 false일 경우에만 게시물이 저장되지 않는다.
+    UpdatePost(ctx context.Context, post *models.Post) (result *models.PostValidationResult, err error)
 
     // 게시물을 삭제하고 에러를 반환한다.
     // 게시물에 포함된 이미지도 함께 삭제한다.
-    DeletePost(postId int)          (err error)
+    DeletePost(ctx context.Context, postId int) (err error)
 
-    // 게시글을 불러온다.
+    // 게시글을 이전, 다음 게시글 정보와 함께 불러온다.
+    // 공개된 게시글을 조회할 경우(status != nil) Markdown 원문은 포함되지 않는다.
+    // 내용의 제목들로 목차를 만들며, id가 없는 제목에는 id가 추가된다.
     // enabled 속성이 true일 경우, 
     // status 열이 false인 게시물에 대하여 
     // RecordNotFound 에러를 반환한다.
-    GetPost(status *bool, postId int)             (post *models.Post, err error)
+    // 이전, 다음 게시글은 같은 게시판 내에서 게시 일자 순으로 검색하며,
+    // titleKeyword, tagKeyword가 nil이 아닐 경우 해당 검색 조건에 부합하는 게시글 중에서 검색한다.
+    GetPost(
+        ctx context.Context,
+        status *bool,
+        postId int,
+        titleKeyword *string,
+        tagKeyword *string,
+    )                               (post *models.Post, err error)
     
+    // 게시판 내에서 slug에 해당하는 게시글을 이전, 다음 게시글 정보와 함께 불러온다.
+    // 번역의 slug일 경우 post.Locale을 번역의 언어로 지정한다.
+    // slug가 게시글의 이전 slug일 경우 moved를 true로,
+    // post를 이전, 다음 게시글 정보가 없는 현재 게시글로 반환한다.
+    // 게시글을 찾지 못할 경우 gorm.ErrRecordNotFound를 반환한다.
+    GetPostBySlug(
+        ctx context.Context,
+        status *bool,
+        boardId int,
+        slug string,
+        titleKeyword *string,
+        tagKeyword *string,
+    )                               (post *models.Post, moved bool, err error)
+
+    // slug가 없는 기존 게시글들의 slug를 제목으로부터 생성한다.
+    GenerateMissingSlugs(ctx context.Context) (err error)
+
+    // 요약과 읽기 시간이 없는 기존 게시글들의 요약과 읽기 시간을 계산한다.
+    GenerateMissingExcerpts(ctx context.Context) (err error)
+
+    // 요청한 언어 중 번역이 있는 언어로 게시글을 변환한다. 공개된 게시글의 상세 조회에 사용한다.
+    // 요청한 언어의 번역이 없을 경우 설정된 대체 언어를 차례로 시도하며, 모두 없을 경우 원문을 유지한다.
+    // post.Locale이 지정되어 있을 경우(번역의 slug로 조회한 경우) 해당 언어를 가장 먼저 시도한다.
+    // post.Locale은 사용된 언어로, post.Locales는 게시글을 제공하는 언어 목록으로 설정된다.
+    Localize(ctx context.Context, post *models.Post, requested []string)
+
+    // 게시글 목록의 제목, slug, 썸네일, 요약을 Localize와 같은 방식으로 변환한다.
+    LocalizePosts(ctx context.Context, posts []models.Post, requested []string)
+
+    // 게시글의 모든 번역을 불러온다. 게시글이 없을 경우 gorm.ErrRecordNotFound를 반환한다.
+    GetTranslations(ctx context.Context, postId int) (translations []models.PostTranslation, err error)
+
+    // 게시글의 번역을 유효성 검사 후 저장한다. 같은 언어의 번역이 있을 경우 교체된다.
+    // 번역의 언어는 site.locales에 포함되어야 하며, 내용은 게시물과 같은 방식으로 변환 및 정제된다.
+    // 게시글이 없을 경우 gorm.ErrRecordNotFound를 반환한다.
+    SaveTranslation(
+        ctx context.Context,
+        translation *models.PostTranslation,
+    )                               (result *models.PostValidationResult, err error)
+
+    // 게시글의 번역을 삭제한다. 번역이 없을 경우 gorm.ErrRecordNotFound를 반환한다.
+    DeleteTranslation(ctx context.Context, postId int, locale string) (err error)
+
+    // locale의 번역이 없는 게시글을 최신순으로 불러온다.
+    GetPostsMissingTranslation(
+        ctx context.Context,
+        locale string,
+        page, size int,
+    )                               (posts []models.Post, count int)
+
     // 조건에 부합하는 게시글의 개수와 함께 게시글 배열을 반환한다.
     // enabled 속성이 true일 경우, status 열이 true인 게시글만을 불러온다.
     // page, size는 페이지네이션을 위한 속성이다.
     // boardId 속성이 nil일 경우 전체 게시판에서 게시글을 검색한다.
     // keyword 속성이 nil이 아닐 경우 제목에 keyword가 포함된 게시글만을 검색한다.
     GetPosts(
+        ctx context.Context,
         status bool,
         selected *bool,
         page, size int,
@@ -51,40 +134,64 @@ type PostService interface {
         tagKeyword *string,
     )                               (posts []models.Post, count int)
 
-    // selected colunm이 true인 게시글들의 썸네일 및 제목 정보를 불러온다.
-    GetSelectedThumbnails()         (thumbnaiils []models.Thumbnail)
+    // main 슬롯 그룹에 노출 중인 게시글들의 썸네일 및 제목 정보를 슬롯 순서대로 불러온다.
+    GetSelectedThumbnails(ctx context.Context) (thumbnaiils []models.Thumbnail)
 
-    // selected column이 true인 게시물을 재설정한다.
+    // selected column이 true인 게시물과 main 슬롯 그룹을 ids의 순서대로 재설정한다.
     // 전달받은 id 목록 중 존재하지 않는 게시물이 있을 경우
     // 해당 id 리스트를 gorm.ErrRecordNotFound와 함께 반환한다.
-    ResetSelectedPosts(ids *[]int)  ([]int, error)
+    ResetSelectedPosts(ctx context.Context, ids *[]int) ([]int, error)
 
     // 사용되지 않는 이미지를 s3에서 삭제한다.
-    DeleteUnusedImages()            (err error)
+    DeleteUnusedImages(ctx context.Context) (err error)
 
 }
 
+// 게시물 slug의 최대 글자 수
+const maxSlugLength = 80
+
 type PostServiceImpl struct {
-    postRepo    repositories.PostRepository
-    conf        *config.Config
-    client      *s3.Client
+    postRepo        repositories.PostRepository
+    tagService      TagService
+    relatedService  RelatedPostService
+    sitemapService  SitemapService
+    conf            *config.Config
+    client          *s3.Client
+    policy          *sanitize.Policy
 }
 
 func NewPostServiceImpl(
     postRepo repositories.PostRepository,
+    tagService TagService,
+    relatedService RelatedPostService,
+    sitemapService SitemapService,
     conf *config.Config,
     client *s3.Client,
 ) PostService {
     return &PostServiceImpl{
         postRepo: postRepo,
+        tagService: tagService,
+        relatedService: relatedService,
+        sitemapService: sitemapService,
         conf: conf,
         client: client,
+        policy: sanitizePolicy(&conf.Sanitizer),
     }
 }
 
+// 설정의 정제 정책. 비어있는 항목은 기본 정책의 값을 사용한다.
+func sanitizePolicy(conf *config.SanitizerConfig) *sanitize.Policy {
+    policy := sanitize.DefaultPolicy()
+    if len(conf.Tags) > 0 { policy.Tags = conf.Tags }
+    if len(conf.Attrs) > 0 { policy.GlobalAttrs = conf.Attrs }
+    if len(conf.Schemes) > 0 { policy.Schemes = conf.Schemes }
+    if len(conf.IframeHosts) > 0 { policy.IframeHosts = conf.IframeHosts }
+    return policy
+}
+
 func (r *PostServiceImpl) checkContent(content string) *string {
     var msg string
-    if content == "" || content == "<p><br></p>"{
+    if content == "" || content == "<p><br></p>" || content == "<p><br/></p>" {
         msg = "내용을 입력하세요."
     } else {
         return nil
@@ -104,7 +211,7 @@ func (r *PostServiceImpl) checkTitle(title string) *string {
 
 func (r *PostServiceImpl) checkThumbnail(thumbnail string) *string {
     var msg string 
-    if thumbnail == "" || thumbnail == "<p><br></p>" {
+    if thumbnail == "" || thumbnail == "<p><br></p>" || thumbnail == "<p><br/></p>" {
         msg = ""
     } else {
         return nil
@@ -112,55 +219,209 @@ func (r *PostServiceImpl) checkThumbnail(thumbnail string) *string {
     return &msg
 }
 
-func (r *PostServiceImpl) postValidation(post *models.Post) *models.PostValidationResult {
+// 선택 입력인 URL 항목을 검사한다. 비어있거나 http(s) 절대 주소일 경우 nil을 반환한다.
+func (r *PostServiceImpl) checkURL(rawURL *string) *string {
+    var msg string
+    if rawURL == nil || *rawURL == "" {
+        return nil
+    } else if u, err := url.Parse(*rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+        msg = "http 또는 https로 시작하는 주소를 입력하세요."
+    } else {
+        return nil
+    }
+    return &msg
+}
+
+// base로부터 게시판 내에서 고유한 slug를 생성한다.
+// 이미 사용중인 slug일 경우 "-2", "-3"과 같이 번호를 붙인다.
+func (r *PostServiceImpl) uniqueSlug(ctx context.Context, boardId int, base string, postId int) string {
+    runes := []rune(slug.Make(base))
+    if len(runes) > maxSlugLength {
+        runes = runes[:maxSlugLength]
+    }
+    base = strings.Trim(string(runes), "-")
+    if base == "" {
+        base = "post"
+    }
+    candidate := base
+    for i := 2; r.postRepo.CheckSlugExists(ctx, boardId, candidate, postId); i++ {
+        candidate = fmt.Sprintf("%s-%d", base, i)
+    }
+    return candidate
+}
+
+// 게시물의 slug를 정규화하고 유효성을 검사한다. If valid, it returns nil.
+// prev는 수정 전의 게시물이며, 새 게시물일 경우 nil이다.
+// slug가 비어있을 경우 새 게시물은 제목으로부터 slug를 생성하며,
+// 기존 게시물은 게시판이 바뀌지 않는 한 slug를 유지한다.
+func (r *PostServiceImpl) checkSlug(ctx context.Context, post *models.Post, prev *models.Post) *string {
+    var msg string
+    boardId, postId, base := post.BoardID, 0, post.Title
+    if prev != nil {
+        postId = prev.PostID
+        if boardId == 0 { boardId = prev.BoardID }
+        if prev.Slug != "" { base = prev.Slug }
+    }
+
+    if strings.TrimSpace(post.Slug) == "" {
+        if prev != nil && prev.Slug != "" && prev.BoardID == boardId {
+            return nil
+        }
+        post.Slug = r.uniqueSlug(ctx, boardId, base, postId)
+        return nil
+    }
+
+    post.Slug = slug.Make(post.Slug)
+    if post.Slug == "" {
+        msg = "주소에는 문자 또는 숫자가 포함되어야 합니다."
+    } else if len([]rune(post.Slug)) > maxSlugLength {
+        msg = fmt.Sprintf("주소는 %d자를 넘을 수 없습니다.", maxSlugLength)
+    } else if r.postRepo.CheckSlugExists(ctx, boardId, post.Slug, postId) {
+        msg = "이미 사용중인 주소입니다."
+    } else {
+        return nil
+    }
+    return &msg
+}
+
+// 내용의 작성 형식을 검사하고, Markdown 형식일 경우 원문을 HTML로 변환하여 post.Content에 저장한다.
+// HTML 형식일 경우 이전에 저장된 Markdown 원문은 지워진다.
+func (r *PostServiceImpl) renderContent(ctx context.Context, post *models.Post) *string {
+    _, span := tracing.Start(ctx, "PostService.renderContent")
+    defer span.End()
+
+    var msg string
+    switch post.ContentFormat {
+    case "", models.ContentFormatHTML:
+        empty := ""
+        post.ContentFormat = models.ContentFormatHTML
+        post.Source = &empty
+        return nil
+    case models.ContentFormatMarkdown:
+        source := ""
+        if post.Source != nil { source = *post.Source }
+        content, err := markdown.Render(source)
+        if err != nil {
+            msg = "Markdown을 변환할 수 없습니다."
+        } else {
+            post.Content = content
+            return nil
+        }
+    default:
+        msg = "html 또는 markdown 형식만 사용할 수 있습니다."
+    }
+    return &msg
+}
+
+// 게시물의 내용과 썸네일 HTML을 정제하고, 항목별로 제거된 태그와 속성을 반환한다.
+func (r *PostServiceImpl) sanitizePost(ctx context.Context, post *models.Post) map[string][]string {
+    _, span := tracing.Start(ctx, "PostService.sanitizePost")
+    defer span.End()
+
+    sanitized := make(map[string][]string)
+    var stripped []string
+    if post.Content, stripped = r.policy.Sanitize(post.Content); len(stripped) > 0 {
+        sanitized["content"] = stripped
+    }
+    if post.Thumbnail, stripped = r.policy.Sanitize(post.Thumbnail); len(stripped) > 0 {
+        sanitized["thumbnail"] = stripped
+    }
+    if len(sanitized) == 0 { return nil }
+    return sanitized
+}
+
+func (r *PostServiceImpl) postValidation(ctx context.Context, post *models.Post, prev *models.Post) *models.PostValidationResult {
+    formatCheck := r.renderContent(ctx, post)
+    sanitized := r.sanitizePost(ctx, post)
     if thumbnailCheck := r.checkThumbnail(post.Thumbnail); thumbnailCheck != nil {
         post.Thumbnail = fmt.Sprintf(
             `<p><img src="https://%s/images/%s"/></p>`,
             r.conf.AWS.Domain,
-            os.Getenv("DEFAULT_THUMBNAIL"),
+            r.conf.DefaultThumbnail,
         )
-        fmt.Println(post.Thumbnail)
     }
     result := &models.PostValidationResult {
         Title: r.checkTitle(post.Title),
+        Slug: r.checkSlug(ctx, post, prev),
         Content: r.checkContent(post.Content),
+        ContentFormat: formatCheck,
+        CanonicalURL: r.checkURL(post.CanonicalURL),
+        OGImage: r.checkURL(post.OGImage),
+        Sanitized: sanitized,
     }
     return result.GetOrNil()
 }
 
-// 태그 중복 제거
-func (r *PostServiceImpl) distinctTags(t []models.PostTag) []models.PostTag {
-    keys := make(map[string]struct{}) 
-    res := make([]models.PostTag, 0) 
-    for _, val := range t { 
-        if _, ok := keys[val.Name]; ok { 
-            continue 
-        } else { 
-            keys[val.Name] = struct{}{} 
-            res = append(res, val) 
-        } 
+func (r *PostServiceImpl) excerptLength() int {
+    if r.conf.Post.ExcerptLength <= 0 {
+        return 160
     }
-    return res 
+    // excerpt 컬럼의 크기를 넘지 않도록 한다.
+    if r.conf.Post.ExcerptLength > 999 {
+        return 999
+    }
+    return r.conf.Post.ExcerptLength
+}
+
+// 예상 읽기 시간(분)을 계산한다. 최소 1분.
+func (r *PostServiceImpl) readingTime(text string) int {
+    wordsPerMinute, charsPerMinute := r.conf.Post.WordsPerMinute, r.conf.Post.CharsPerMinute
+    if wordsPerMinute <= 0 { wordsPerMinute = 200 }
+    if charsPerMinute <= 0 { charsPerMinute = 500 }
+
+    words, chars := htmltext.CountWords(text)
+    minutes := float64(words) / float64(wordsPerMinute) + float64(chars) / float64(charsPerMinute)
+    if minutes < 1 {
+        return 1
+    }
+    return int(math.Round(minutes))
 }
 
-func (r *PostServiceImpl) WritePost(post *models.Post) (postId int, result *models.PostValidationResult,  err error) {
-    result = r.postValidation(post)
-    if result == nil {
-        post.Tags = r.distinctTags(post.Tags)
-        postId, err = r.postRepo.InsertPost(post)
+// 게시물 내용으로부터 요약과 예상 읽기 시간을 계산한다.
+func (r *PostServiceImpl) summarize(post *models.Post) {
+    text := htmltext.ExtractText(post.Content)
+    post.Excerpt = htmltext.Truncate(text, r.excerptLength())
+    post.ReadingTime = r.readingTime(text)
+}
+
+// 게시물이 변경된 후 연관 게시물 목록과 sitemap을 다시 생성하도록 기록한다.
+func (r *PostServiceImpl) onPostsChanged() {
+    r.relatedService.MarkDirty()
+    r.sitemapService.MarkDirty()
+}
+
+func (r *PostServiceImpl) WritePost(ctx context.Context, post *models.Post) (postId int, result *models.PostValidationResult,  err error) {
+    ctx, span := tracing.Start(ctx, "PostService.WritePost")
+    defer func() { tracing.End(span, err) }()
+
+    result = r.postValidation(ctx, post, nil)
+    if result == nil || result.Valid() {
+        r.summarize(post)
+        if post.Tags, err = r.tagService.ResolveTags(ctx, post.Tags); err != nil { return }
+        postId, err = r.postRepo.InsertPost(ctx, post)
+        if err == nil { r.onPostsChanged() }
     }
     return
 }
 
-func (r *PostServiceImpl) UpdatePost(post *models.Post) (result *models.PostValidationResult, err error) {
-    result = r.postValidation(post)
-    if result == nil {
-        err = r.postRepo.UpdatePost(post)
+func (r *PostServiceImpl) UpdatePost(ctx context.Context, post *models.Post) (result *models.PostValidationResult, err error) {
+    ctx, span := tracing.Start(ctx, "PostService.UpdatePost")
+    defer func() { tracing.End(span, err) }()
+
+    prev, err := r.postRepo.GetPost(ctx, nil, post.PostID)
+    if err != nil { return }
+
+    result = r.postValidation(ctx, post, prev)
+    if result == nil || result.Valid() {
+        r.summarize(post)
+        if post.Tags, err = r.tagService.ResolveTags(ctx, post.Tags); err != nil { return }
+        err = r.postRepo.UpdatePost(ctx, post)
+        if err == nil { r.onPostsChanged() }
     }
     return
 }
 
-func (r *PostServiceImpl) deleteImageFromHTML(htmlStr string) (err error) {
+func (r *PostServiceImpl) deleteImageFromHTML(ctx context.Context, htmlStr string) (err error) {
     node, err := html.Parse(strings.NewReader(htmlStr))
     if err != nil { return }
 
@@ -172,29 +433,32 @@ func (r *PostServiceImpl) deleteImageFromHTML(htmlStr string) (err error) {
         src := img.AttrOr("src", "")
         temp := strings.Split(src, "/")
         filename := temp[5]
-        if filename == os.Getenv("DEFAULT_THUMBNAIL") {
+        if filename == r.conf.DefaultThumbnail {
             return
         }
-        _, err := r.client.DeleteObject(context.TODO(), &s3.DeleteObjectInput {
+        _, err := r.client.DeleteObject(ctx, &s3.DeleteObjectInput {
             Bucket: aws.String(r.conf.AWS.Bucket),
             Key:    aws.String("images/"+filename),
         })
         if err!= nil {
-            log.Println(err)
+            logging.FromContext(ctx).Error("이미지를 삭제하지 못했습니다.", "file", filename, "error", err)
         } else {
-            log.Println("이미지가 삭제되었습니다: "+filename)
+            logging.FromContext(ctx).Info("이미지가 삭제되었습니다.", "file", filename)
         }
     })
     return nil
 }
 
-func (r *PostServiceImpl) DeleteUnusedImages() (err error) {
-    //posts := r.postRepo.GetAllPosts();
+func (r *PostServiceImpl) DeleteUnusedImages(ctx context.Context) (err error) {
+    ctx, span := tracing.Start(ctx, "PostService.DeleteUnusedImages")
+    defer func() { tracing.End(span, err) }()
+
+    //posts := r.postRepo.GetAllPosts(ctx);
     params := &s3.ListObjectsInput {
         Bucket: aws.String(r.conf.AWS.Bucket),
         Prefix: aws.String("images"),
     }
-    resp, err := r.client.ListObjects(context.TODO(), params)
+    resp, err := r.client.ListObjects(ctx, params)
     if err != nil  { return }
 
     var filenames []string
@@ -206,35 +470,314 @@ func (r *PostServiceImpl) DeleteUnusedImages() (err error) {
     return
 }
 
-func (r *PostServiceImpl) DeletePost(postId int) (err error) {
-    post, err := r.postRepo.GetPost(nil, postId)
+func (r *PostServiceImpl) DeletePost(ctx context.Context, postId int) (err error) {
+    ctx, span := tracing.Start(ctx, "PostService.DeletePost")
+    defer func() { tracing.End(span, err) }()
+
+    post, err := r.postRepo.GetPost(ctx, nil, postId)
     if err != nil { return }
 
-    err = r.deleteImageFromHTML(post.Content)
+    err = r.deleteImageFromHTML(ctx, post.Content)
     if err != nil { return }
 
-    err = r.deleteImageFromHTML(post.Thumbnail)
+    err = r.deleteImageFromHTML(ctx, post.Thumbnail)
+    if err != nil { return }
+
+    if err = r.postRepo.DeletePost(ctx, postId); err != nil { return }
+
+    r.onPostsChanged()
+    return
+}
+
+// 상세 조회 시 내용의 제목들로 목차를 만들고, id가 없는 제목에 id를 추가한다.
+func (r *PostServiceImpl) setTOC(post *models.Post) {
+    content, headings := htmltext.Headings(post.Content)
+    post.Content = content
+    for _, heading := range headings {
+        post.TOC = append(post.TOC, models.TOCItem {
+            Level: heading.Level,
+            ID: heading.ID,
+            Text: heading.Text,
+        })
+    }
+}
+
+// 공개된 게시물을 조회할 경우 Markdown 원문을 제외한다.
+func (r *PostServiceImpl) hideSource(status *bool, post *models.Post) {
+    if status != nil {
+        post.Source = nil
+    }
+}
+
+// 이전, 다음 게시글 정보를 post에 추가한다.
+func (r *PostServiceImpl) setAdjacentPosts(
+    ctx context.Context,
+    status *bool,
+    post *models.Post,
+    titleKeyword *string,
+    tagKeyword *string,
+) {
+    prevPost, nextPost, err := r.postRepo.GetAdjacentPosts(ctx, status, post, titleKeyword, tagKeyword)
+    if err != nil {
+        logging.FromContext(ctx).Error("이전/다음 게시물을 조회하지 못했습니다.", "post_id", post.PostID, "error", err)
+        return
+    }
+    post.Prev = prevPost
+    post.Next = nextPost
+}
+
+func (r *PostServiceImpl) GetPost(
+    ctx context.Context,
+    status *bool,
+    postId int,
+    titleKeyword *string,
+    tagKeyword *string,
+) (post *models.Post, err error) {
+    ctx, span := tracing.Start(ctx, "PostService.GetPost")
+    defer func() { tracing.End(span, err) }()
+
+    post, err = r.postRepo.GetPost(ctx, status, postId)
     if err != nil { return }
 
-    return r.postRepo.DeletePost(postId)
+    r.hideSource(status, post)
+    r.setTOC(post)
+    r.setAdjacentPosts(ctx, status, post, titleKeyword, tagKeyword)
+    return
+}
+
+func (r *PostServiceImpl) GetPostBySlug(
+    ctx context.Context,
+    status *bool,
+    boardId int,
+    postSlug string,
+    titleKeyword *string,
+    tagKeyword *string,
+) (post *models.Post, moved bool, err error) {
+    ctx, span := tracing.Start(ctx, "PostService.GetPostBySlug")
+    defer func() { tracing.End(span, err) }()
+
+    post, err = r.postRepo.GetPostBySlug(ctx, status, boardId, postSlug)
+    if err == nil {
+        r.hideSource(status, post)
+        r.setTOC(post)
+        r.setAdjacentPosts(ctx, status, post, titleKeyword, tagKeyword)
+        return
+    }
+    if !errors.Is(err, gorm.ErrRecordNotFound) { return }
+
+    // 번역의 slug일 경우 번역의 언어를 post.Locale로 지정한다.
+    if translated, l, translationErr := r.postRepo.GetPostByTranslationSlug(ctx, status, boardId, postSlug); translationErr == nil {
+        post, err = translated, nil
+        post.Locale = l
+        r.hideSource(status, post)
+        r.setTOC(post)
+        r.setAdjacentPosts(ctx, status, post, titleKeyword, tagKeyword)
+        return
+    }
+
+    history, historyErr := r.postRepo.GetSlugHistory(ctx, boardId, postSlug)
+    if historyErr != nil { return }
+
+    post, err = r.postRepo.GetPost(ctx, status, history.PostID)
+    return post, err == nil, err
+}
+
+// 게시물을 제공하는 언어 목록. 첫 번째는 원문의 언어이다.
+func supportedLocales(conf *config.Config) []string {
+    locales := []string{ conf.Site.DefaultLocale() }
+    for _, l := range conf.Site.Locales {
+        if l != conf.Site.DefaultLocale() { locales = append(locales, l) }
+    }
+    return locales
+}
+
+// 요청한 언어와 대체 언어를 차례로 시도하여 사용할 번역을 선택한다.
+// 원문을 사용해야 할 경우 nil을 반환한다.
+func selectTranslation(
+    conf *config.Config,
+    translations []models.PostTranslation,
+    requested []string,
+) *models.PostTranslation {
+    candidates := locale.Match(requested, supportedLocales(conf))
+    candidates = append(candidates, conf.Site.FallbackLocales...)
+    for _, l := range candidates {
+        if l == conf.Site.DefaultLocale() { return nil }
+        for i := range translations {
+            if translations[i].Locale == l { return &translations[i] }
+        }
+    }
+    return nil
+}
+
+// 번역의 비어있지 않은 항목으로 게시물의 항목을 교체한다.
+// 원문에 지정된 canonical 주소는 번역에 사용하지 않는다.
+func applyTranslation(post *models.Post, translation *models.PostTranslation) {
+    post.Locale = translation.Locale
+    post.CanonicalURL = nil
+    post.Title = translation.Title
+    if translation.Slug != "" { post.Slug = translation.Slug }
+    if translation.Thumbnail != "" { post.Thumbnail = translation.Thumbnail }
+    if translation.Content != "" {
+        post.Content = translation.Content
+        post.ContentFormat = translation.ContentFormat
+        post.Source = translation.Source
+    }
+    if translation.Excerpt != "" {
+        post.Excerpt = translation.Excerpt
+        post.ReadingTime = translation.ReadingTime
+    }
+    if translation.MetaTitle != nil { post.MetaTitle = translation.MetaTitle }
+    if translation.MetaDescription != nil { post.MetaDescription = translation.MetaDescription }
+    if translation.OGImage != nil { post.OGImage = translation.OGImage }
 }
 
-func (r *PostServiceImpl) GetPost(status *bool, postId int) (post *models.Post, err error) {
-    post, err = r.postRepo.GetPost(status, postId)
+func (r *PostServiceImpl) Localize(ctx context.Context, post *models.Post, requested []string) {
+    if post.Locale != "" {
+        requested = append([]string{ post.Locale }, requested...)
+    }
+    translations := r.postRepo.GetTranslations(ctx, []int{ post.PostID }, nil)
+
+    post.Locale = r.conf.Site.DefaultLocale()
+    post.Locales = []string{ post.Locale }
+    for _, translation := range translations {
+        post.Locales = append(post.Locales, translation.Locale)
+    }
+    if translation := selectTranslation(r.conf, translations, requested); translation != nil {
+        applyTranslation(post, translation)
+        post.Source = nil
+        post.TOC = nil
+        r.setTOC(post)
+    }
+}
+
+func (r *PostServiceImpl) LocalizePosts(ctx context.Context, posts []models.Post, requested []string) {
+    postIds := make([]int, len(posts))
+    for i := range posts {
+        postIds[i] = posts[i].PostID
+    }
+    translations := make(map[int][]models.PostTranslation)
+    for _, translation := range r.postRepo.GetTranslations(ctx, postIds, nil) {
+        translations[translation.PostID] = append(translations[translation.PostID], translation)
+    }
+    for i := range posts {
+        posts[i].Locale = r.conf.Site.DefaultLocale()
+        if translation := selectTranslation(r.conf, translations[posts[i].PostID], requested); translation != nil {
+            applyTranslation(&posts[i], translation)
+            // 목록에는 내용을 포함하지 않는다.
+            posts[i].Content = ""
+            posts[i].Source = nil
+        }
+    }
+}
+
+func (r *PostServiceImpl) GetTranslations(ctx context.Context, postId int) (translations []models.PostTranslation, err error) {
+    if _, err = r.postRepo.GetPost(ctx, nil, postId); err != nil { return }
+    translations = r.postRepo.GetTranslations(ctx, []int{ postId }, nil)
+    if translations == nil {
+        translations = make([]models.PostTranslation, 0)
+    }
+    return
+}
+
+// 번역의 언어를 검사한다. If valid, it returns nil.
+func (r *PostServiceImpl) checkLocale(l string) *string {
+    var msg string
+    for _, supported := range supportedLocales(r.conf)[1:] {
+        if l == supported { return nil }
+    }
+    msg = fmt.Sprintf("번역할 수 있는 언어가 아닙니다. (%s)", strings.Join(supportedLocales(r.conf)[1:], ", "))
+    return &msg
+}
+
+func (r *PostServiceImpl) SaveTranslation(
+    ctx context.Context,
+    translation *models.PostTranslation,
+) (result *models.PostValidationResult, err error) {
+    ctx, span := tracing.Start(ctx, "PostService.SaveTranslation")
+    defer func() { tracing.End(span, err) }()
+
+    post, err := r.postRepo.GetPost(ctx, nil, translation.PostID)
     if err != nil { return }
 
-    if prevPost, err := r.postRepo.GetPrevPostInfo(status, postId); err == nil {
-        post.Prev = prevPost
+    // 번역을 게시물과 같은 방식으로 검사하기 위해 게시물로 변환한다.
+    prev := &models.Post{ PostID: post.PostID, BoardID: post.BoardID }
+    for _, existing := range r.postRepo.GetTranslations(ctx, []int{ post.PostID }, &translation.Locale) {
+        prev.Slug = existing.Slug
     }
+    temp := &models.Post {
+        PostID: post.PostID,
+        BoardID: post.BoardID,
+        Title: translation.Title,
+        Slug: translation.Slug,
+        Thumbnail: translation.Thumbnail,
+        Content: translation.Content,
+        ContentFormat: translation.ContentFormat,
+        Source: translation.Source,
+        OGImage: translation.OGImage,
+    }
+    formatCheck := r.renderContent(ctx, temp)
+    sanitized := r.sanitizePost(ctx, temp)
+    result = (&models.PostValidationResult {
+        Title: r.checkTitle(temp.Title),
+        Slug: r.checkSlug(ctx, temp, prev),
+        Locale: r.checkLocale(translation.Locale),
+        Content: r.checkContent(temp.Content),
+        ContentFormat: formatCheck,
+        OGImage: r.checkURL(temp.OGImage),
+        Sanitized: sanitized,
+    }).GetOrNil()
+    if result != nil && !result.Valid() { return }
+
+    if temp.Slug == "" { temp.Slug = prev.Slug }
+    r.summarize(temp)
+
+    translation.Slug = temp.Slug
+    translation.Thumbnail = temp.Thumbnail
+    translation.Content = temp.Content
+    translation.ContentFormat = temp.ContentFormat
+    translation.Source = temp.Source
+    translation.Excerpt = temp.Excerpt
+    translation.ReadingTime = temp.ReadingTime
+    err = r.postRepo.SaveTranslation(ctx, translation)
+    return
+}
+
+func (r *PostServiceImpl) DeleteTranslation(ctx context.Context, postId int, locale string) (err error) {
+    return r.postRepo.DeleteTranslation(ctx, postId, locale)
+}
 
-    if nextPost, err := r.postRepo.GetNextPostInfo(status, postId); err == nil {
-        post.Next = nextPost
+func (r *PostServiceImpl) GetPostsMissingTranslation(
+    ctx context.Context,
+    locale string,
+    page, size int,
+) (posts []models.Post, count int) {
+    return r.postRepo.GetPostsMissingTranslation(ctx, locale, page, size)
+}
+
+func (r *PostServiceImpl) GenerateMissingExcerpts(ctx context.Context) (err error) {
+    defer func(start time.Time) { metrics.ObserveJob("generate_excerpts", start, err) }(time.Now())
+    for _, post := range r.postRepo.GetPostsWithoutExcerpt(ctx) {
+        r.summarize(&post)
+        if err = r.postRepo.UpdateExcerpt(ctx, post.PostID, post.Excerpt, post.ReadingTime); err != nil {
+            return
+        }
     }
+    return
+}
 
+func (r *PostServiceImpl) GenerateMissingSlugs(ctx context.Context) (err error) {
+    defer func(start time.Time) { metrics.ObserveJob("generate_slugs", start, err) }(time.Now())
+    for _, post := range r.postRepo.GetPostsWithoutSlug(ctx) {
+        postSlug := r.uniqueSlug(ctx, post.BoardID, post.Title, post.PostID)
+        if err = r.postRepo.UpdateSlug(ctx, post.PostID, postSlug); err != nil {
+            return
+        }
+    }
     return
 }
 
 func (r *PostServiceImpl) GetPosts(
+    ctx context.Context,
     enabled bool,
     selected *bool,
     page, size int,
@@ -243,6 +786,7 @@ func (r *PostServiceImpl) GetPosts(
     tagKeyword *string,
 ) (posts []models.Post, count int) {
     posts, count = r.postRepo.GetPosts(
+        ctx,
         enabled,
         selected,
         page, size,
@@ -254,19 +798,19 @@ func (r *PostServiceImpl) GetPosts(
     return
 }
 
-func (r *PostServiceImpl) GetSelectedThumbnails() (thumbnails []models.Thumbnail){
-    return r.postRepo.GetSelectedThumbnails()
+func (r *PostServiceImpl) GetSelectedThumbnails(ctx context.Context) (thumbnails []models.Thumbnail){
+    return r.postRepo.GetSelectedThumbnails(ctx)
 }
 
-func (r *PostServiceImpl) ResetSelectedPosts(ids *[]int) ([]int, error) {
+func (r *PostServiceImpl) ResetSelectedPosts(ctx context.Context, ids *[]int) ([]int, error) {
     var nonexistids []int
     for _, id := range *ids {
-        if !r.postRepo.CheckPostExists(id) {
+        if !r.postRepo.CheckPostExists(ctx, id) {
             nonexistids = append(nonexistids, id)
         }
     }
     if len(nonexistids) > 0 {
         return nonexistids, gorm.ErrRecordNotFound
     }
-    return nil, r.postRepo.ResetSelectedPost(ids)
+    return nil, r.postRepo.ResetSelectedPost(ctx, ids)
 }
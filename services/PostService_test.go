@@ -1,29 +1,31 @@
 package services_test
 
 import (
+	"context"
 	"okra_board2/config"
 	"okra_board2/models"
 	"okra_board2/repositories"
 	"okra_board2/services"
+	"okra_board2/testutil"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-//    "gorm.io/gorm"
+    "gorm.io/gorm"
 )
 
 func TestPostService(t *testing.T) {
-    conf, err := config.LoadConfigTest()
-    if err != nil { assert.Error(t, err) }
-
-    db, err := config.InitDBConnection(conf)
-    if err != nil { assert.Error(t, err) }
-
-    s3, err := config.InitAwsS3Client(conf)
-    if err != nil { assert.Error(t, err) }
+    ctx := context.Background()
+    conf := testutil.NewConfig()
+    db := testutil.NewDB(t)
+    s3, fakeS3 := testutil.NewS3(t)
 
     postRepo := repositories.NewPostRepositoryImpl(db)
-    s := services.NewPostServiceImpl(postRepo, conf, s3)
+    relatedService := services.NewRelatedPostServiceImpl(postRepo)
+    tagService := services.NewTagServiceImpl(repositories.NewTagRepositoryImpl(db), relatedService)
+    sitemapService := services.NewSitemapServiceImpl(postRepo, conf)
+    s := services.NewPostServiceImpl(postRepo, tagService, relatedService, sitemapService, conf, s3)
 
     posts := make([]models.Post, 5)
     for i := 0; i < 5; i++ {
@@ -31,40 +33,372 @@ func TestPostService(t *testing.T) {
         posts[i] = models.Post {
             BoardID: 1,
             Title: "test title " + index,
-            Thumbnail: "test thumbnail " + index,
+            Thumbnail: `<img src="https://images.example.com/test-bucket/images/thumbnail-` + index + `.png">`,
             Content: "test content " + index,
         }
+        fakeS3.Put(conf.AWS.Bucket, "images/thumbnail-" + index + ".png", []byte("png"))
     }
 
     // insert
     for i := 0; i < len(posts); i++ {
-        if _, _, err := s.WritePost(&posts[i]); err != nil {
+        if _, _, err := s.WritePost(ctx, &posts[i]); err != nil {
             assert.Error(t, err)
         }
     }
+    _, count := s.GetPosts(ctx, false, nil, 1, 10, nil, nil, nil)
+    assert.Equal(t, 5, count)
 
     // reset selected posts
 //    ids := []int{posts[0].PostID, posts[1].PostID, posts[2].PostID, 1}
 //
-//    nonexistids, err := s.ResetSelectedPosts(&ids)
+//    nonexistids, err := s.ResetSelectedPosts(ctx, &ids)
 //    assert.Equal(t, err, gorm.ErrRecordNotFound)
 //    assert.Equal(t, nonexistids, []int{1})
 //
 //    ids = []int{posts[0].PostID, posts[1].PostID, posts[2].PostID}
 //
-//    nonexistids, err = s.ResetSelectedPosts(&ids)
+//    nonexistids, err = s.ResetSelectedPosts(ctx, &ids)
 //    assert.Equal(t, len(nonexistids), 0)
 //    assert.Equal(t, err, nil)
 
     // get selected thumbnails
-//    thumbnails := s.GetSelectedThumbnails()
+//    thumbnails := s.GetSelectedThumbnails(ctx)
 //    assert.Equal(t, 3, len(thumbnails))
 
     // delete
     for i := 0; i < len(posts); i++ {
-        if err := s.DeletePost(posts[i].PostID); err != nil {
+        if err := s.DeletePost(ctx, posts[i].PostID); err != nil {
             assert.Error(t, err)
         }
     }
 
+    // 게시물을 삭제하면 썸네일 이미지도 삭제된다.
+    assert.Empty(t, fakeS3.Keys(conf.AWS.Bucket))
+}
+
+type slugPostRepositoryStub struct {
+    repositories.PostRepository
+    posts   []models.Post
+    history []models.PostSlug
+    translations []models.PostTranslation
+}
+
+func (r *slugPostRepositoryStub) CheckSlugExists(ctx context.Context, boardId int, slug string, postId int) bool {
+    for _, post := range r.posts {
+        if post.BoardID == boardId && post.Slug == slug && post.PostID != postId { return true }
+    }
+    for _, history := range r.history {
+        if history.BoardID == boardId && history.Slug == slug && history.PostID != postId { return true }
+    }
+    for _, translation := range r.translations {
+        if translation.Slug == slug && translation.PostID != postId { return true }
+    }
+    return false
+}
+
+func (r *slugPostRepositoryStub) GetTranslations(ctx context.Context, postIds []int, locale *string) (translations []models.PostTranslation) {
+    for _, translation := range r.translations {
+        for _, postId := range postIds {
+            if translation.PostID == postId && (locale == nil || translation.Locale == *locale) {
+                translations = append(translations, translation)
+            }
+        }
+    }
+    return
+}
+
+func (r *slugPostRepositoryStub) SaveTranslation(ctx context.Context, translation *models.PostTranslation) error {
+    for i := range r.translations {
+        if r.translations[i].PostID == translation.PostID && r.translations[i].Locale == translation.Locale {
+            r.translations[i] = *translation
+            return nil
+        }
+    }
+    r.translations = append(r.translations, *translation)
+    return nil
+}
+
+func (r *slugPostRepositoryStub) GetPostByTranslationSlug(ctx context.Context, status *bool, boardId int, slug string) (*models.Post, string, error) {
+    for _, translation := range r.translations {
+        if translation.Slug != slug { continue }
+        post, err := r.GetPost(ctx, status, translation.PostID)
+        if err == nil && post.BoardID == boardId {
+            return post, translation.Locale, nil
+        }
+    }
+    return nil, "", gorm.ErrRecordNotFound
+}
+
+func (r *slugPostRepositoryStub) InsertPost(ctx context.Context, post *models.Post) (int, error) {
+    post.PostID = len(r.posts) + 1
+    r.posts = append(r.posts, *post)
+    return post.PostID, nil
+}
+
+func (r *slugPostRepositoryStub) UpdatePost(ctx context.Context, post *models.Post) error {
+    for i := range r.posts {
+        if r.posts[i].PostID != post.PostID { continue }
+        if post.Slug != "" && post.Slug != r.posts[i].Slug {
+            r.history = append(r.history, models.PostSlug {
+                BoardID: r.posts[i].BoardID, Slug: r.posts[i].Slug, PostID: post.PostID,
+            })
+            r.posts[i].Slug = post.Slug
+        }
+        r.posts[i].Title = post.Title
+        return nil
+    }
+    return gorm.ErrRecordNotFound
+}
+
+func (r *slugPostRepositoryStub) GetPost(ctx context.Context, status *bool, postId int) (*models.Post, error) {
+    for i := range r.posts {
+        if r.posts[i].PostID == postId {
+            post := r.posts[i]
+            return &post, nil
+        }
+    }
+    return nil, gorm.ErrRecordNotFound
+}
+
+func (r *slugPostRepositoryStub) GetPostBySlug(ctx context.Context, status *bool, boardId int, slug string) (*models.Post, error) {
+    for i := range r.posts {
+        if r.posts[i].BoardID == boardId && r.posts[i].Slug == slug {
+            post := r.posts[i]
+            return &post, nil
+        }
+    }
+    return nil, gorm.ErrRecordNotFound
+}
+
+func (r *slugPostRepositoryStub) GetSlugHistory(ctx context.Context, boardId int, slug string) (*models.PostSlug, error) {
+    for i := range r.history {
+        if r.history[i].BoardID == boardId && r.history[i].Slug == slug {
+            return &r.history[i], nil
+        }
+    }
+    return nil, gorm.ErrRecordNotFound
+}
+
+func (r *slugPostRepositoryStub) GetAdjacentPosts(
+    ctx context.Context,
+    status *bool, post *models.Post, titleKeyword *string, tagKeyword *string,
+) (*models.PostE, *models.PostE, error) {
+    return nil, nil, nil
+}
+
+func (r *slugPostRepositoryStub) GetAllPublishedPosts(ctx context.Context) []models.Post {
+    return nil
+}
+
+// 저장소 stub과 설정으로 이미지 저장소 없이 PostService를 생성한다.
+func newPostService(t *testing.T, repo repositories.PostRepository, conf *config.Config) services.PostService {
+    t.Helper()
+    relatedService := services.NewRelatedPostServiceImpl(repo)
+    tagService := services.NewTagServiceImpl(&tagRepositoryStub{}, relatedService)
+    sitemapService := services.NewSitemapServiceImpl(repo, conf)
+    return services.NewPostServiceImpl(repo, tagService, relatedService, sitemapService, conf, nil)
+}
+
+func TestPostServiceSlug(t *testing.T) {
+    ctx := context.Background()
+    repo := &slugPostRepositoryStub{}
+    conf := &config.Config{}
+    s := newPostService(t, repo, conf)
+
+    newPost := func(title string) *models.Post {
+        return &models.Post{ BoardID: 1, Title: title, Thumbnail: "thumbnail.png", Content: "<p>content</p>" }
+    }
+
+    // 제목으로부터 생성되며, 중복될 경우 번호가 붙는다.
+    _, result, err := s.WritePost(ctx, newPost("Hello World"))
+    assert.Nil(t, result)
+    assert.NoError(t, err)
+    assert.Equal(t, "hello-world", repo.posts[0].Slug)
+
+    _, result, err = s.WritePost(ctx, newPost("Hello, World!"))
+    assert.Nil(t, result)
+    assert.NoError(t, err)
+    assert.Equal(t, "hello-world-2", repo.posts[1].Slug)
+
+    // 직접 입력한 slug가 중복될 경우 유효성 검사에 실패한다.
+    post := newPost("Another")
+    post.Slug = "Hello World"
+    _, result, _ = s.WritePost(ctx, post)
+    assert.NotNil(t, result)
+    assert.NotNil(t, result.Slug)
+
+    // slug를 비워서 수정하면 기존 slug가 유지된다.
+    result, err = s.UpdatePost(ctx, &models.Post{ PostID: 1, BoardID: 1, Title: "Renamed", Thumbnail: "thumbnail.png", Content: "<p>content</p>" })
+    assert.Nil(t, result)
+    assert.NoError(t, err)
+    assert.Equal(t, "hello-world", repo.posts[0].Slug)
+
+    // slug가 변경되면 이전 slug는 현재 게시글로 연결된다.
+    result, err = s.UpdatePost(ctx, &models.Post{ PostID: 1, BoardID: 1, Title: "Renamed", Slug: "renamed", Thumbnail: "thumbnail.png", Content: "<p>content</p>" })
+    assert.Nil(t, result)
+    assert.NoError(t, err)
+
+    found, moved, err := s.GetPostBySlug(ctx, nil, 1, "renamed", nil, nil)
+    assert.NoError(t, err)
+    assert.False(t, moved)
+    assert.Equal(t, 1, found.PostID)
+
+    found, moved, err = s.GetPostBySlug(ctx, nil, 1, "hello-world", nil, nil)
+    assert.NoError(t, err)
+    assert.True(t, moved)
+    assert.Equal(t, "renamed", found.Slug)
+
+    _, _, err = s.GetPostBySlug(ctx, nil, 2, "renamed", nil, nil)
+    assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestPostServiceSanitize(t *testing.T) {
+    ctx := context.Background()
+    repo := &slugPostRepositoryStub{}
+    conf := &config.Config{}
+    s := newPostService(t, repo, conf)
+
+    post := &models.Post {
+        BoardID: 1,
+        Title: "sanitize",
+        Thumbnail: `<p><img src="https://cdn.example.com/a.png" onerror="alert(1)"></p>`,
+        Content: `<p onclick="alert(1)">hello <a href="javascript:alert(1)" target="_blank">link</a></p>` +
+            `<script>alert(1)</script><font>text</font>` +
+            `<iframe src="https://www.youtube.com/embed/abc"></iframe>` +
+            `<iframe src="https://evil.example.com/"></iframe>`,
+    }
+    _, result, err := s.WritePost(ctx, post)
+    assert.NoError(t, err)
+    assert.True(t, result.Valid())
+    assert.Equal(t, []string{"<p onclick>", "<a href>", "<script>", "<font>", "<iframe src>"}, result.Sanitized["content"])
+    assert.Equal(t, []string{"<img onerror>"}, result.Sanitized["thumbnail"])
+    assert.Equal(t,
+        `<p>hello <a target="_blank" rel="noopener noreferrer">link</a></p>text` +
+        `<iframe src="https://www.youtube.com/embed/abc"></iframe>`,
+        repo.posts[0].Content,
+    )
+
+    // 정제 후 내용이 비어있을 경우 유효성 검사에 실패한다.
+    _, result, _ = s.WritePost(ctx, &models.Post{ BoardID: 1, Title: "empty", Content: "<script>alert(1)</script>" })
+    assert.False(t, result.Valid())
+    assert.NotNil(t, result.Content)
+    assert.Len(t, repo.posts, 1)
+}
+
+func TestPostServiceMarkdown(t *testing.T) {
+    ctx := context.Background()
+    repo := &slugPostRepositoryStub{}
+    conf := &config.Config{}
+    s := newPostService(t, repo, conf)
+
+    source := "# 소개\n\n| a | b |\n|---|---|\n| 1 | 2 |\n\n본문[^1] <span onclick=\"x\">!</span>\n\n[^1]: 각주\n"
+    post := &models.Post {
+        BoardID: 1, Title: "markdown", Thumbnail: "thumbnail.png",
+        ContentFormat: models.ContentFormatMarkdown, Source: &source,
+    }
+    _, result, err := s.WritePost(ctx, post)
+    assert.NoError(t, err)
+    assert.True(t, result.Valid())
+    assert.Equal(t, []string{"<span onclick>"}, result.Sanitized["content"])
+
+    content := repo.posts[0].Content
+    assert.Contains(t, content, `<h1 id="소개">소개</h1>`)
+    assert.Contains(t, content, "<table>")
+    assert.Contains(t, content, `<li id="fn:1">`)
+    assert.NotContains(t, content, "onclick")
+    assert.Equal(t, source, *repo.posts[0].Source)
+
+    // 공개된 게시물에는 원문이 포함되지 않는다.
+    status := true
+    found, err := s.GetPost(ctx, &status, 1, nil, nil)
+    assert.NoError(t, err)
+    assert.Nil(t, found.Source)
+    found, err = s.GetPost(ctx, nil, 1, nil, nil)
+    assert.NoError(t, err)
+    assert.Equal(t, source, *found.Source)
+
+    _, result, _ = s.WritePost(ctx, &models.Post{ BoardID: 1, Title: "unknown", Content: "text", ContentFormat: "rst" })
+    assert.False(t, result.Valid())
+    assert.NotNil(t, result.ContentFormat)
+}
+
+func TestPostServiceExcerpt(t *testing.T) {
+    ctx := context.Background()
+    repo := &slugPostRepositoryStub{}
+    conf := &config.Config{ Post: config.PostConfig{ ExcerptLength: 10 } }
+    s := newPostService(t, repo, conf)
+
+    // 한글 1,000자와 영어 200단어는 각각 2분, 1분이 걸린다.
+    content := "<h2>소개</h2><p>" + strings.Repeat("가", 1000) + "</p><h2>소개</h2><h3 id=\"custom\">Details</h3><p>" +
+        strings.Repeat("word ", 200) + "</p>"
+    _, result, err := s.WritePost(ctx, &models.Post{ BoardID: 1, Title: "excerpt", Thumbnail: "thumbnail.png", Content: content })
+    assert.NoError(t, err)
+    assert.Nil(t, result)
+    assert.Equal(t, "소개 " + strings.Repeat("가", 7) + "…", repo.posts[0].Excerpt)
+    assert.Equal(t, 3, repo.posts[0].ReadingTime)
+
+    post, err := s.GetPost(ctx, nil, 1, nil, nil)
+    assert.NoError(t, err)
+    assert.Equal(t, []models.TOCItem {
+        { Level: 2, ID: "소개", Text: "소개" },
+        { Level: 2, ID: "소개-2", Text: "소개" },
+        { Level: 3, ID: "custom", Text: "Details" },
+    }, post.TOC)
+    assert.Contains(t, post.Content, `<h2 id="소개-2">소개</h2>`)
+}
+
+func TestPostServiceTranslation(t *testing.T) {
+    ctx := context.Background()
+    repo := &slugPostRepositoryStub{}
+    conf := &config.Config{ Site: config.SiteConfig{
+        Language: "ko", Locales: []string{"en", "ja"}, FallbackLocales: []string{"en"},
+    }}
+    s := newPostService(t, repo, conf)
+
+    s.WritePost(ctx, &models.Post{ BoardID: 1, Title: "안녕하세요", Thumbnail: "thumbnail.png", Content: "<p>본문</p>", Status: true })
+
+    result, err := s.SaveTranslation(ctx, &models.PostTranslation{ PostID: 1, Locale: "en", Title: "Hello", Content: "<h2>Intro</h2><p>Body</p>" })
+    assert.NoError(t, err)
+    assert.Nil(t, result)
+    assert.Equal(t, "hello", repo.translations[0].Slug)
+    assert.Equal(t, "Intro Body", repo.translations[0].Excerpt)
+
+    result, _ = s.SaveTranslation(ctx, &models.PostTranslation{ PostID: 1, Locale: "fr", Title: "Bonjour", Content: "<p>Texte</p>" })
+    assert.NotNil(t, result.Locale)
+    _, err = s.SaveTranslation(ctx, &models.PostTranslation{ PostID: 2, Locale: "en", Title: "Hello", Content: "<p>Body</p>" })
+    assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+    status := true
+    localize := func(requested ...string) *models.Post {
+        post, err := s.GetPost(ctx, &status, 1, nil, nil)
+        assert.NoError(t, err)
+        s.Localize(ctx, post, requested)
+        return post
+    }
+
+    // 요청한 언어의 번역이 있을 경우 번역을 사용한다.
+    post := localize("en-US", "ko")
+    assert.Equal(t, "en", post.Locale)
+    assert.Equal(t, "Hello", post.Title)
+    assert.Equal(t, "hello", post.Slug)
+    assert.Equal(t, "thumbnail.png", post.Thumbnail)
+    assert.Equal(t, []string{"ko", "en"}, post.Locales)
+    assert.Equal(t, "intro", post.TOC[0].ID)
+
+    // 원문의 언어를 요청한 경우 원문을 사용한다.
+    assert.Equal(t, "안녕하세요", localize("ko", "en").Title)
+    // 번역이 없는 언어는 대체 언어를 사용한다.
+    assert.Equal(t, "en", localize("ja").Locale)
+
+    // 번역의 slug로 조회할 경우 번역의 언어를 사용한다.
+    post, moved, err := s.GetPostBySlug(ctx, &status, 1, "hello", nil, nil)
+    assert.NoError(t, err)
+    assert.False(t, moved)
+    s.Localize(ctx, post, []string{"ko"})
+    assert.Equal(t, "Hello", post.Title)
+
+    posts := []models.Post{ repo.posts[0] }
+    s.LocalizePosts(ctx, posts, []string{"en"})
+    assert.Equal(t, "Hello", posts[0].Title)
+    assert.Empty(t, posts[0].Content)
 }
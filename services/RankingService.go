@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"okra_board2/config"
+	"okra_board2/logging"
+	"okra_board2/metrics"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+    RankingWindowDay    = "day"
+    RankingWindowWeek   = "week"
+    RankingWindowMonth  = "month"
+    RankingWindowAll    = "all"
+
+    RankingSortViews    = "views"
+    RankingSortTrending = "trending"
+)
+
+var ErrInvalidRankingWindow = errors.New("Invalid ranking window.")
+var ErrInvalidRankingSort = errors.New("Invalid ranking sort.")
+
+// 각 기간별 조회 기록의 시작일 (오늘 기준 일수)
+var rankingWindowDays = map[string]int {
+    RankingWindowDay:   1,
+    RankingWindowWeek:  7,
+    RankingWindowMonth: 30,
+}
+
+type RankingService interface {
+
+    // 게시물의 조회수를 1 증가시킨다.
+    RecordView(ctx context.Context, postId int) (err error)
+
+    // 캐시된 게시물 순위를 불러온다.
+    // window: day, week, month, all 중 하나. 해당 기간의 조회수로 순위를 매긴다.
+    // sortBy: views일 경우 기간 내 조회수, trending일 경우 시간에 따라 감쇠된 점수로 정렬한다.
+    // boardId 속성이 nil일 경우 전체 게시판에서 순위를 매긴다.
+    GetRanking(
+        window, sortBy string,
+        boardId *int,
+        size int,
+    )                               (posts []models.RankedPost, err error)
+
+    // db에서 조회 기록을 불러와 순위 캐시를 갱신한다.
+    Refresh(ctx context.Context)    (err error)
+
+    // ctx가 종료될 때 까지 설정된 주기로 순위 캐시를 갱신한다.
+    Run(ctx context.Context)
+
+    // Run이 실행 중이며 마지막 갱신이 성공했고 갱신 주기의 3배 이내에 이루어졌을 경우 nil을 반환한다.
+    Health()                        (err error)
+
+}
+
+type RankingServiceImpl struct {
+    rankingRepo     repositories.RankingRepository
+    conf            *config.Config
+    location        *time.Location
+
+    mutex           sync.RWMutex
+    rankings        map[string][]models.RankedPost
+
+    // 백그라운드 갱신 상태. mutex로 보호된다.
+    running         bool
+    refreshedAt     time.Time
+    refreshErr      error
+}
+
+func NewRankingServiceImpl(
+    rankingRepo repositories.RankingRepository,
+    conf *config.Config,
+) RankingService {
+    location, err := time.LoadLocation("Asia/Seoul")
+    if err != nil { location = time.Local }
+    return &RankingServiceImpl{
+        rankingRepo: rankingRepo,
+        conf: conf,
+        location: location,
+        rankings: make(map[string][]models.RankedPost),
+    }
+}
+
+func (s *RankingServiceImpl) today() time.Time {
+    now := time.Now().In(s.location)
+    return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.location)
+}
+
+func (s *RankingServiceImpl) refreshInterval() time.Duration {
+    if s.conf.Ranking.RefreshInterval <= 0 {
+        return 5 * time.Minute
+    }
+    return time.Duration(s.conf.Ranking.RefreshInterval) * time.Second
+}
+
+func (s *RankingServiceImpl) halfLife() float64 {
+    if s.conf.Ranking.HalfLife <= 0 {
+        return 24
+    }
+    return float64(s.conf.Ranking.HalfLife)
+}
+
+// 일자별 조회수에 반감기를 적용한 점수.
+// 조회 기록은 하루 단위로 집계되므로 해당 일자의 정오를 기준으로 경과 시간을 계산한다.
+func (s *RankingServiceImpl) decayedScore(view models.PostView, now time.Time) float64 {
+    viewDate := time.Date(
+        view.ViewDate.Year(), view.ViewDate.Month(), view.ViewDate.Day(),
+        12, 0, 0, 0, s.location,
+    )
+    age := math.Max(now.Sub(viewDate).Hours(), 0)
+    return float64(view.Count) * math.Pow(0.5, age / s.halfLife())
+}
+
+func (s *RankingServiceImpl) RecordView(ctx context.Context, postId int) (err error) {
+    return s.rankingRepo.InsertView(ctx, postId, s.today())
+}
+
+func (s *RankingServiceImpl) Refresh(ctx context.Context) (err error) {
+    defer func(start time.Time) { 
+        metrics.ObserveJob("ranking_refresh", start, err)
+        s.mutex.Lock()
+        s.refreshedAt, s.refreshErr = time.Now(), err
+        s.mutex.Unlock()
+    }(time.Now())
+    now := time.Now().In(s.location)
+    today := s.today()
+    since := today.AddDate(0, 0, -rankingWindowDays[RankingWindowMonth] + 1)
+
+    infos := s.rankingRepo.GetPublishedPostsInfo(ctx)
+    views := s.rankingRepo.GetDailyViews(ctx, since)
+
+    rankings := make(map[string][]models.RankedPost)
+    for _, window := range []string{ RankingWindowDay, RankingWindowWeek, RankingWindowMonth, RankingWindowAll } {
+        windowViews := make(map[int]int)
+        scores := make(map[int]float64)
+        for _, view := range views {
+            // 기간 밖의 조회는 조회수와 trending 점수에 포함하지 않는다.
+            if days, ok := rankingWindowDays[window]; ok {
+                if view.ViewDate.Before(today.AddDate(0, 0, -days + 1)) { continue }
+                windowViews[view.PostID] += view.Count
+            }
+            scores[view.PostID] += s.decayedScore(view, now)
+        }
+
+        ranked := make([]models.RankedPost, 0)
+        for _, info := range infos {
+            if window != RankingWindowAll {
+                info.Views = windowViews[info.PostID]
+            }
+            info.Score = scores[info.PostID]
+            if info.Views > 0 {
+                ranked = append(ranked, info)
+            }
+        }
+
+        byViews := make([]models.RankedPost, len(ranked))
+        copy(byViews, ranked)
+        sort.SliceStable(byViews, func(i, j int) bool {
+            if byViews[i].Views != byViews[j].Views {
+                return byViews[i].Views > byViews[j].Views
+            }
+            if byViews[i].Score != byViews[j].Score {
+                return byViews[i].Score > byViews[j].Score
+            }
+            return byViews[i].PostID > byViews[j].PostID
+        })
+        rankings[window + ":" + RankingSortViews] = byViews
+
+        byScore := ranked
+        sort.SliceStable(byScore, func(i, j int) bool {
+            if byScore[i].Score != byScore[j].Score {
+                return byScore[i].Score > byScore[j].Score
+            }
+            if byScore[i].Views != byScore[j].Views {
+                return byScore[i].Views > byScore[j].Views
+            }
+            return byScore[i].PostID > byScore[j].PostID
+        })
+        rankings[window + ":" + RankingSortTrending] = byScore
+    }
+
+    s.mutex.Lock()
+    s.rankings = rankings
+    s.mutex.Unlock()
+    return nil
+}
+
+func (s *RankingServiceImpl) GetRanking(
+    window, sortBy string,
+    boardId *int,
+    size int,
+) (posts []models.RankedPost, err error) {
+    if _, ok := rankingWindowDays[window]; !ok && window != RankingWindowAll {
+        return nil, ErrInvalidRankingWindow
+    }
+    if sortBy != RankingSortViews && sortBy != RankingSortTrending {
+        return nil, ErrInvalidRankingSort
+    }
+
+    s.mutex.RLock()
+    ranked := s.rankings[window + ":" + sortBy]
+    s.mutex.RUnlock()
+
+    posts = []models.RankedPost{}
+    for _, post := range ranked {
+        if len(posts) >= size { break }
+        if boardId != nil && post.BoardID != *boardId { continue }
+        posts = append(posts, post)
+    }
+    return
+}
+
+func (s *RankingServiceImpl) Run(ctx context.Context) {
+    s.setRunning(true)
+    defer s.setRunning(false)
+    if err := s.Refresh(ctx); err != nil {
+        logging.FromContext(ctx).Error("게시물 순위 갱신에 실패했습니다.", "error", err)
+    }
+    ticker := time.NewTicker(s.refreshInterval())
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+            if err := s.Refresh(ctx); err != nil {
+                logging.FromContext(ctx).Error("게시물 순위 갱신에 실패했습니다.", "error", err)
+            }
+        }
+    }
+}
+
+func (s *RankingServiceImpl) setRunning(running bool) {
+    s.mutex.Lock()
+    defer s.mutex.Unlock()
+    s.running = running
+}
+
+func (s *RankingServiceImpl) Health() error {
+    s.mutex.RLock()
+    defer s.mutex.RUnlock()
+    if !s.running {
+        return errors.New("Ranking worker is not running.")
+    }
+    if s.refreshErr != nil {
+        return s.refreshErr
+    }
+    if s.refreshedAt.IsZero() {
+        return errors.New("Rankings have not been refreshed yet.")
+    }
+    if time.Since(s.refreshedAt) > 3 * s.refreshInterval() {
+        return fmt.Errorf("Rankings were last refreshed at %s.", s.refreshedAt.Format(time.RFC3339))
+    }
+    return nil
+}
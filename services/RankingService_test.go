@@ -0,0 +1,113 @@
+package services_test
+
+import (
+	"context"
+	"okra_board2/config"
+	"okra_board2/models"
+	"okra_board2/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rankingRepositoryStub struct {
+    infos   []models.RankedPost
+    views   []models.PostView
+}
+
+func (r *rankingRepositoryStub) InsertView(ctx context.Context, postId int, viewDate time.Time) error {
+    r.views = append(r.views, models.PostView{ PostID: postId, ViewDate: viewDate, Count: 1 })
+    return nil
+}
+
+func (r *rankingRepositoryStub) GetDailyViews(ctx context.Context, since time.Time) (views []models.PostView) {
+    for _, view := range r.views {
+        if !view.ViewDate.Before(since) {
+            views = append(views, view)
+        }
+    }
+    return
+}
+
+func (r *rankingRepositoryStub) GetPublishedPostsInfo(ctx context.Context) []models.RankedPost {
+    return r.infos
+}
+
+func TestRankingService(t *testing.T) {
+    seoul, _ := time.LoadLocation("Asia/Seoul")
+    now := time.Now().In(seoul)
+    today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, seoul)
+
+    repo := &rankingRepositoryStub{
+        infos: []models.RankedPost {
+            { PostID: 1, BoardID: 1, Title: "old but popular", Views: 100 },
+            { PostID: 2, BoardID: 1, Title: "fresh", Views: 5 },
+            { PostID: 3, BoardID: 2, Title: "other board", Views: 3 },
+        },
+        views: []models.PostView {
+            { PostID: 1, ViewDate: today.AddDate(0, 0, -5), Count: 20 },
+            { PostID: 2, ViewDate: today, Count: 5 },
+            { PostID: 3, ViewDate: today.AddDate(0, 0, -1), Count: 3 },
+        },
+    }
+    s := services.NewRankingServiceImpl(repo, &config.Config{})
+    assert.Nil(t, s.Refresh(context.Background()))
+
+    // 오늘 조회된 게시물만 포함
+    posts, err := s.GetRanking(services.RankingWindowDay, services.RankingSortViews, nil, 10)
+    assert.Nil(t, err)
+    assert.Equal(t, 1, len(posts))
+    assert.Equal(t, 2, posts[0].PostID)
+
+    // 기간 내 조회수 순
+    posts, _ = s.GetRanking(services.RankingWindowWeek, services.RankingSortViews, nil, 10)
+    assert.Equal(t, []int{1, 2, 3}, postIds(posts))
+    assert.Equal(t, 20, posts[0].Views)
+
+    // 최근 조회된 게시물이 더 높은 점수를 갖는다.
+    posts, _ = s.GetRanking(services.RankingWindowWeek, services.RankingSortTrending, nil, 10)
+    assert.Equal(t, 2, posts[0].PostID)
+
+    // 누적 조회수 및 게시판 필터
+    boardId := 1
+    posts, _ = s.GetRanking(services.RankingWindowAll, services.RankingSortViews, &boardId, 1)
+    assert.Equal(t, []int{1}, postIds(posts))
+    assert.Equal(t, 100, posts[0].Views)
+
+    _, err = s.GetRanking("year", services.RankingSortViews, nil, 10)
+    assert.Equal(t, services.ErrInvalidRankingWindow, err)
+}
+
+func TestRankingServiceTrendingWindow(t *testing.T) {
+    seoul, _ := time.LoadLocation("Asia/Seoul")
+    now := time.Now().In(seoul)
+    today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, seoul)
+
+    repo := &rankingRepositoryStub{
+        infos: []models.RankedPost {
+            { PostID: 1, BoardID: 1, Title: "popular last week" },
+            { PostID: 2, BoardID: 1, Title: "popular today" },
+        },
+        views: []models.PostView {
+            { PostID: 1, ViewDate: today.AddDate(0, 0, -3), Count: 1000 },
+            { PostID: 1, ViewDate: today, Count: 1 },
+            { PostID: 2, ViewDate: today, Count: 2 },
+        },
+    }
+    s := services.NewRankingServiceImpl(repo, &config.Config{})
+    assert.Nil(t, s.Refresh(context.Background()))
+
+    // 기간 밖의 조회는 점수에 포함되지 않는다.
+    posts, _ := s.GetRanking(services.RankingWindowDay, services.RankingSortTrending, nil, 10)
+    assert.Equal(t, []int{2, 1}, postIds(posts))
+    posts, _ = s.GetRanking(services.RankingWindowWeek, services.RankingSortTrending, nil, 10)
+    assert.Equal(t, []int{1, 2}, postIds(posts))
+}
+
+func postIds(posts []models.RankedPost) (ids []int) {
+    for _, post := range posts {
+        ids = append(ids, post.PostID)
+    }
+    return
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"okra_board2/config"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"time"
+)
+
+// 항상 사용할 수 있는 기본 반응
+const ReactionLike = "like"
+
+var ErrUnknownReaction = errors.New("Unknown reaction type.")
+
+type ReactionService interface {
+
+    // 사용할 수 있는 반응 종류를 반환한다. 첫 번째는 항상 like이다.
+    GetReactionTypes()                  (types []string)
+
+    // 공개된 게시물에 방문자의 반응을 추가하고 게시물의 반응 종류별 개수를 반환한다.
+    // 방문자가 이미 같은 반응을 남긴 경우 개수는 변하지 않는다.
+    // 게시물이 없을 경우 gorm.ErrRecordNotFound를, 사용할 수 없는 반응일 경우 ErrUnknownReaction을 반환한다.
+    React(ctx context.Context, postId int, reactionType, visitorId string) (counts map[string]int, err error)
+
+    // 방문자의 반응을 취소하고 게시물의 반응 종류별 개수를 반환한다.
+    Unreact(ctx context.Context, postId int, reactionType, visitorId string) (counts map[string]int, err error)
+
+    // 게시물의 반응 종류별 개수를 반환한다.
+    GetReactionCounts(ctx context.Context, postId int) (counts map[string]int)
+
+    // 게시물들의 반응 종류별 개수를 posts에 추가한다.
+    SetReactionCounts(ctx context.Context, posts []models.Post)
+
+    // 반응이 많은 공개된 게시물을 순서대로 불러온다.
+    // reactionType이 nil이 아닐 경우 해당 종류의 반응만 세며, days가 0보다 클 경우 최근 days일의 반응만 센다.
+    GetReactionRanking(
+        ctx context.Context,
+        reactionType *string,
+        days int,
+        size int,
+    )                                   (posts []models.ReactedPost, err error)
+
+}
+
+type ReactionServiceImpl struct {
+    reactionRepo    repositories.ReactionRepository
+    postRepo        repositories.PostRepository
+    conf            *config.Config
+}
+
+func NewReactionServiceImpl(
+    reactionRepo repositories.ReactionRepository,
+    postRepo repositories.PostRepository,
+    conf *config.Config,
+) ReactionService {
+    return &ReactionServiceImpl{
+        reactionRepo: reactionRepo,
+        postRepo: postRepo,
+        conf: conf,
+    }
+}
+
+func (s *ReactionServiceImpl) GetReactionTypes() (types []string) {
+    emojis := s.conf.Reaction.Emojis
+    if len(emojis) == 0 {
+        emojis = []string{"love", "haha", "wow", "sad"}
+    }
+    types = append(types, ReactionLike)
+    for _, emoji := range emojis {
+        if emoji != ReactionLike { types = append(types, emoji) }
+    }
+    return
+}
+
+func (s *ReactionServiceImpl) checkType(reactionType string) error {
+    for _, t := range s.GetReactionTypes() {
+        if t == reactionType { return nil }
+    }
+    return ErrUnknownReaction
+}
+
+func (s *ReactionServiceImpl) React(ctx context.Context, postId int, reactionType, visitorId string) (counts map[string]int, err error) {
+    if err = s.checkType(reactionType); err != nil { return }
+    status := true
+    if _, err = s.postRepo.GetPost(ctx, &status, postId); err != nil { return }
+
+    _, err = s.reactionRepo.AddReaction(ctx, &models.Reaction{
+        PostID: postId,
+        Type: reactionType,
+        VisitorID: visitorId,
+    })
+    if err != nil { return }
+    return s.GetReactionCounts(ctx, postId), nil
+}
+
+func (s *ReactionServiceImpl) Unreact(ctx context.Context, postId int, reactionType, visitorId string) (counts map[string]int, err error) {
+    if err = s.checkType(reactionType); err != nil { return }
+    status := true
+    if _, err = s.postRepo.GetPost(ctx, &status, postId); err != nil { return }
+
+    err = s.reactionRepo.RemoveReaction(ctx, &models.Reaction{
+        PostID: postId,
+        Type: reactionType,
+        VisitorID: visitorId,
+    })
+    if err != nil { return }
+    return s.GetReactionCounts(ctx, postId), nil
+}
+
+func (s *ReactionServiceImpl) GetReactionCounts(ctx context.Context, postId int) (counts map[string]int) {
+    counts = s.reactionRepo.GetReactionCounts(ctx, []int{postId})[postId]
+    if counts == nil {
+        counts = make(map[string]int)
+    }
+    return
+}
+
+func (s *ReactionServiceImpl) SetReactionCounts(ctx context.Context, posts []models.Post) {
+    postIds := make([]int, len(posts))
+    for i := range posts {
+        postIds[i] = posts[i].PostID
+    }
+    counts := s.reactionRepo.GetReactionCounts(ctx, postIds)
+    for i := range posts {
+        posts[i].Reactions = counts[posts[i].PostID]
+    }
+}
+
+func (s *ReactionServiceImpl) GetReactionRanking(
+    ctx context.Context,
+    reactionType *string,
+    days int,
+    size int,
+) (posts []models.ReactedPost, err error) {
+    if reactionType != nil {
+        if err = s.checkType(*reactionType); err != nil { return }
+    }
+    var since *time.Time
+    if days > 0 {
+        temp := time.Now().AddDate(0, 0, -days)
+        since = &temp
+    }
+    posts = s.reactionRepo.GetReactionRanking(ctx, reactionType, since, size)
+    if posts == nil {
+        posts = make([]models.ReactedPost, 0)
+    }
+    return
+}
@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"context"
+	"okra_board2/config"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"okra_board2/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type reactionRepositoryStub struct {
+    repositories.ReactionRepository
+    reactions []models.Reaction
+}
+
+func (r *reactionRepositoryStub) AddReaction(ctx context.Context, reaction *models.Reaction) (bool, error) {
+    for _, existing := range r.reactions {
+        if existing.PostID == reaction.PostID && existing.Type == reaction.Type &&
+            existing.VisitorID == reaction.VisitorID {
+            return false, nil
+        }
+    }
+    r.reactions = append(r.reactions, *reaction)
+    return true, nil
+}
+
+func (r *reactionRepositoryStub) RemoveReaction(ctx context.Context, reaction *models.Reaction) error {
+    for i, existing := range r.reactions {
+        if existing.PostID == reaction.PostID && existing.Type == reaction.Type &&
+            existing.VisitorID == reaction.VisitorID {
+            r.reactions = append(r.reactions[:i], r.reactions[i+1:]...)
+            break
+        }
+    }
+    return nil
+}
+
+func (r *reactionRepositoryStub) GetReactionCounts(ctx context.Context, postIds []int) map[int]map[string]int {
+    counts := make(map[int]map[string]int)
+    for _, reaction := range r.reactions {
+        for _, postId := range postIds {
+            if reaction.PostID != postId { continue }
+            if counts[postId] == nil { counts[postId] = make(map[string]int) }
+            counts[postId][reaction.Type]++
+        }
+    }
+    return counts
+}
+
+func (r *reactionRepositoryStub) GetReactionRanking(
+    ctx context.Context, reactionType *string, since *time.Time, size int,
+) []models.ReactedPost {
+    return nil
+}
+
+func TestReactionService(t *testing.T) {
+    repo := &reactionRepositoryStub{}
+    postRepo := &singlePostRepositoryStub{ post: &models.Post{ PostID: 1, Status: true } }
+    conf := &config.Config{ Reaction: config.ReactionConfig{ Emojis: []string{"love", "like"} } }
+    s := services.NewReactionServiceImpl(repo, postRepo, conf)
+
+    assert.Equal(t, []string{"like", "love"}, s.GetReactionTypes())
+
+    // 방문자별로 한 번씩만 반영된다.
+    s.React(context.Background(), 1, "like", "visitor1")
+    s.React(context.Background(), 1, "like", "visitor1")
+    s.React(context.Background(), 1, "love", "visitor1")
+    counts, err := s.React(context.Background(), 1, "like", "visitor2")
+    assert.NoError(t, err)
+    assert.Equal(t, map[string]int{ "like": 2, "love": 1 }, counts)
+
+    counts, err = s.Unreact(context.Background(), 1, "love", "visitor1")
+    assert.NoError(t, err)
+    assert.Equal(t, map[string]int{ "like": 2 }, counts)
+
+    _, err = s.React(context.Background(), 1, "angry", "visitor1")
+    assert.Equal(t, services.ErrUnknownReaction, err)
+    _, err = s.React(context.Background(), 2, "like", "visitor1")
+    assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+    posts := []models.Post{{ PostID: 1 }, { PostID: 2 }}
+    s.SetReactionCounts(context.Background(), posts)
+    assert.Equal(t, 2, posts[0].Reactions["like"])
+    assert.Nil(t, posts[1].Reactions)
+
+    ranking, err := s.GetReactionRanking(context.Background(), nil, 7, 10)
+    assert.NoError(t, err)
+    assert.Empty(t, ranking)
+}
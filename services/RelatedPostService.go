@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"math"
+	"okra_board2/logging"
+	"okra_board2/metrics"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"okra_board2/utils/debounce"
+	"okra_board2/utils/htmltext"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// 게시물마다 미리 계산해 둘 연관 게시물의 최대 개수
+const MaxRelatedPosts = 10
+
+// 태그가 겹치지 않는 게시물을 연관 게시물로 인정할 최소 내용 유사도
+const minContentSimilarity = 0.05
+
+// 마지막 변경 후 연관 게시물 목록을 다시 계산하기까지 기다리는 시간
+const relatedRebuildDelay = time.Second
+
+type RelatedPostService interface {
+
+    // 공개된 게시물들의 태그와 내용을 분석하여 연관 게시물 목록을 다시 계산한다.
+    Rebuild(ctx context.Context)            (err error)
+
+    // 연관 게시물 목록을 다시 계산해야 함을 기록한다.
+    // 게시물이나 태그가 변경될 때마다 호출되어야 하며, 목록은 Run에서 변경이 멈춘 뒤 한 번에 다시 계산된다.
+    MarkDirty()
+
+    // ctx가 종료될 때 까지 MarkDirty로 기록된 변경을 모아 연관 게시물 목록을 다시 계산한다.
+    Run(ctx context.Context)
+
+    // 미리 계산된 연관 게시물 중 점수가 높은 순으로 최대 size개를 반환한다.
+    // 점수는 겹치는 태그의 개수에 내용의 코사인 유사도(0~1)를 더한 값이다.
+    GetRelatedPosts(postId, size int)       (posts []models.RelatedPost)
+
+}
+
+type RelatedPostServiceImpl struct {
+    postRepo    repositories.PostRepository
+
+    rebuilder   *debounce.Debouncer
+
+    mutex       sync.RWMutex
+    related     map[int][]models.RelatedPost
+}
+
+func NewRelatedPostServiceImpl(postRepo repositories.PostRepository) RelatedPostService {
+    return &RelatedPostServiceImpl{
+        postRepo: postRepo,
+        rebuilder: debounce.New(relatedRebuildDelay),
+        related: make(map[int][]models.RelatedPost),
+    }
+}
+
+// 텍스트를 소문자 단어로 분리한다.
+// 한글 단어는 조사가 붙어도 비교할 수 있도록 두 글자씩 나눈다.
+func (s *RelatedPostServiceImpl) tokenize(text string) (tokens []string) {
+    words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+        return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+    })
+    for _, word := range words {
+        runes := []rune(word)
+        if len(runes) < 2 { continue }
+        if unicode.Is(unicode.Hangul, runes[0]) {
+            for i := 0; i+1 < len(runes); i++ {
+                tokens = append(tokens, string(runes[i:i+2]))
+            }
+        } else {
+            tokens = append(tokens, word)
+        }
+    }
+    return
+}
+
+// 게시물들의 TF-IDF 벡터를 계산한다. 각 벡터는 크기가 1로 정규화된다.
+func (s *RelatedPostServiceImpl) vectorize(posts []models.Post) []map[string]float64 {
+    termFreqs := make([]map[string]float64, len(posts))
+    docFreqs := make(map[string]int)
+    for i, post := range posts {
+        tf := make(map[string]float64)
+        for _, token := range s.tokenize(post.Title + " " + htmltext.ExtractText(post.Content)) {
+            tf[token]++
+        }
+        for token := range tf {
+            docFreqs[token]++
+        }
+        termFreqs[i] = tf
+    }
+
+    n := float64(len(posts))
+    for _, tf := range termFreqs {
+        var norm float64
+        for token, freq := range tf {
+            tf[token] = freq * math.Log(1 + n / float64(docFreqs[token]))
+            norm += tf[token] * tf[token]
+        }
+        norm = math.Sqrt(norm)
+        for token := range tf {
+            tf[token] /= norm
+        }
+    }
+    return termFreqs
+}
+
+func (s *RelatedPostServiceImpl) Rebuild(ctx context.Context) (err error) {
+    defer func(start time.Time) { metrics.ObserveJob("related_rebuild", start, err) }(time.Now())
+    posts := s.postRepo.GetAllPublishedPosts(ctx)
+    vectors := s.vectorize(posts)
+
+    tags := make([]map[int]struct{}, len(posts))
+    for i, post := range posts {
+        tags[i] = make(map[int]struct{})
+        for _, tag := range post.Tags {
+            tags[i][tag.TagID] = struct{}{}
+        }
+    }
+
+    candidates := make([][]models.RelatedPost, len(posts))
+    for i := range posts {
+        for j := i + 1; j < len(posts); j++ {
+            overlap := 0
+            for tag := range tags[i] {
+                if _, ok := tags[j][tag]; ok { overlap++ }
+            }
+            var similarity float64
+            a, b := vectors[i], vectors[j]
+            if len(a) > len(b) { a, b = b, a }
+            for token, weight := range a {
+                similarity += weight * b[token]
+            }
+            if overlap == 0 && similarity < minContentSimilarity { continue }
+
+            score := float64(overlap) + similarity
+            candidates[i] = append(candidates[i], models.RelatedPost{
+                PostID: posts[j].PostID,
+                BoardID: posts[j].BoardID,
+                Slug: posts[j].Slug,
+                Title: posts[j].Title,
+                Thumbnail: posts[j].Thumbnail,
+                Score: score,
+            })
+            candidates[j] = append(candidates[j], models.RelatedPost{
+                PostID: posts[i].PostID,
+                BoardID: posts[i].BoardID,
+                Slug: posts[i].Slug,
+                Title: posts[i].Title,
+                Thumbnail: posts[i].Thumbnail,
+                Score: score,
+            })
+        }
+    }
+
+    related := make(map[int][]models.RelatedPost, len(posts))
+    for i, post := range posts {
+        list := candidates[i]
+        sort.SliceStable(list, func(a, b int) bool {
+            if list[a].Score != list[b].Score {
+                return list[a].Score > list[b].Score
+            }
+            return list[a].PostID > list[b].PostID
+        })
+        if len(list) > MaxRelatedPosts {
+            list = list[:MaxRelatedPosts]
+        }
+        related[post.PostID] = list
+    }
+
+    s.mutex.Lock()
+    s.related = related
+    s.mutex.Unlock()
+    return nil
+}
+
+func (s *RelatedPostServiceImpl) MarkDirty() {
+    s.rebuilder.Mark()
+}
+
+func (s *RelatedPostServiceImpl) Run(ctx context.Context) {
+    s.rebuilder.Run(ctx, func(ctx context.Context) {
+        if err := s.Rebuild(ctx); err != nil {
+            logging.FromContext(ctx).Error("연관 게시글 생성에 실패했습니다.", "error", err)
+        }
+    })
+}
+
+func (s *RelatedPostServiceImpl) GetRelatedPosts(postId, size int) (posts []models.RelatedPost) {
+    s.mutex.RLock()
+    defer s.mutex.RUnlock()
+    list := s.related[postId]
+    if size < len(list) {
+        list = list[:size]
+    }
+    posts = make([]models.RelatedPost, len(list))
+    copy(posts, list)
+    return
+}
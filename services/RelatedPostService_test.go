@@ -0,0 +1,83 @@
+package services_test
+
+import (
+	"context"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"okra_board2/services"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type publishedPostRepositoryStub struct {
+    repositories.PostRepository
+    posts []models.Post
+    loads atomic.Int32
+}
+
+func (r *publishedPostRepositoryStub) GetAllPublishedPosts(ctx context.Context) []models.Post {
+    r.loads.Add(1)
+    return r.posts
+}
+
+func TestRelatedPostService(t *testing.T) {
+    repo := &publishedPostRepositoryStub{
+        posts: []models.Post {
+            {
+                PostID: 1, Title: "오크라 수확 일지",
+                Content: "<p>오늘은 오크라를 수확했습니다.</p>",
+                Tags: []models.Tag{{ TagID: 1, Name: "okra" }, { TagID: 2, Name: "farm" }},
+            },
+            {
+                PostID: 2, Title: "오크라 요리법",
+                Content: "<p>수확한 오크라로 요리를 했습니다.</p>",
+                Tags: []models.Tag{{ TagID: 1, Name: "okra" }, { TagID: 3, Name: "recipe" }},
+            },
+            {
+                PostID: 3, Title: "농장 소식",
+                Content: "<p>새로운 트랙터가 도착했습니다.</p>",
+                Tags: []models.Tag{{ TagID: 2, Name: "farm" }},
+            },
+            {
+                PostID: 4, Title: "Notice",
+                Content: "<p>Server maintenance</p>",
+            },
+        },
+    }
+    s := services.NewRelatedPostServiceImpl(repo)
+    assert.Nil(t, s.Rebuild(context.Background()))
+
+    // 태그와 내용이 모두 겹치는 게시물이 우선한다.
+    related := s.GetRelatedPosts(1, 10)
+    assert.Equal(t, 2, len(related))
+    assert.Equal(t, 2, related[0].PostID)
+    assert.Equal(t, 3, related[1].PostID)
+
+    assert.Equal(t, 1, len(s.GetRelatedPosts(1, 1)))
+    assert.Equal(t, 0, len(s.GetRelatedPosts(4, 10)))
+}
+
+func TestRelatedPostServiceRun(t *testing.T) {
+    repo := &publishedPostRepositoryStub{
+        posts: []models.Post {
+            { PostID: 1, Title: "okra", Tags: []models.Tag{{ TagID: 1, Name: "okra" }} },
+            { PostID: 2, Title: "farm", Tags: []models.Tag{{ TagID: 1, Name: "okra" }} },
+        },
+    }
+    s := services.NewRelatedPostServiceImpl(repo)
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    go s.Run(ctx)
+
+    // 연속된 변경은 한 번의 계산으로 합쳐진다.
+    s.MarkDirty()
+    s.MarkDirty()
+    s.MarkDirty()
+    assert.Eventually(t, func() bool {
+        return len(s.GetRelatedPosts(1, 10)) == 1
+    }, 3 * time.Second, 10 * time.Millisecond)
+    assert.Equal(t, int32(1), repo.loads.Load())
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"okra_board2/config"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"okra_board2/utils/htmltext"
+	"okra_board2/utils/permalink"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 자동 생성되는 meta description의 최대 글자 수
+const metaDescriptionLength = 160
+
+type SeoService interface {
+
+    // 공개된 게시물의 검색 엔진 및 Open Graph 메타데이터를 생성한다.
+    // 게시물에 지정되지 않은 항목은 다음과 같이 생성된다.
+    // title: 게시물 제목
+    // description: 본문의 첫 번째 문단
+    // canonicalUrl: 사이트의 게시물 주소
+    // image: 썸네일의 첫 번째 이미지
+    // 번역이 있을 경우 요청한 언어(requested)의 번역으로 생성하며, 언어별 게시물 주소를 함께 제공한다.
+    // 공개되지 않은 게시물일 경우 gorm.ErrRecordNotFound를 반환한다.
+    GetPostMeta(
+        ctx context.Context,
+        postId int,
+        requested []string,
+    )                               (meta *models.PostMeta, err error)
+
+}
+
+type SeoServiceImpl struct {
+    postRepo    repositories.PostRepository
+    conf        *config.Config
+}
+
+func NewSeoServiceImpl(
+    postRepo repositories.PostRepository,
+    conf *config.Config,
+) SeoService {
+    return &SeoServiceImpl{
+        postRepo: postRepo,
+        conf: conf,
+    }
+}
+
+// 값이 비어있지 않으면 값을, 비어있으면 defaultValue를 반환한다.
+func orDefault(value *string, defaultValue string) string {
+    if value != nil && strings.TrimSpace(*value) != "" {
+        return strings.TrimSpace(*value)
+    }
+    return defaultValue
+}
+
+func sortedKeys(m map[string]string) []string {
+    keys := make([]string, 0, len(m))
+    for key := range m {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    return keys
+}
+
+// 게시물 페이지의 전체 주소. 게시물의 언어가 지정되지 않은 경우 원문의 언어를 사용한다.
+func postURL(conf *config.Config, post *models.Post) string {
+    locale := post.Locale
+    if locale == "" {
+        locale = conf.Site.DefaultLocale()
+    }
+    return permalink.Post(conf.Site.URL, conf.Site.PostPath, post.PostID, post.BoardID, post.Slug, locale)
+}
+
+// 게시물의 canonical 주소
+func canonicalURL(conf *config.Config, post *models.Post) string {
+    return orDefault(post.CanonicalURL, postURL(conf, post))
+}
+
+func (s *SeoServiceImpl) GetPostMeta(ctx context.Context, postId int, requested []string) (meta *models.PostMeta, err error) {
+    status := true
+    post, err := s.postRepo.GetPost(ctx, &status, postId)
+    if err != nil { return }
+
+    // 언어별 주소는 번역을 적용하기 전의 원문으로부터 만든다.
+    translations := s.postRepo.GetTranslations(ctx, []int{ postId }, nil)
+    var alternates map[string]string
+    if len(translations) > 0 {
+        alternates = map[string]string{ s.conf.Site.DefaultLocale(): canonicalURL(s.conf, post) }
+        for i := range translations {
+            localized := *post
+            applyTranslation(&localized, &translations[i])
+            alternates[translations[i].Locale] = canonicalURL(s.conf, &localized)
+        }
+    }
+    post.Locale = s.conf.Site.DefaultLocale()
+    if translation := selectTranslation(s.conf, translations, requested); translation != nil {
+        applyTranslation(post, translation)
+    }
+
+    published := post.AddedDate
+    if post.PublishedDate != nil { published = *post.PublishedDate }
+    modified := published
+    if post.UpdatedDate != nil && post.UpdatedDate.After(published) { modified = *post.UpdatedDate }
+
+    meta = &models.PostMeta{
+        Title: orDefault(post.MetaTitle, post.Title),
+        Description: orDefault(
+            post.MetaDescription,
+            htmltext.Truncate(htmltext.FirstParagraph(post.Content), metaDescriptionLength),
+        ),
+        CanonicalURL: canonicalURL(s.conf, post),
+        Image: orDefault(post.OGImage, htmltext.FirstImageSrc(post.Thumbnail)),
+        NoIndex: post.NoIndex != nil && *post.NoIndex,
+        SiteName: s.conf.Site.Title,
+        PublishedTime: published,
+        ModifiedTime: modified,
+        Locale: post.Locale,
+        Alternates: alternates,
+    }
+    for _, tag := range post.Tags {
+        meta.Tags = append(meta.Tags, tag.Name)
+    }
+    meta.JSONLD = s.articleJSONLD(meta)
+    meta.HTML, err = s.metaHTML(meta)
+    return
+}
+
+// schema.org Article
+func (s *SeoServiceImpl) articleJSONLD(meta *models.PostMeta) map[string]interface{} {
+    article := map[string]interface{} {
+        "@context": "https://schema.org",
+        "@type": "Article",
+        "headline": meta.Title,
+        "description": meta.Description,
+        "datePublished": meta.PublishedTime.Format(time.RFC3339),
+        "dateModified": meta.ModifiedTime.Format(time.RFC3339),
+        "inLanguage": meta.Locale,
+        "mainEntityOfPage": map[string]interface{} {
+            "@type": "WebPage",
+            "@id": meta.CanonicalURL,
+        },
+    }
+    if meta.Image != "" {
+        article["image"] = []string{ meta.Image }
+    }
+    if len(meta.Tags) > 0 {
+        article["keywords"] = strings.Join(meta.Tags, ", ")
+    }
+    if meta.SiteName != "" {
+        article["publisher"] = map[string]interface{} {
+            "@type": "Organization",
+            "name": meta.SiteName,
+            "url": s.conf.Site.URL,
+        }
+    }
+    return article
+}
+
+// head에 삽입할 태그들을 생성한다.
+func (s *SeoServiceImpl) metaHTML(meta *models.PostMeta) (string, error) {
+    var sb strings.Builder
+    tag := func(format string, values ...string) {
+        escaped := make([]interface{}, len(values))
+        for i, value := range values {
+            escaped[i] = html.EscapeString(value)
+        }
+        sb.WriteString(fmt.Sprintf(format, escaped...))
+        sb.WriteString("\n")
+    }
+
+    tag(`<title>%s</title>`, meta.Title)
+    tag(`<meta name="description" content="%s">`, meta.Description)
+    tag(`<link rel="canonical" href="%s">`, meta.CanonicalURL)
+    if meta.NoIndex {
+        tag(`<meta name="robots" content="noindex">`)
+    }
+    for _, l := range sortedKeys(meta.Alternates) {
+        tag(`<link rel="alternate" hreflang="%s" href="%s">`, l, meta.Alternates[l])
+    }
+    tag(`<meta property="og:type" content="article">`)
+    tag(`<meta property="og:title" content="%s">`, meta.Title)
+    tag(`<meta property="og:description" content="%s">`, meta.Description)
+    tag(`<meta property="og:url" content="%s">`, meta.CanonicalURL)
+    if meta.SiteName != "" {
+        tag(`<meta property="og:site_name" content="%s">`, meta.SiteName)
+    }
+    if meta.Image != "" {
+        tag(`<meta property="og:image" content="%s">`, meta.Image)
+        tag(`<meta name="twitter:card" content="summary_large_image">`)
+    } else {
+        tag(`<meta name="twitter:card" content="summary">`)
+    }
+    tag(`<meta property="article:published_time" content="%s">`, meta.PublishedTime.Format(time.RFC3339))
+    tag(`<meta property="article:modified_time" content="%s">`, meta.ModifiedTime.Format(time.RFC3339))
+    for _, t := range meta.Tags {
+        tag(`<meta property="article:tag" content="%s">`, t)
+    }
+
+    // json.Marshal은 <, >, &를 이스케이프하므로 script 태그 안에 안전하게 삽입할 수 있다.
+    jsonLD, err := json.Marshal(meta.JSONLD)
+    if err != nil { return "", err }
+    sb.WriteString(`<script type="application/ld+json">`)
+    sb.Write(jsonLD)
+    sb.WriteString("</script>")
+    return sb.String(), nil
+}
@@ -0,0 +1,78 @@
+package services_test
+
+import (
+	"context"
+	"okra_board2/config"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"okra_board2/services"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type singlePostRepositoryStub struct {
+    repositories.PostRepository
+    post *models.Post
+}
+
+func (r *singlePostRepositoryStub) GetPost(ctx context.Context, status *bool, postId int) (*models.Post, error) {
+    if r.post == nil || r.post.PostID != postId || (status != nil && r.post.Status != *status) {
+        return nil, gorm.ErrRecordNotFound
+    }
+    post := *r.post
+    return &post, nil
+}
+
+func (r *singlePostRepositoryStub) GetTranslations(ctx context.Context, postIds []int, locale *string) []models.PostTranslation {
+    return nil
+}
+
+func TestSeoService(t *testing.T) {
+    published := time.Date(2022, 6, 1, 9, 0, 0, 0, time.UTC)
+    repo := &singlePostRepositoryStub{
+        post: &models.Post{
+            PostID: 7,
+            Status: true,
+            Title: `오크라 "수확" 일지`,
+            Thumbnail: `<p><img src="https://cdn.okraseoul.com/images/a.png"/></p>`,
+            Content: `<p><br></p><p>첫 번째 <b>문단</b>입니다.</p><p>두 번째 문단</p>`,
+            PublishedDate: &published,
+            Tags: []models.Tag{{ Name: "okra" }},
+        },
+    }
+    conf := &config.Config{
+        Site: config.SiteConfig{ Title: "Okra Seoul", URL: "https://okraseoul.com" },
+    }
+    s := services.NewSeoServiceImpl(repo, conf)
+
+    meta, err := s.GetPostMeta(context.Background(), 7, nil)
+    assert.Nil(t, err)
+    assert.Equal(t, `오크라 "수확" 일지`, meta.Title)
+    assert.Equal(t, "첫 번째 문단입니다.", meta.Description)
+    assert.Equal(t, "https://okraseoul.com/posts/7", meta.CanonicalURL)
+    assert.Equal(t, "https://cdn.okraseoul.com/images/a.png", meta.Image)
+    assert.False(t, meta.NoIndex)
+    assert.True(t, strings.Contains(meta.HTML, `<meta property="og:title" content="오크라 &#34;수확&#34; 일지">`))
+    assert.True(t, strings.Contains(meta.HTML, `<script type="application/ld+json">{"@context":"https://schema.org"`))
+    assert.False(t, strings.Contains(meta.HTML, "noindex"))
+
+    // 지정된 값이 자동 생성된 값보다 우선한다.
+    description := "직접 입력한 설명"
+    canonical := "https://blog.okraseoul.com/okra"
+    noIndex := true
+    repo.post.MetaDescription = &description
+    repo.post.CanonicalURL = &canonical
+    repo.post.NoIndex = &noIndex
+    meta, _ = s.GetPostMeta(context.Background(), 7, nil)
+    assert.Equal(t, description, meta.Description)
+    assert.Equal(t, canonical, meta.CanonicalURL)
+    assert.True(t, strings.Contains(meta.HTML, `<meta name="robots" content="noindex">`))
+
+    repo.post.Status = false
+    _, err = s.GetPostMeta(context.Background(), 7, nil)
+    assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"okra_board2/config"
+	"okra_board2/logging"
+	"okra_board2/metrics"
+	"okra_board2/repositories"
+	"okra_board2/utils/debounce"
+	"okra_board2/utils/htmltext"
+	"okra_board2/utils/sitemap"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 마지막 변경 후 sitemap을 다시 생성하기까지 기다리는 시간
+const sitemapRegenerateDelay = time.Second
+
+type SitemapService interface {
+
+    // 공개된 게시물들로 sitemap을 다시 생성한다.
+    // noIndex로 지정된 게시물은 제외하며, 게시물의 canonical 주소를 사용한다.
+    // canonical 주소가 사이트와 다른 호스트를 가리키는 게시물은 다른 사이트의 sitemap에 속하므로 제외한다.
+    Regenerate(ctx context.Context) (err error)
+
+    // sitemap을 다시 생성해야 함을 기록한다.
+    // 게시물이 게시, 수정, 삭제될 때마다 호출되어야 하며, sitemap은 Run에서 변경이 멈춘 뒤 한 번에 다시 생성된다.
+    MarkDirty()
+
+    // ctx가 종료될 때 까지 MarkDirty로 기록된 변경을 모아 sitemap을 다시 생성한다.
+    // 생성은 하나의 goroutine에서만 실행되므로 이전 게시물 목록으로 만든 sitemap이 최신 sitemap을 덮어쓰지 않는다.
+    Run(ctx context.Context)
+
+    // /sitemap.xml 문서를 반환한다.
+    // URL이 sitemap.MaxURLs개를 넘을 경우 나누어진 sitemap 파일들의 index를 반환한다.
+    GetSitemap()                    (body []byte)
+
+    // 나누어진 sitemap 중 page번째(1부터 시작) 파일을 반환한다.
+    // 존재하지 않는 파일일 경우 nil을 반환한다.
+    GetSitemapPage(page int)        (body []byte)
+
+}
+
+type SitemapServiceImpl struct {
+    postRepo    repositories.PostRepository
+    conf        *config.Config
+    regenerator *debounce.Debouncer
+
+    mutex       sync.RWMutex
+    sitemap     []byte
+    pages       [][]byte
+}
+
+func NewSitemapServiceImpl(
+    postRepo repositories.PostRepository,
+    conf *config.Config,
+) SitemapService {
+    return &SitemapServiceImpl{
+        postRepo: postRepo,
+        conf: conf,
+        regenerator: debounce.New(sitemapRegenerateDelay),
+    }
+}
+
+func (s *SitemapServiceImpl) pageURL(page int) string {
+    base := s.conf.Site.SitemapBaseURL
+    if base == "" {
+        base = s.conf.Site.URL
+    }
+    return fmt.Sprintf("%s/sitemaps/sitemap-%d.xml", strings.TrimRight(base, "/"), page)
+}
+
+// 주소가 사이트와 같은 호스트를 가리키는지 확인한다.
+func (s *SitemapServiceImpl) onSite(loc string) bool {
+    site, err := url.Parse(s.conf.Site.URL)
+    if err != nil { return false }
+    parsed, err := url.Parse(loc)
+    if err != nil { return false }
+    return strings.EqualFold(parsed.Host, site.Host)
+}
+
+// 본문 이미지의 주소를 sitemap에 사용할 수 있는 절대 주소로 바꾼다.
+// 상대 주소는 사이트 주소를 기준으로 변환하며, http(s)가 아닌 주소(data: 등)는 제외한다.
+func (s *SitemapServiceImpl) imageURLs(srcs []string) (urls []string) {
+    base, err := url.Parse(s.conf.Site.URL)
+    if err != nil { return }
+    for _, src := range srcs {
+        ref, err := url.Parse(strings.TrimSpace(src))
+        if err != nil { continue }
+        resolved := base.ResolveReference(ref)
+        if resolved.Scheme != "http" && resolved.Scheme != "https" { continue }
+        urls = append(urls, resolved.String())
+    }
+    return
+}
+
+func (s *SitemapServiceImpl) Regenerate(ctx context.Context) (err error) {
+    defer func(start time.Time) { metrics.ObserveJob("sitemap_regenerate", start, err) }(time.Now())
+    posts := s.postRepo.GetAllPublishedPosts(ctx)
+
+    urls := make([]sitemap.URL, 0, len(posts) + 1)
+    urls = append(urls, sitemap.URL{ Loc: strings.TrimRight(s.conf.Site.URL, "/") + "/" })
+    for i, post := range posts {
+        if post.NoIndex != nil && *post.NoIndex { continue }
+
+        lastMod := post.AddedDate
+        if post.PublishedDate != nil { lastMod = *post.PublishedDate }
+        if post.UpdatedDate != nil && post.UpdatedDate.After(lastMod) { lastMod = *post.UpdatedDate }
+
+        loc := canonicalURL(s.conf, &posts[i])
+        if !s.onSite(loc) { continue }
+
+        urls = append(urls, sitemap.URL{
+            Loc: loc,
+            LastMod: lastMod,
+            Images: s.imageURLs(htmltext.ImageSrcs(post.Content)),
+        })
+        if lastMod.After(urls[0].LastMod) {
+            urls[0].LastMod = lastMod
+        }
+    }
+
+    var pages [][]byte
+    var locs []string
+    var lastMods []time.Time
+    for start := 0; start < len(urls); start += sitemap.MaxURLs {
+        end := start + sitemap.MaxURLs
+        if end > len(urls) { end = len(urls) }
+
+        page, err := sitemap.URLSet(urls[start:end])
+        if err != nil { return err }
+        pages = append(pages, page)
+
+        var lastMod time.Time
+        for _, url := range urls[start:end] {
+            if url.LastMod.After(lastMod) { lastMod = url.LastMod }
+        }
+        locs = append(locs, s.pageURL(len(pages)))
+        lastMods = append(lastMods, lastMod)
+    }
+
+    body := pages[0]
+    if len(pages) > 1 {
+        if body, err = sitemap.Index(locs, lastMods); err != nil { return }
+    }
+
+    s.mutex.Lock()
+    s.sitemap = body
+    s.pages = pages
+    s.mutex.Unlock()
+    return nil
+}
+
+func (s *SitemapServiceImpl) MarkDirty() {
+    s.regenerator.Mark()
+}
+
+func (s *SitemapServiceImpl) Run(ctx context.Context) {
+    s.regenerator.Run(ctx, func(ctx context.Context) {
+        if err := s.Regenerate(ctx); err != nil {
+            logging.FromContext(ctx).Error("사이트맵 생성에 실패했습니다.", "error", err)
+        }
+    })
+}
+
+func (s *SitemapServiceImpl) GetSitemap() []byte {
+    s.mutex.RLock()
+    defer s.mutex.RUnlock()
+    return s.sitemap
+}
+
+func (s *SitemapServiceImpl) GetSitemapPage(page int) []byte {
+    s.mutex.RLock()
+    defer s.mutex.RUnlock()
+    if page < 1 || page > len(s.pages) {
+        return nil
+    }
+    return s.pages[page - 1]
+}
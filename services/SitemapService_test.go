@@ -0,0 +1,88 @@
+package services_test
+
+import (
+	"context"
+	"okra_board2/config"
+	"okra_board2/models"
+	"okra_board2/services"
+	"okra_board2/utils/sitemap"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSitemapService(t *testing.T) {
+    noIndex := true
+    sameHost, otherHost := "https://okraseoul.com/archive/5", "https://medium.com/@okra/6"
+    repo := &publishedPostRepositoryStub{
+        posts: []models.Post {
+            {
+                PostID: 1,
+                Content: `<p><img src="https://cdn.okraseoul.com/images/a.png"/><img src="https://cdn.okraseoul.com/images/b.png"/></p>`,
+            },
+            { PostID: 2, Content: "<p>no image</p>" },
+            {
+                PostID: 4,
+                Content: `<p><img src="/images/c.png"/><img src="data:image/png;base64,AAAA"/><img src="//cdn.okraseoul.com/images/d.png"/></p>`,
+            },
+            { PostID: 3, NoIndex: &noIndex },
+            { PostID: 5, CanonicalURL: &sameHost },
+            { PostID: 6, CanonicalURL: &otherHost },
+        },
+    }
+    conf := &config.Config{
+        Site: config.SiteConfig{ URL: "https://okraseoul.com", PostPath: "/posts/{postId}" },
+    }
+    s := services.NewSitemapServiceImpl(repo, conf)
+    assert.Nil(t, s.Regenerate(context.Background()))
+
+    body := string(s.GetSitemap())
+    assert.True(t, strings.Contains(body, "<urlset"))
+    assert.True(t, strings.Contains(body, "<loc>https://okraseoul.com/posts/1</loc>"))
+    assert.True(t, strings.Contains(body, "<image:loc>https://cdn.okraseoul.com/images/b.png</image:loc>"))
+    assert.False(t, strings.Contains(body, "<loc>https://okraseoul.com/posts/3</loc>"))
+    // 다른 호스트를 canonical 주소로 지정한 게시물은 제외된다.
+    assert.True(t, strings.Contains(body, "<loc>https://okraseoul.com/archive/5</loc>"))
+    assert.False(t, strings.Contains(body, "medium.com"))
+    assert.False(t, strings.Contains(body, "<loc>https://okraseoul.com/posts/6</loc>"))
+    // 상대 주소는 사이트 주소를 기준으로 변환하며, http(s)가 아닌 이미지는 제외한다.
+    assert.True(t, strings.Contains(body, "<image:loc>https://okraseoul.com/images/c.png</image:loc>"))
+    assert.True(t, strings.Contains(body, "<image:loc>https://cdn.okraseoul.com/images/d.png</image:loc>"))
+    assert.False(t, strings.Contains(body, "data:image"))
+    assert.Nil(t, s.GetSitemapPage(2))
+
+    // URL이 sitemap.MaxURLs개를 넘으면 index로 나누어진다.
+    repo.posts = make([]models.Post, sitemap.MaxURLs)
+    for i := range repo.posts {
+        repo.posts[i].PostID = i + 1
+    }
+    assert.Nil(t, s.Regenerate(context.Background()))
+
+    body = string(s.GetSitemap())
+    assert.True(t, strings.Contains(body, "<sitemapindex"))
+    assert.True(t, strings.Contains(body, "<loc>https://okraseoul.com/sitemaps/sitemap-2.xml</loc>"))
+    assert.NotNil(t, s.GetSitemapPage(2))
+    assert.Nil(t, s.GetSitemapPage(3))
+}
+
+func TestSitemapServiceRun(t *testing.T) {
+    repo := &publishedPostRepositoryStub{}
+    conf := &config.Config{
+        Site: config.SiteConfig{ URL: "https://okraseoul.com", PostPath: "/posts/{postId}" },
+    }
+    s := services.NewSitemapServiceImpl(repo, conf)
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    go s.Run(ctx)
+
+    // 연속된 변경은 한 번의 생성으로 합쳐진다.
+    repo.posts = []models.Post{{ PostID: 1 }}
+    s.MarkDirty()
+    s.MarkDirty()
+    assert.Eventually(t, func() bool {
+        return strings.Contains(string(s.GetSitemap()), "<loc>https://okraseoul.com/posts/1</loc>")
+    }, 3 * time.Second, 10 * time.Millisecond)
+    assert.Equal(t, int32(1), repo.loads.Load())
+}
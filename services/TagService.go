@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"okra_board2/utils/slug"
+	"strings"
+)
+
+var ErrSameTag = errors.New("Cannot merge a tag into itself.")
+
+type TagService interface {
+
+    // 태그 이름의 앞뒤 공백을 제거하고 연속된 공백을 하나로 합친다.
+    NormalizeTagName(name string)       (normalized string)
+
+    // 게시물의 태그 목록을 정규화하여 db상의 태그로 치환한다.
+    // slug가 같은 태그는 하나로 합쳐지며, 이름이 비어있는 태그는 제외된다.
+    // 존재하지 않는 태그는 새로 생성한다.
+    ResolveTags(ctx context.Context, tags []models.Tag) (resolved []models.Tag, err error)
+
+    // 태그 목록을 게시물 수와 함께 불러온다.
+    // enabled 속성이 true일 경우, 공개된 게시물만을 센다.
+    GetTags(ctx context.Context, enabled bool) (tags []models.TagCount)
+
+    // 자동 완성을 위해 keyword로 시작하는 태그를 최대 size개 불러온다.
+    SearchTags(ctx context.Context, keyword string, size int) (tags []models.Tag)
+
+    // 태그의 이름을 변경하고 유효성 검사 결과와 에러를 반환한다.
+    // 변경된 이름의 slug가 다른 태그와 겹칠 경우 유효성 검사에 실패한다.
+    // 존재하지 않는 태그일 경우 gorm.ErrRecordNotFound를 반환한다.
+    RenameTag(ctx context.Context, tagId int, name string) (result *models.TagValidationResult, err error)
+
+    // source 태그를 target 태그로 병합한다.
+    // 두 태그 중 하나라도 존재하지 않을 경우 gorm.ErrRecordNotFound를 반환한다.
+    MergeTags(ctx context.Context, sourceId, targetId int) (err error)
+
+    // 게시물에 연결되지 않은 태그를 삭제하고, 삭제된 태그의 수를 반환한다.
+    DeleteUnusedTags(ctx context.Context) (count int64, err error)
+
+}
+
+type TagServiceImpl struct {
+    tagRepo         repositories.TagRepository
+    relatedService  RelatedPostService
+}
+
+func NewTagServiceImpl(
+    tagRepo repositories.TagRepository,
+    relatedService RelatedPostService,
+) TagService {
+    return &TagServiceImpl{
+        tagRepo: tagRepo,
+        relatedService: relatedService,
+    }
+}
+
+func (s *TagServiceImpl) NormalizeTagName(name string) string {
+    return strings.Join(strings.Fields(name), " ")
+}
+
+func (s *TagServiceImpl) ResolveTags(ctx context.Context, tags []models.Tag) ([]models.Tag, error) {
+    keys := make(map[string]struct{})
+    normalized := make([]models.Tag, 0)
+    for _, tag := range tags {
+        name := s.NormalizeTagName(tag.Name)
+        tagSlug := slug.MakeTag(name)
+        if tagSlug == "" { continue }
+        if _, ok := keys[tagSlug]; ok { continue }
+        keys[tagSlug] = struct{}{}
+        normalized = append(normalized, models.Tag{ Name: name, Slug: tagSlug })
+    }
+    if len(normalized) == 0 {
+        return normalized, nil
+    }
+    return s.tagRepo.FindOrCreateTags(ctx, normalized)
+}
+
+func (s *TagServiceImpl) GetTags(ctx context.Context, enabled bool) []models.TagCount {
+    return s.tagRepo.GetTagCounts(ctx, enabled)
+}
+
+func (s *TagServiceImpl) SearchTags(ctx context.Context, keyword string, size int) []models.Tag {
+    return s.tagRepo.SearchTags(ctx, s.NormalizeTagName(keyword), size)
+}
+
+func (s *TagServiceImpl) RenameTag(ctx context.Context, tagId int, name string) (result *models.TagValidationResult, err error) {
+    tag, err := s.tagRepo.GetTag(ctx, tagId)
+    if err != nil { return }
+
+    name = s.NormalizeTagName(name)
+    tagSlug := slug.MakeTag(name)
+
+    var msg string
+    result = &models.TagValidationResult{}
+    if tagSlug == "" {
+        msg = "태그 이름을 입력하세요."
+        result.Name = &msg
+    } else if tagSlug != tag.Slug && s.tagRepo.CheckSlugExists(ctx, tagSlug) {
+        msg = "이미 존재하는 태그입니다. 태그를 병합하세요."
+        result.Name = &msg
+    }
+    if result = result.GetOrNil(); result != nil {
+        return
+    }
+
+    tag.Name = name
+    tag.Slug = tagSlug
+    err = s.tagRepo.UpdateTag(ctx, tag)
+    return
+}
+
+func (s *TagServiceImpl) MergeTags(ctx context.Context, sourceId, targetId int) (err error) {
+    if sourceId == targetId {
+        return ErrSameTag
+    }
+    if _, err = s.tagRepo.GetTag(ctx, sourceId); err != nil { return }
+    if _, err = s.tagRepo.GetTag(ctx, targetId); err != nil { return }
+
+    if err = s.tagRepo.MergeTags(ctx, sourceId, targetId); err != nil { return }
+    s.relatedService.MarkDirty()
+    return nil
+}
+
+func (s *TagServiceImpl) DeleteUnusedTags(ctx context.Context) (count int64, err error) {
+    return s.tagRepo.DeleteUnusedTags(ctx)
+}
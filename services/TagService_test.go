@@ -0,0 +1,112 @@
+package services_test
+
+import (
+	"context"
+	"okra_board2/models"
+	"okra_board2/repositories"
+	"okra_board2/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type tagRepositoryStub struct {
+    repositories.TagRepository
+    tags []models.Tag
+}
+
+func (r *tagRepositoryStub) FindOrCreateTags(ctx context.Context, tags []models.Tag) (result []models.Tag, err error) {
+    for _, tag := range tags {
+        found := false
+        for _, existing := range r.tags {
+            if existing.Slug == tag.Slug {
+                result = append(result, existing)
+                found = true
+            }
+        }
+        if !found {
+            tag.TagID = len(r.tags) + 1
+            r.tags = append(r.tags, tag)
+            result = append(result, tag)
+        }
+    }
+    return
+}
+
+func (r *tagRepositoryStub) GetTag(ctx context.Context, tagId int) (*models.Tag, error) {
+    for i := range r.tags {
+        if r.tags[i].TagID == tagId {
+            tag := r.tags[i]
+            return &tag, nil
+        }
+    }
+    return nil, gorm.ErrRecordNotFound
+}
+
+func (r *tagRepositoryStub) CheckSlugExists(ctx context.Context, slug string) bool {
+    for _, tag := range r.tags {
+        if tag.Slug == slug { return true }
+    }
+    return false
+}
+
+func (r *tagRepositoryStub) UpdateTag(ctx context.Context, tag *models.Tag) error {
+    r.tags[tag.TagID - 1] = *tag
+    return nil
+}
+
+func TestTagService(t *testing.T) {
+    repo := &tagRepositoryStub{
+        tags: []models.Tag {
+            { TagID: 1, Name: "Okra", Slug: "okra" },
+        },
+    }
+    s := services.NewTagServiceImpl(repo, services.NewRelatedPostServiceImpl(&publishedPostRepositoryStub{}))
+
+    // 공백과 대소문자만 다른 태그는 같은 태그로 취급된다.
+    tags, err := s.ResolveTags(context.Background(), []models.Tag {
+        { Name: "okra " },
+        { Name: "  Okra" },
+        { Name: "Seoul   Farm" },
+        { Name: "   " },
+    })
+    assert.Nil(t, err)
+    assert.Equal(t, 2, len(tags))
+    assert.Equal(t, 1, tags[0].TagID)
+    assert.Equal(t, "Seoul Farm", tags[1].Name)
+    assert.Equal(t, "seoul-farm", tags[1].Slug)
+
+    // rename
+    result, err := s.RenameTag(context.Background(), 2, "seoul farm")
+    assert.Nil(t, err)
+    assert.Nil(t, result)
+    assert.Equal(t, "seoul farm", repo.tags[1].Name)
+
+    result, err = s.RenameTag(context.Background(), 2, "OKRA")
+    assert.Nil(t, err)
+    assert.NotNil(t, result.Name)
+
+    result, err = s.RenameTag(context.Background(), 2, " ")
+    assert.NotNil(t, result.Name)
+
+    _, err = s.RenameTag(context.Background(), 3, "new tag")
+    assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+    assert.Equal(t, services.ErrSameTag, s.MergeTags(context.Background(), 1, 1))
+
+    // 기호만 다른 태그는 합쳐지지 않으며, 기호로만 이루어진 태그도 제외되지 않는다.
+    tags, err = s.ResolveTags(context.Background(), []models.Tag {
+        { Name: "C" },
+        { Name: "C++" },
+        { Name: "C#" },
+        { Name: "🔥" },
+    })
+    assert.Nil(t, err)
+    if assert.Equal(t, 4, len(tags)) {
+        assert.Equal(t, "c", tags[0].Slug)
+        assert.Equal(t, "c-plus-plus", tags[1].Slug)
+        assert.Equal(t, "c-sharp", tags[2].Slug)
+        assert.Equal(t, "u1f525", tags[3].Slug)
+    }
+}
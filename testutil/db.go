@@ -0,0 +1,57 @@
+package testutil
+
+import (
+	"okra_board2/config"
+	"okra_board2/migrations"
+	"okra_board2/module"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 테스트용 설정. DB는 메모리 SQLite를 사용한다. SQLite 드라이버는 순수 Go로 구현되어 cgo 없이 테스트를 실행할 수 있다.
+func NewConfig() *config.Config {
+    return &config.Config{
+        AccessSecret: "test-access-secret",
+        RefreshSecret: "test-refresh-secret",
+        Domain: "localhost",
+        DefaultThumbnail: "default.png",
+        DB: config.DBConfig{
+            Driver: config.DriverSQLite,
+            Database: ":memory:",
+        },
+        AWS: config.AWSConfig{
+            Region: "ap-northeast-2",
+            Bucket: "test-bucket",
+            Domain: "images.example.com",
+        },
+        Site: config.SiteConfig{
+            Title: "Test Board",
+            URL: "https://example.com",
+        },
+    }
+}
+
+// 모든 마이그레이션이 적용된 메모리 SQLite DB를 생성한다. DB는 테스트가 끝나면 닫힌다.
+func NewDB(t testing.TB) *gorm.DB {
+    t.Helper()
+    conf := NewConfig()
+    db, err := module.InitDBConnection(conf)
+    if err != nil {
+        t.Fatal(err)
+    }
+    db.Logger = logger.Default.LogMode(logger.Silent)
+
+    sqlDB, err := db.DB()
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { sqlDB.Close() })
+
+    if _, err := migrations.NewMigratorImpl(db, migrations.All).Up(); err != nil {
+        t.Fatal(err)
+    }
+    return db
+}
+
@@ -0,0 +1,83 @@
+package testutil
+
+import (
+	"fmt"
+	"okra_board2/models"
+	"okra_board2/utils/encryption"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// 테스트용 관리자의 비밀번호
+const AdminPassword = "@@Test123456"
+
+// 기본값으로 채운 게시물을 저장하고 반환한다. options로 저장 전에 값을 바꿀 수 있다.
+// 태그는 tags 테이블에 없을 경우 생성하여 연결한다.
+func CreatePost(t testing.TB, db *gorm.DB, options ...func(post *models.Post)) models.Post {
+    t.Helper()
+    var count int64
+    db.Model(&models.Post{}).Count(&count)
+    index := count + 1
+
+    post := models.Post{
+        BoardID: 1,
+        Title: fmt.Sprintf("test title %d", index),
+        Slug: fmt.Sprintf("test-title-%d", index),
+        Thumbnail: fmt.Sprintf(`<img src="https://images.example.com/public/images/a/thumbnail-%d.png">`, index),
+        Content: fmt.Sprintf("<h2>Heading</h2><p>test content %d</p>", index),
+        ContentFormat: models.ContentFormatHTML,
+        Excerpt: fmt.Sprintf("test content %d", index),
+        ReadingTime: 1,
+        Status: true,
+    }
+    for _, option := range options {
+        option(&post)
+    }
+
+    tags := post.Tags
+    post.Tags = nil
+    for i := range tags {
+        tags[i] = CreateTag(t, db, tags[i].Name, tags[i].Slug)
+    }
+    if post.Status && post.PublishedDate == nil {
+        now := db.NowFunc()
+        post.PublishedDate = &now
+    }
+    if err := db.Omit("Tags").Create(&post).Error; err != nil {
+        t.Fatal(err)
+    }
+    for _, tag := range tags {
+        if err := db.Create(&models.PostTag{ PostID: post.PostID, TagID: tag.TagID }).Error; err != nil {
+            t.Fatal(err)
+        }
+    }
+    post.Tags = tags
+    return post
+}
+
+// slug가 같은 태그가 없을 경우 생성하고, 태그를 반환한다.
+func CreateTag(t testing.TB, db *gorm.DB, name, slug string) models.Tag {
+    t.Helper()
+    tag := models.Tag{}
+    if err := db.Where(models.Tag{ Slug: slug }).Attrs(models.Tag{ Name: name }).FirstOrCreate(&tag).Error; err != nil {
+        t.Fatal(err)
+    }
+    return tag
+}
+
+// 비밀번호가 AdminPassword인 관리자를 저장하고 반환한다.
+func CreateAdmin(t testing.TB, db *gorm.DB, id string) models.Admin {
+    t.Helper()
+    admin := models.Admin{
+        ID: id,
+        Password: encryption.EncryptSHA256(AdminPassword),
+        Name: "강민석",
+        Email: id + "@example.com",
+        Phone: "010-1234-5678",
+    }
+    if err := db.Create(&admin).Error; err != nil {
+        t.Fatal(err)
+    }
+    return admin
+}
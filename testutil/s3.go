@@ -0,0 +1,134 @@
+package testutil
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"okra_board2/metrics"
+	"okra_board2/tracing"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// 메모리에 객체를 저장하는 S3 대체 서버.
+// path-style 요청의 PutObject, GetObject, HeadObject, DeleteObject, ListObjects(V2)를 지원한다.
+type FakeS3 struct {
+    Server      *httptest.Server
+    mutex       sync.Mutex
+    objects     map[string][]byte
+}
+
+// FakeS3 서버를 시작하고 서버에 연결된 S3 클라이언트를 반환한다. 서버는 테스트가 끝나면 종료된다.
+func NewS3(t testing.TB) (*s3.Client, *FakeS3) {
+    fake := &FakeS3{ objects: make(map[string][]byte) }
+    fake.Server = httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+    t.Cleanup(fake.Server.Close)
+
+    client := s3.New(s3.Options{
+        Region: "ap-northeast-2",
+        Credentials: credentials.NewStaticCredentialsProvider("test", "test", ""),
+        EndpointResolver: s3.EndpointResolverFromURL(fake.Server.URL),
+        UsePathStyle: true,
+        RetryMaxAttempts: 1,
+        APIOptions: []func(*middleware.Stack) error{ metrics.S3Middleware, tracing.S3Middleware },
+    })
+    return client, fake
+}
+
+// bucket에 저장된 객체의 key 목록을 정렬하여 반환한다.
+func (f *FakeS3) Keys(bucket string) (keys []string) {
+    f.mutex.Lock()
+    defer f.mutex.Unlock()
+    for path := range f.objects {
+        if strings.HasPrefix(path, bucket + "/") {
+            keys = append(keys, strings.TrimPrefix(path, bucket + "/"))
+        }
+    }
+    sort.Strings(keys)
+    return
+}
+
+// 객체를 직접 저장한다.
+func (f *FakeS3) Put(bucket, key string, body []byte) {
+    f.mutex.Lock()
+    defer f.mutex.Unlock()
+    f.objects[bucket + "/" + key] = body
+}
+
+type listBucketResult struct {
+    XMLName     xml.Name    `xml:"ListBucketResult"`
+    Name        string
+    Prefix      string
+    KeyCount    int
+    IsTruncated bool
+    Contents    []listObject
+}
+
+type listObject struct {
+    Key         string
+    Size        int
+}
+
+func (f *FakeS3) serveHTTP(w http.ResponseWriter, r *http.Request) {
+    f.mutex.Lock()
+    defer f.mutex.Unlock()
+
+    path := strings.TrimPrefix(r.URL.Path, "/")
+    bucket, key := path, ""
+    if i := strings.Index(path, "/"); i >= 0 {
+        bucket, key = path[:i], path[i+1:]
+    }
+
+    switch {
+    case key == "" && r.Method == http.MethodGet:
+        prefix := r.URL.Query().Get("prefix")
+        result := listBucketResult{ Name: bucket, Prefix: prefix }
+        for objectPath, body := range f.objects {
+            objectKey := strings.TrimPrefix(objectPath, bucket + "/")
+            if objectKey != objectPath && strings.HasPrefix(objectKey, prefix) {
+                result.Contents = append(result.Contents, listObject{ Key: objectKey, Size: len(body) })
+            }
+        }
+        sort.Slice(result.Contents, func(i, j int) bool { return result.Contents[i].Key < result.Contents[j].Key })
+        result.KeyCount = len(result.Contents)
+        w.Header().Set("Content-Type", "application/xml")
+        xml.NewEncoder(w).Encode(result)
+    case key == "":
+        w.WriteHeader(http.StatusNotImplemented)
+    case r.Method == http.MethodPut:
+        body, err := io.ReadAll(r.Body)
+        if err != nil {
+            w.WriteHeader(http.StatusBadRequest)
+            return
+        }
+        f.objects[path] = body
+        w.Header().Set("ETag", `"fake"`)
+    case r.Method == http.MethodGet || r.Method == http.MethodHead:
+        body, ok := f.objects[path]
+        if !ok {
+            w.Header().Set("Content-Type", "application/xml")
+            w.WriteHeader(http.StatusNotFound)
+            if r.Method == http.MethodGet {
+                io.WriteString(w, "<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>")
+            }
+            return
+        }
+        w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+        if r.Method == http.MethodGet {
+            w.Write(body)
+        }
+    case r.Method == http.MethodDelete:
+        delete(f.objects, path)
+        w.WriteHeader(http.StatusNoContent)
+    default:
+        w.WriteHeader(http.StatusNotImplemented)
+    }
+}
@@ -0,0 +1,76 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// 진행 중인 span을 gorm.DB에 저장하는 key
+const gormSpanKey = "okra:tracing:span"
+
+// gorm의 create, query, update, delete, row, raw 콜백마다 span을 기록하는 plugin.
+// 쿼리는 db.WithContext(ctx)로 전달된 context의 span을 상위 span으로 한다.
+type GormPlugin struct{}
+
+func NewGormPlugin() gorm.Plugin {
+    return &GormPlugin{}
+}
+
+func (p *GormPlugin) Name() string {
+    return "okra:tracing"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+    callback := db.Callback()
+    for _, err := range []error{
+        callback.Create().Before("gorm:create").Register("okra:tracing:before_create", p.before("create")),
+        callback.Create().After("gorm:create").Register("okra:tracing:after_create", p.after),
+        callback.Query().Before("gorm:query").Register("okra:tracing:before_query", p.before("query")),
+        callback.Query().After("gorm:query").Register("okra:tracing:after_query", p.after),
+        callback.Update().Before("gorm:update").Register("okra:tracing:before_update", p.before("update")),
+        callback.Update().After("gorm:update").Register("okra:tracing:after_update", p.after),
+        callback.Delete().Before("gorm:delete").Register("okra:tracing:before_delete", p.before("delete")),
+        callback.Delete().After("gorm:delete").Register("okra:tracing:after_delete", p.after),
+        callback.Row().Before("gorm:row").Register("okra:tracing:before_row", p.before("row")),
+        callback.Row().After("gorm:row").Register("okra:tracing:after_row", p.after),
+        callback.Raw().Before("gorm:raw").Register("okra:tracing:before_raw", p.before("raw")),
+        callback.Raw().After("gorm:raw").Register("okra:tracing:after_raw", p.after),
+    } {
+        if err != nil { return err }
+    }
+    return nil
+}
+
+func (p *GormPlugin) before(operation string) func(*gorm.DB) {
+    return func(db *gorm.DB) {
+        _, span := Start(db.Statement.Context, "gorm." + operation,
+            semconv.DBSystemKey.String(db.Dialector.Name()),
+        )
+        db.InstanceSet(gormSpanKey, span)
+    }
+}
+
+func (p *GormPlugin) after(db *gorm.DB) {
+    value, ok := db.InstanceGet(gormSpanKey)
+    if !ok { return }
+    span, ok := value.(trace.Span)
+    if !ok { return }
+    defer span.End()
+
+    span.SetAttributes(
+        semconv.DBStatement(db.Statement.SQL.String()),
+        attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+    )
+    if db.Statement.Table != "" {
+        span.SetAttributes(semconv.DBSQLTable(db.Statement.Table))
+    }
+    if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+        span.RecordError(db.Error)
+        span.SetStatus(codes.Error, db.Error.Error())
+    }
+}
@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"log/slog"
+	"net/http"
+	"okra_board2/logging"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 요청마다 "<method> <route>" 이름의 server span을 시작하고, span이 담긴 context를 요청의 context로 지정한다.
+// 요청 헤더에 traceparent가 있을 경우 해당 trace를 이어간다. trace ID는 요청의 로그에 포함된다.
+// skipPaths의 요청은 기록하지 않는다.
+func Middleware(skipPaths ...string) gin.HandlerFunc {
+    skip := make(map[string]bool, len(skipPaths))
+    for _, path := range skipPaths {
+        skip[path] = true
+    }
+    return func(c *gin.Context) {
+        if skip[c.Request.URL.Path] {
+            c.Next()
+            return
+        }
+        route := c.FullPath()
+        name := c.Request.Method + " " + route
+        if route == "" {
+            name = c.Request.Method
+        }
+        ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+        ctx, span := otel.Tracer(tracerName).Start(ctx, name,
+            trace.WithSpanKind(trace.SpanKindServer),
+            trace.WithAttributes(
+                semconv.HTTPRequestMethodKey.String(c.Request.Method),
+                semconv.HTTPRoute(route),
+                semconv.URLPath(c.Request.URL.Path),
+                attribute.String("request.id", logging.RequestID(c)),
+            ),
+        )
+        defer span.End()
+        if traceID := TraceID(ctx); traceID != "" {
+            ctx = logging.With(ctx, slog.String("trace_id", traceID))
+        }
+        c.Request = c.Request.WithContext(ctx)
+
+        c.Next()
+
+        status := c.Writer.Status()
+        span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+        if status >= 500 {
+            span.SetStatus(codes.Error, http.StatusText(status))
+        }
+        if len(c.Errors) > 0 {
+            span.SetAttributes(attribute.String("gin.errors", c.Errors.String()))
+        }
+    }
+}
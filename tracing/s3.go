@@ -0,0 +1,30 @@
+package tracing
+
+import (
+	"context"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// S3 요청마다 "S3.<operation>" 이름의 client span을 기록한다. s3.Options.APIOptions에 추가하여 사용한다.
+func S3Middleware(stack *middleware.Stack) error {
+    return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("OkraTracing", func(
+        ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+    ) (out middleware.InitializeOutput, metadata middleware.Metadata, err error) {
+        service, operation := awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx)
+        ctx, span := otel.Tracer(tracerName).Start(ctx, service + "." + operation,
+            trace.WithSpanKind(trace.SpanKindClient),
+            trace.WithAttributes(
+                semconv.RPCSystemKey.String("aws-api"),
+                semconv.RPCService(service),
+                semconv.RPCMethod(operation),
+            ),
+        )
+        defer func() { End(span, err) }()
+        return next.HandleInitialize(ctx, in)
+    }), middleware.After)
+}
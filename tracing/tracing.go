@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"okra_board2/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// span을 생성하는 Tracer의 이름
+const tracerName = "okra_board2"
+
+// 지원하는 span exporter. 비어있을 경우 span을 내보내지 않는다.
+const (
+    ExporterNone    = ""
+    ExporterOTLP    = config.TracingExporterOTLP
+    ExporterStdout  = config.TracingExporterStdout
+)
+
+// exporter 이름을 검사한다.
+func ParseExporter(name string) (string, error) {
+    switch name {
+    case ExporterNone, ExporterOTLP, ExporterStdout:
+        return name, nil
+    }
+    return "", fmt.Errorf("Unknown trace exporter: %s", name)
+}
+
+// span exporter를 생성한다. otlp는 endpoint(host:port)로 OTLP/HTTP 요청을 보내며,
+// endpoint가 비어있을 경우 OTEL_EXPORTER_OTLP_* 환경변수 또는 localhost:4318을 사용한다.
+// stdout은 span을 w에 JSON으로 기록한다.
+func NewExporter(ctx context.Context, name, endpoint string, insecure bool, w io.Writer) (sdktrace.SpanExporter, error) {
+    switch name {
+    case ExporterOTLP:
+        options := []otlptracehttp.Option{}
+        if endpoint != "" {
+            options = append(options, otlptracehttp.WithEndpoint(endpoint))
+        }
+        if insecure {
+            options = append(options, otlptracehttp.WithInsecure())
+        }
+        return otlptracehttp.New(ctx, options...)
+    case ExporterStdout:
+        return stdouttrace.New(stdouttrace.WithWriter(w))
+    }
+    return nil, fmt.Errorf("Unknown trace exporter: %s", name)
+}
+
+// exporter로 span을 내보내는 TracerProvider를 생성한다.
+// 상위 span이 없는 trace는 sampleRatio의 비율로 기록하고, 상위 span이 있을 경우 상위 span의 결정을 따른다.
+func NewProvider(exporter sdktrace.SpanExporter, serviceName string, sampleRatio float64) *sdktrace.TracerProvider {
+    return sdktrace.NewTracerProvider(
+        sdktrace.WithBatcher(exporter),
+        sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+        sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+    )
+}
+
+// provider를 전역 TracerProvider로 등록하고, W3C Trace Context와 Baggage 헤더로 trace를 전파한다.
+// provider가 nil일 경우 span을 기록하지 않고 요청의 trace 정보만 전파한다.
+func SetGlobal(provider trace.TracerProvider) {
+    if provider != nil {
+        otel.SetTracerProvider(provider)
+    }
+    otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+        propagation.TraceContext{},
+        propagation.Baggage{},
+    ))
+}
+
+// ctx의 span을 상위 span으로 하는 새로운 span을 시작한다. 반환된 span은 End 또는 span.End로 종료해야 한다.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+    return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// err를 span에 기록한 뒤 span을 종료한다. 조회 결과가 없는 것은 에러로 기록하지 않는다.
+func End(span trace.Span, err error) {
+    if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+        span.RecordError(err)
+        span.SetStatus(codes.Error, err.Error())
+    }
+    span.End()
+}
+
+// ctx에 기록 중인 span이 있을 경우 trace ID를 반환한다.
+func TraceID(ctx context.Context) string {
+    spanContext := trace.SpanContextFromContext(ctx)
+    if !spanContext.HasTraceID() {
+        return ""
+    }
+    return spanContext.TraceID().String()
+}
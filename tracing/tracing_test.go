@@ -0,0 +1,133 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"okra_board2/models"
+	"okra_board2/testutil"
+	"okra_board2/tracing"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// 기록된 span을 메모리에 저장하는 TracerProvider를 전역으로 등록한다.
+func newRecorder(t *testing.T) *tracetest.SpanRecorder {
+    recorder := tracetest.NewSpanRecorder()
+    previous := otel.GetTracerProvider()
+    tracing.SetGlobal(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+    t.Cleanup(func() { otel.SetTracerProvider(previous) })
+    return recorder
+}
+
+func findSpan(spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+    for _, span := range spans {
+        if span.Name() == name {
+            return span
+        }
+    }
+    return nil
+}
+
+func attributeValue(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+    for _, attr := range span.Attributes() {
+        if attr.Key == key {
+            return attr.Value
+        }
+    }
+    return attribute.Value{}
+}
+
+func TestMiddleware(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    recorder := newRecorder(t)
+
+    route := gin.New()
+    route.Use(tracing.Middleware("/healthz"))
+    var traceID string
+    route.GET("/posts/:postId", func(c *gin.Context) {
+        _, span := tracing.Start(c.Request.Context(), "child")
+        span.End()
+        traceID = tracing.TraceID(c.Request.Context())
+        c.Status(500)
+    })
+    route.GET("/healthz", func(c *gin.Context) { c.Status(200) })
+
+    // 요청 헤더의 trace를 이어간다.
+    req := httptest.NewRequest("GET", "/posts/1", nil)
+    req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+    route.ServeHTTP(httptest.NewRecorder(), req)
+    route.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+
+    spans := recorder.Ended()
+    assert.Len(t, spans, 2)
+    server := findSpan(spans, "GET /posts/:postId")
+    if assert.NotNil(t, server) {
+        assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+        assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+        assert.Equal(t, codes.Error, server.Status().Code)
+        assert.Equal(t, int64(500), attributeValue(server, "http.response.status_code").AsInt64())
+    }
+    child := findSpan(spans, "child")
+    if assert.NotNil(t, child) && server != nil {
+        assert.Equal(t, server.SpanContext().SpanID(), child.Parent().SpanID())
+    }
+}
+
+func TestGormPlugin(t *testing.T) {
+    db := testutil.NewDB(t)
+    recorder := newRecorder(t)
+
+    ctx, parent := tracing.Start(context.Background(), "parent")
+    db.WithContext(ctx).Create(&models.Tag{ Name: "go", Slug: "go" })
+    // 조회 결과가 없는 것은 에러로 기록하지 않는다.
+    db.WithContext(ctx).First(&models.Tag{}, "slug = ?", "none")
+    parent.End()
+
+    spans := recorder.Ended()
+    create := findSpan(spans, "gorm.create")
+    if assert.NotNil(t, create) {
+        assert.Equal(t, parent.SpanContext().SpanID(), create.Parent().SpanID())
+        assert.True(t, strings.HasPrefix(attributeValue(create, "db.statement").AsString(), "INSERT INTO"))
+        assert.Equal(t, int64(1), attributeValue(create, "db.rows_affected").AsInt64())
+    }
+    query := findSpan(spans, "gorm.query")
+    if assert.NotNil(t, query) {
+        assert.Equal(t, codes.Unset, query.Status().Code)
+    }
+}
+
+func TestS3Middleware(t *testing.T) {
+    client, _ := testutil.NewS3(t)
+    recorder := newRecorder(t)
+
+    client.PutObject(context.Background(), &s3.PutObjectInput{
+        Bucket: aws.String("test-bucket"),
+        Key: aws.String("images/test.png"),
+        Body: strings.NewReader("png"),
+    })
+    client.GetObject(context.Background(), &s3.GetObjectInput{
+        Bucket: aws.String("test-bucket"),
+        Key: aws.String("images/none.png"),
+    })
+
+    spans := recorder.Ended()
+    put := findSpan(spans, "S3.PutObject")
+    if assert.NotNil(t, put) {
+        assert.Equal(t, codes.Unset, put.Status().Code)
+        assert.Equal(t, "PutObject", attributeValue(put, "rpc.method").AsString())
+    }
+    get := findSpan(spans, "S3.GetObject")
+    if assert.NotNil(t, get) {
+        assert.Equal(t, codes.Error, get.Status().Code)
+    }
+}
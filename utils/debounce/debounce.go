@@ -0,0 +1,53 @@
+package debounce
+
+import (
+	"context"
+	"time"
+)
+
+// 여러 번의 변경 요청을 모아 한 번에 처리하는 작업.
+// Mark가 여러 번 호출되어도 마지막 호출 후 delay 동안 새로운 요청이 없을 때 한 번만 실행되며,
+// 실행 중에 들어온 요청은 실행이 끝난 뒤 다시 처리된다.
+type Debouncer struct {
+    delay   time.Duration
+    dirty   chan struct{}
+}
+
+func New(delay time.Duration) *Debouncer {
+    return &Debouncer{ delay: delay, dirty: make(chan struct{}, 1) }
+}
+
+// 변경 요청을 기록한다. 이미 처리되지 않은 요청이 있을 경우 하나로 합쳐진다.
+func (d *Debouncer) Mark() {
+    select {
+    case d.dirty <- struct{}{}:
+    default:
+    }
+}
+
+// ctx가 종료될 때 까지 변경 요청을 기다려 fn을 실행한다. fn은 하나의 goroutine에서만 실행된다.
+func (d *Debouncer) Run(ctx context.Context, fn func(ctx context.Context)) {
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-d.dirty:
+        }
+
+        // 마지막 요청 후 delay 동안 새로운 요청이 없을 때까지 기다린다.
+        timer := time.NewTimer(d.delay)
+        for waiting := true; waiting; {
+            select {
+            case <-ctx.Done():
+                timer.Stop()
+                return
+            case <-d.dirty:
+                if !timer.Stop() { <-timer.C }
+                timer.Reset(d.delay)
+            case <-timer.C:
+                waiting = false
+            }
+        }
+        fn(ctx)
+    }
+}
@@ -0,0 +1,257 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"mime"
+	"path"
+	"time"
+)
+
+// RSS, Atom, JSON Feed로 변환할 수 있는 피드
+type Feed struct {
+    Title       string
+    Description string
+    // 사이트 주소
+    Link        string
+    // 피드 자신의 주소
+    FeedURL     string
+    Language    string
+    Updated     time.Time
+    Items       []Item
+}
+
+type Item struct {
+    ID          string
+    Title       string
+    Link        string
+    Summary     string
+    // 비어있을 경우 본문을 포함하지 않는다.
+    Content     string
+    Published   time.Time
+    Updated     time.Time
+    Image       string
+    Categories  []string
+}
+
+// 이미지 주소의 확장자로 MIME 타입을 추정한다.
+func imageType(url string) string {
+    if t := mime.TypeByExtension(path.Ext(url)); t != "" {
+        return t
+    }
+    return "image/png"
+}
+
+type rss struct {
+    XMLName     xml.Name    `xml:"rss"`
+    Version     string      `xml:"version,attr"`
+    AtomNS      string      `xml:"xmlns:atom,attr"`
+    ContentNS   string      `xml:"xmlns:content,attr"`
+    Channel     rssChannel  `xml:"channel"`
+}
+
+type rssChannel struct {
+    Title       string      `xml:"title"`
+    Link        string      `xml:"link"`
+    Description string      `xml:"description"`
+    Language    string      `xml:"language,omitempty"`
+    LastBuildDate string    `xml:"lastBuildDate"`
+    AtomLink    atomLink    `xml:"atom:link"`
+    Items       []rssItem   `xml:"item"`
+}
+
+type rssItem struct {
+    Title       string      `xml:"title"`
+    Link        string      `xml:"link"`
+    GUID        rssGUID     `xml:"guid"`
+    PubDate     string      `xml:"pubDate"`
+    Description string      `xml:"description"`
+    Content     *rssContent `xml:"content:encoded,omitempty"`
+    Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+    Categories  []string    `xml:"category"`
+}
+
+type rssGUID struct {
+    IsPermaLink string      `xml:"isPermaLink,attr"`
+    Value       string      `xml:",chardata"`
+}
+
+type rssContent struct {
+    Value       string      `xml:",cdata"`
+}
+
+type rssEnclosure struct {
+    URL         string      `xml:"url,attr"`
+    Length      string      `xml:"length,attr"`
+    Type        string      `xml:"type,attr"`
+}
+
+// RSS 2.0 형식으로 변환한다.
+func (f *Feed) RSS() ([]byte, error) {
+    channel := rssChannel{
+        Title: f.Title,
+        Link: f.Link,
+        Description: f.Description,
+        Language: f.Language,
+        LastBuildDate: f.Updated.Format(time.RFC1123Z),
+        AtomLink: atomLink{ Href: f.FeedURL, Rel: "self", Type: "application/rss+xml" },
+    }
+    for _, item := range f.Items {
+        rssItem := rssItem{
+            Title: item.Title,
+            Link: item.Link,
+            GUID: rssGUID{ IsPermaLink: "true", Value: item.Link },
+            PubDate: item.Published.Format(time.RFC1123Z),
+            Description: item.Summary,
+            Categories: item.Categories,
+        }
+        if item.Content != "" {
+            rssItem.Content = &rssContent{ Value: item.Content }
+        }
+        if item.Image != "" {
+            rssItem.Enclosure = &rssEnclosure{ URL: item.Image, Length: "0", Type: imageType(item.Image) }
+        }
+        channel.Items = append(channel.Items, rssItem)
+    }
+    return marshalXML(rss{
+        Version: "2.0",
+        AtomNS: "http://www.w3.org/2005/Atom",
+        ContentNS: "http://purl.org/rss/1.0/modules/content/",
+        Channel: channel,
+    })
+}
+
+type atomFeed struct {
+    XMLName     xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+    Lang        string      `xml:"xml:lang,attr,omitempty"`
+    Title       string      `xml:"title"`
+    Subtitle    string      `xml:"subtitle,omitempty"`
+    ID          string      `xml:"id"`
+    Updated     string      `xml:"updated"`
+    Links       []atomLink  `xml:"link"`
+    Entries     []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+    Href        string      `xml:"href,attr"`
+    Rel         string      `xml:"rel,attr,omitempty"`
+    Type        string      `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+    Title       string      `xml:"title"`
+    ID          string      `xml:"id"`
+    Links       []atomLink  `xml:"link"`
+    Published   string      `xml:"published"`
+    Updated     string      `xml:"updated"`
+    Summary     atomText    `xml:"summary"`
+    Content     *atomText   `xml:"content,omitempty"`
+    Categories  []atomCategory `xml:"category"`
+}
+
+type atomText struct {
+    Type        string      `xml:"type,attr"`
+    Value       string      `xml:",chardata"`
+}
+
+type atomCategory struct {
+    Term        string      `xml:"term,attr"`
+}
+
+// Atom 1.0 형식으로 변환한다.
+func (f *Feed) Atom() ([]byte, error) {
+    feed := atomFeed{
+        Lang: f.Language,
+        Title: f.Title,
+        Subtitle: f.Description,
+        ID: f.FeedURL,
+        Updated: f.Updated.Format(time.RFC3339),
+        Links: []atomLink{
+            { Href: f.Link, Rel: "alternate", Type: "text/html" },
+            { Href: f.FeedURL, Rel: "self", Type: "application/atom+xml" },
+        },
+    }
+    for _, item := range f.Items {
+        entry := atomEntry{
+            Title: item.Title,
+            ID: item.Link,
+            Links: []atomLink{{ Href: item.Link, Rel: "alternate", Type: "text/html" }},
+            Published: item.Published.Format(time.RFC3339),
+            Updated: item.Updated.Format(time.RFC3339),
+            Summary: atomText{ Type: "text", Value: item.Summary },
+        }
+        if item.Content != "" {
+            entry.Content = &atomText{ Type: "html", Value: item.Content }
+        }
+        if item.Image != "" {
+            entry.Links = append(entry.Links, atomLink{ Href: item.Image, Rel: "enclosure", Type: imageType(item.Image) })
+        }
+        for _, category := range item.Categories {
+            entry.Categories = append(entry.Categories, atomCategory{ Term: category })
+        }
+        feed.Entries = append(feed.Entries, entry)
+    }
+    return marshalXML(feed)
+}
+
+type jsonFeed struct {
+    Version     string      `json:"version"`
+    Title       string      `json:"title"`
+    HomePageURL string      `json:"home_page_url"`
+    FeedURL     string      `json:"feed_url"`
+    Description string      `json:"description,omitempty"`
+    Language    string      `json:"language,omitempty"`
+    Items       []jsonItem  `json:"items"`
+}
+
+type jsonItem struct {
+    ID          string      `json:"id"`
+    URL         string      `json:"url"`
+    Title       string      `json:"title"`
+    ContentHTML string      `json:"content_html,omitempty"`
+    ContentText string      `json:"content_text,omitempty"`
+    Summary     string      `json:"summary"`
+    Image       string      `json:"image,omitempty"`
+    DatePublished string    `json:"date_published"`
+    DateModified string     `json:"date_modified"`
+    Tags        []string    `json:"tags,omitempty"`
+}
+
+// JSON Feed 1.1 형식으로 변환한다.
+func (f *Feed) JSON() ([]byte, error) {
+    feed := jsonFeed{
+        Version: "https://jsonfeed.org/version/1.1",
+        Title: f.Title,
+        HomePageURL: f.Link,
+        FeedURL: f.FeedURL,
+        Description: f.Description,
+        Language: f.Language,
+        Items: make([]jsonItem, 0, len(f.Items)),
+    }
+    for _, item := range f.Items {
+        jsonItem := jsonItem{
+            ID: item.ID,
+            URL: item.Link,
+            Title: item.Title,
+            Summary: item.Summary,
+            Image: item.Image,
+            DatePublished: item.Published.Format(time.RFC3339),
+            DateModified: item.Updated.Format(time.RFC3339),
+            Tags: item.Categories,
+        }
+        // 본문을 포함하지 않을 경우 요약을 본문으로 사용한다.
+        if item.Content != "" {
+            jsonItem.ContentHTML = item.Content
+        } else {
+            jsonItem.ContentText = item.Summary
+        }
+        feed.Items = append(feed.Items, jsonItem)
+    }
+    return json.Marshal(feed)
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+    body, err := xml.Marshal(v)
+    if err != nil { return nil, err }
+    return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,219 @@
+package htmltext
+
+import (
+	"fmt"
+	"okra_board2/utils/slug"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// 단어 사이에 공백을 넣지 않는 인라인 요소
+var inlineElements = map[atom.Atom]bool {
+    atom.A: true, atom.B: true, atom.I: true, atom.U: true, atom.S: true,
+    atom.Em: true, atom.Strong: true, atom.Span: true, atom.Code: true,
+    atom.Small: true, atom.Mark: true, atom.Sub: true, atom.Sup: true,
+}
+
+// HTML 문자열에서 태그를 제거한 일반 텍스트를 추출한다.
+// script, style 태그의 내용은 무시하며, 연속된 공백은 하나로 합친다.
+func ExtractText(htmlStr string) string {
+    node, err := html.Parse(strings.NewReader(htmlStr))
+    if err != nil { return "" }
+
+    var sb strings.Builder
+    var walk func(n *html.Node)
+    walk = func(n *html.Node) {
+        switch n.Type {
+        case html.TextNode:
+            sb.WriteString(n.Data)
+        case html.ElementNode:
+            if n.DataAtom == atom.Script || n.DataAtom == atom.Style {
+                return
+            }
+        }
+        for c := n.FirstChild; c != nil; c = c.NextSibling {
+            walk(c)
+        }
+        // 블록 요소 사이의 단어가 붙지 않도록 공백을 넣는다.
+        if n.Type == html.ElementNode && !inlineElements[n.DataAtom] {
+            sb.WriteString(" ")
+        }
+    }
+    walk(node)
+
+    return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// HTML 문자열에 포함된 img 태그들의 src 속성을 순서대로 반환한다.
+// 중복된 주소와 비어있는 주소는 제외한다.
+func ImageSrcs(htmlStr string) (srcs []string) {
+    node, err := html.Parse(strings.NewReader(htmlStr))
+    if err != nil { return }
+
+    keys := make(map[string]struct{})
+    var walk func(n *html.Node)
+    walk = func(n *html.Node) {
+        if n.Type == html.ElementNode && n.DataAtom == atom.Img {
+            for _, attr := range n.Attr {
+                if attr.Key != "src" || attr.Val == "" { continue }
+                if _, ok := keys[attr.Val]; ok { continue }
+                keys[attr.Val] = struct{}{}
+                srcs = append(srcs, attr.Val)
+            }
+        }
+        for c := n.FirstChild; c != nil; c = c.NextSibling {
+            walk(c)
+        }
+    }
+    walk(node)
+    return
+}
+
+// HTML 문자열에서 첫 번째 img 태그의 src 속성을 반환한다.
+// img 태그가 없을 경우 빈 문자열을 반환한다.
+func FirstImageSrc(htmlStr string) string {
+    if srcs := ImageSrcs(htmlStr); len(srcs) > 0 {
+        return srcs[0]
+    }
+    return ""
+}
+
+// 텍스트를 최대 length 글자로 자른다. 잘린 경우 단어 단위로 자르고 "…"를 붙인다.
+// 글자 수는 바이트가 아닌 문자(rune) 단위로 센다.
+func Truncate(text string, length int) string {
+    runes := []rune(text)
+    if len(runes) <= length {
+        return text
+    }
+    cut := string(runes[:length])
+    if i := strings.LastIndex(cut, " "); i > len(cut) / 2 {
+        cut = cut[:i]
+    }
+    return strings.TrimSpace(cut) + "…"
+}
+
+// HTML 문자열에서 내용이 있는 첫 번째 문단(p 태그)의 텍스트를 반환한다.
+// 내용이 있는 문단이 없을 경우 전체 텍스트를 반환한다.
+func FirstParagraph(htmlStr string) string {
+    node, err := html.Parse(strings.NewReader(htmlStr))
+    if err != nil { return "" }
+
+    var find func(n *html.Node) string
+    find = func(n *html.Node) string {
+        if n.Type == html.ElementNode && n.DataAtom == atom.P {
+            var sb strings.Builder
+            if err := html.Render(&sb, n); err == nil {
+                if text := ExtractText(sb.String()); text != "" {
+                    return text
+                }
+            }
+            return ""
+        }
+        for c := n.FirstChild; c != nil; c = c.NextSibling {
+            if text := find(c); text != "" { return text }
+        }
+        return ""
+    }
+    if text := find(node); text != "" {
+        return text
+    }
+    return ExtractText(htmlStr)
+}
+
+// 한 글자를 하나의 단위로 읽는 문자 (한글, 한자, 가나)
+func isCJK(r rune) bool {
+    return unicode.In(r, unicode.Hangul, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// 텍스트에서 한글 등 CJK 문자의 수와 그 외 단어의 수를 센다.
+// CJK 문자가 섞인 단어는 CJK 문자를 제외한 나머지 부분이 있을 경우에만 단어로 센다.
+func CountWords(text string) (words, chars int) {
+    for _, field := range strings.Fields(text) {
+        rest := false
+        for _, r := range field {
+            if isCJK(r) {
+                chars++
+            } else if unicode.IsLetter(r) || unicode.IsNumber(r) {
+                rest = true
+            }
+        }
+        if rest { words++ }
+    }
+    return
+}
+
+// 목차를 구성하는 제목
+type Heading struct {
+    Level   int
+    ID      string
+    Text    string
+}
+
+var headingLevels = map[atom.Atom]int {
+    atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+}
+
+// HTML 문자열의 제목(h1 ~ h6) 목록을 반환한다.
+// id가 없는 제목에는 제목의 slug로 id를 추가하며, 중복될 경우 "-2", "-3"과 같이 번호를 붙인다.
+// id가 추가된 HTML 문자열을 함께 반환한다.
+func Headings(htmlStr string) (string, []Heading) {
+    context := &html.Node{ Type: html.ElementNode, Data: "body", DataAtom: atom.Body }
+    nodes, err := html.ParseFragment(strings.NewReader(htmlStr), context)
+    if err != nil { return htmlStr, nil }
+
+    var headings []Heading
+    var targets []*html.Node
+    ids := make(map[string]bool)
+    var walk func(n *html.Node)
+    walk = func(n *html.Node) {
+        if n.Type == html.ElementNode {
+            for _, attr := range n.Attr {
+                if attr.Key == "id" { ids[attr.Val] = true }
+            }
+            if _, ok := headingLevels[n.DataAtom]; ok {
+                targets = append(targets, n)
+                return
+            }
+        }
+        for c := n.FirstChild; c != nil; c = c.NextSibling {
+            walk(c)
+        }
+    }
+    for _, n := range nodes {
+        walk(n)
+    }
+
+    changed := false
+    for _, n := range targets {
+        var sb strings.Builder
+        html.Render(&sb, n)
+        heading := Heading{ Level: headingLevels[n.DataAtom], Text: ExtractText(sb.String()) }
+        for _, attr := range n.Attr {
+            if attr.Key == "id" { heading.ID = attr.Val }
+        }
+        if heading.ID == "" {
+            base := slug.Make(heading.Text)
+            if base == "" { base = "heading" }
+            heading.ID = base
+            for i := 2; ids[heading.ID]; i++ {
+                heading.ID = fmt.Sprintf("%s-%d", base, i)
+            }
+            ids[heading.ID] = true
+            n.Attr = append(n.Attr, html.Attribute{ Key: "id", Val: heading.ID })
+            changed = true
+        }
+        headings = append(headings, heading)
+    }
+    if !changed {
+        return htmlStr, headings
+    }
+
+    var sb strings.Builder
+    for _, n := range nodes {
+        if err := html.Render(&sb, n); err != nil { return htmlStr, headings }
+    }
+    return sb.String(), headings
+}
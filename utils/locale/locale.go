@@ -0,0 +1,69 @@
+package locale
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 언어 코드를 소문자로 바꾸고 구분자를 '-'로 통일한다. (ex. en_US => en-us)
+func Normalize(tag string) string {
+    return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
+
+// Accept-Language 헤더를 선호도(q) 순으로 정렬된 언어 코드 목록으로 변환한다.
+// 선호도가 같은 언어는 헤더에 나타난 순서를 따르며, q=0인 언어와 "*"는 제외한다.
+func ParseAcceptLanguage(header string) (tags []string) {
+    type weighted struct {
+        tag string
+        q   float64
+    }
+    var list []weighted
+    for _, part := range strings.Split(header, ",") {
+        fields := strings.Split(part, ";")
+        tag := Normalize(fields[0])
+        if tag == "" || tag == "*" { continue }
+        q := 1.0
+        for _, param := range fields[1:] {
+            param = strings.TrimSpace(param)
+            if strings.HasPrefix(param, "q=") {
+                if value, err := strconv.ParseFloat(param[2:], 64); err == nil {
+                    q = value
+                }
+            }
+        }
+        if q <= 0 { continue }
+        list = append(list, weighted{ tag: tag, q: q })
+    }
+    sort.SliceStable(list, func(i, j int) bool { return list[i].q > list[j].q })
+    for _, item := range list {
+        tags = append(tags, item.tag)
+    }
+    return
+}
+
+// 요청한 언어들을 제공하는 언어 중에서 찾아 우선순위 순으로 반환한다.
+// 지역이 포함된 언어(ex. en-us)는 정확히 일치하는 언어가 없을 경우 기본 언어(ex. en)와 일치시킨다.
+func Match(requested []string, supported []string) (matched []string) {
+    keys := make(map[string]struct{})
+    add := func(tag string) bool {
+        for _, s := range supported {
+            if Normalize(s) != tag { continue }
+            if _, ok := keys[s]; !ok {
+                keys[s] = struct{}{}
+                matched = append(matched, s)
+            }
+            return true
+        }
+        return false
+    }
+    for _, tag := range requested {
+        tag = Normalize(tag)
+        if !add(tag) {
+            if i := strings.Index(tag, "-"); i > 0 {
+                add(tag[:i])
+            }
+        }
+    }
+    return
+}
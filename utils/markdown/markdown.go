@@ -0,0 +1,56 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"okra_board2/utils/slug"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// CommonMark에 GFM 표와 각주를 더한 변환기.
+// 본문의 HTML은 그대로 출력되므로 결과는 반드시 정제한 후 사용해야 한다.
+var converter = goldmark.New(
+    goldmark.WithExtensions(
+        extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+        extension.Footnote,
+    ),
+    goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+    goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+// 제목의 slug로 anchor id를 생성한다. 중복될 경우 "-2", "-3"과 같이 번호를 붙인다.
+type headingIDs struct {
+    values map[string]bool
+}
+
+func (s *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+    base := slug.Make(string(value))
+    if base == "" {
+        base = "heading"
+    }
+    id := base
+    for i := 2; s.values[id]; i++ {
+        id = fmt.Sprintf("%s-%d", base, i)
+    }
+    s.values[id] = true
+    return []byte(id)
+}
+
+func (s *headingIDs) Put(value []byte) {
+    s.values[string(value)] = true
+}
+
+// Markdown 문자열을 HTML로 변환한다. 제목에는 slug 형태의 id가 추가된다.
+func Render(source string) (string, error) {
+    var buf bytes.Buffer
+    ctx := parser.NewContext(parser.WithIDs(&headingIDs{ values: make(map[string]bool) }))
+    if err := converter.Convert([]byte(source), &buf, parser.WithContext(ctx)); err != nil {
+        return "", err
+    }
+    return buf.String(), nil
+}
@@ -0,0 +1,26 @@
+package permalink
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// 게시물 경로가 설정되지 않았을 때 사용하는 경로
+const DefaultPostPath = "/posts/{postId}"
+
+// 게시물 페이지의 전체 주소를 반환한다.
+// path의 {postId}, {boardId}, {slug}, {locale}은 각각 게시물 번호, 게시판 번호, 게시물 slug, 게시물의 언어로 치환되며,
+// path가 비어있을 경우 DefaultPostPath를 사용한다.
+func Post(siteURL, path string, postId, boardId int, slug, locale string) string {
+    if path == "" {
+        path = DefaultPostPath
+    }
+    path = strings.NewReplacer(
+        "{postId}", strconv.Itoa(postId),
+        "{boardId}", strconv.Itoa(boardId),
+        "{slug}", url.PathEscape(slug),
+        "{locale}", locale,
+    ).Replace(path)
+    return strings.TrimRight(siteURL, "/") + path
+}
@@ -0,0 +1,182 @@
+package sanitize
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// 허용 목록 기반의 HTML 정제 정책
+type Policy struct {
+    // 허용되는 태그와 태그별로 허용되는 속성
+    Tags        map[string][]string
+    // 모든 허용된 태그에 사용할 수 있는 속성
+    GlobalAttrs []string
+    // href, src 등 URL 속성에 허용되는 scheme. scheme이 없는 상대 주소는 항상 허용된다.
+    Schemes     []string
+    // iframe의 src로 허용되는 호스트
+    IframeHosts []string
+}
+
+// 내용까지 함께 제거되는 태그
+var dropContent = map[atom.Atom]bool {
+    atom.Script: true, atom.Style: true, atom.Iframe: true, atom.Object: true,
+    atom.Embed: true, atom.Noscript: true, atom.Template: true, atom.Textarea: true,
+    atom.Select: true, atom.Title: true, atom.Head: true,
+}
+
+// URL 값을 갖는 속성
+var urlAttrs = map[string]bool {
+    "href": true, "src": true, "cite": true, "poster": true, "action": true,
+}
+
+// 게시물 편집기에서 사용하는 태그와 YouTube 임베드를 허용하는 기본 정책을 반환한다.
+func DefaultPolicy() *Policy {
+    cell := []string{"colspan", "rowspan", "align"}
+    return &Policy {
+        Tags: map[string][]string {
+            "p": nil, "br": nil, "div": nil, "span": nil, "hr": nil,
+            "strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil,
+            "strike": nil, "del": nil, "sub": nil, "sup": nil, "mark": nil, "small": nil,
+            "h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+            "blockquote": {"cite"}, "pre": nil, "code": nil,
+            "ul": nil, "ol": {"start"}, "li": nil, "section": nil,
+            "a": {"href", "title", "target", "rel"},
+            "img": {"src", "alt", "title", "width", "height"},
+            "figure": nil, "figcaption": nil,
+            "table": nil, "thead": nil, "tbody": nil, "tfoot": nil, "tr": nil,
+            "th": cell, "td": cell,
+            "iframe": {"src", "width", "height", "allow", "allowfullscreen", "frameborder"},
+        },
+        GlobalAttrs: []string{"class", "id", "role"},
+        Schemes: []string{"http", "https", "mailto", "tel"},
+        IframeHosts: []string{"www.youtube.com", "youtube.com", "www.youtube-nocookie.com"},
+    }
+}
+
+func contains(list []string, value string) bool {
+    for _, item := range list {
+        if strings.EqualFold(item, value) { return true }
+    }
+    return false
+}
+
+// URL이 정책에서 허용하는 scheme을 사용하는지 확인한다.
+func (p *Policy) AllowURL(rawURL string) bool {
+    u, err := url.Parse(strings.TrimSpace(rawURL))
+    if err != nil { return false }
+    return u.Scheme == "" || contains(p.Schemes, u.Scheme)
+}
+
+func (p *Policy) allowIframe(src string) bool {
+    u, err := url.Parse(strings.TrimSpace(src))
+    if err != nil { return false }
+    return u.Scheme == "https" && contains(p.IframeHosts, u.Hostname())
+}
+
+// HTML 문자열을 정책에 따라 정제하고, 제거된 태그와 속성을 "<script>", "<a onclick>"과 같은 형태로
+// 처음 나타난 순서대로 중복 없이 반환한다.
+// 허용되지 않은 태그는 내용을 남기고 제거하며, script, style 등은 내용까지 제거한다.
+// 허용되지 않은 호스트의 iframe은 제거된다.
+func (p *Policy) Sanitize(htmlStr string) (string, []string) {
+    context := &html.Node{ Type: html.ElementNode, Data: "body", DataAtom: atom.Body }
+    nodes, err := html.ParseFragment(strings.NewReader(htmlStr), context)
+    if err != nil { return "", nil }
+
+    var stripped []string
+    keys := make(map[string]struct{})
+    report := func(item string) {
+        if _, ok := keys[item]; ok { return }
+        keys[item] = struct{}{}
+        stripped = append(stripped, item)
+    }
+
+    for _, n := range nodes {
+        context.AppendChild(n)
+    }
+    p.sanitizeChildren(context, report)
+
+    var sb strings.Builder
+    for c := context.FirstChild; c != nil; c = c.NextSibling {
+        if err := html.Render(&sb, c); err != nil { return "", stripped }
+    }
+    return sb.String(), stripped
+}
+
+func (p *Policy) sanitizeChildren(parent *html.Node, report func(string)) {
+    for c := parent.FirstChild; c != nil; {
+        next := c.NextSibling
+        switch c.Type {
+        case html.CommentNode, html.DoctypeNode:
+            parent.RemoveChild(c)
+        case html.ElementNode:
+            attrs, allowed := p.Tags[c.Data]
+            if allowed && c.DataAtom == atom.Iframe && !p.allowIframe(attrValue(c, "src")) {
+                report("<iframe src>")
+                parent.RemoveChild(c)
+                break
+            }
+            if !allowed {
+                report("<" + c.Data + ">")
+                if dropContent[c.DataAtom] {
+                    parent.RemoveChild(c)
+                    break
+                }
+                // 내용을 남기고 태그만 제거한다. 옮겨진 자식들은 이후 순회에서 정제된다.
+                next = c.FirstChild
+                if next == nil { next = c.NextSibling }
+                for child := c.FirstChild; child != nil; child = c.FirstChild {
+                    c.RemoveChild(child)
+                    parent.InsertBefore(child, c)
+                }
+                parent.RemoveChild(c)
+                break
+            }
+            p.sanitizeAttrs(c, attrs, report)
+            p.sanitizeChildren(c, report)
+        }
+        c = next
+    }
+}
+
+func (p *Policy) sanitizeAttrs(n *html.Node, allowed []string, report func(string)) {
+    attrs := n.Attr[:0]
+    blank := false
+    for _, attr := range n.Attr {
+        key := strings.ToLower(attr.Key)
+        if attr.Namespace != "" || (!contains(allowed, key) && !contains(p.GlobalAttrs, key)) {
+            report("<" + n.Data + " " + key + ">")
+            continue
+        }
+        if urlAttrs[key] && !p.AllowURL(attr.Val) {
+            report("<" + n.Data + " " + key + ">")
+            continue
+        }
+        if key == "target" && attr.Val == "_blank" { blank = true }
+        attrs = append(attrs, attr)
+    }
+    n.Attr = attrs
+    // 새 창으로 열리는 링크가 원래 창에 접근하지 못하도록 한다.
+    if blank {
+        setAttr(n, "rel", "noopener noreferrer")
+    }
+}
+
+func attrValue(n *html.Node, key string) string {
+    for _, attr := range n.Attr {
+        if attr.Key == key { return attr.Val }
+    }
+    return ""
+}
+
+func setAttr(n *html.Node, key, value string) {
+    for i := range n.Attr {
+        if n.Attr[i].Key == key {
+            n.Attr[i].Val = value
+            return
+        }
+    }
+    n.Attr = append(n.Attr, html.Attribute{ Key: key, Val: value })
+}
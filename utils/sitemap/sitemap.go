@@ -0,0 +1,84 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// 하나의 sitemap 파일에 포함될 수 있는 최대 URL 수
+const MaxURLs = 50000
+
+// 하나의 URL에 포함될 수 있는 최대 이미지 수
+const MaxImages = 1000
+
+type URL struct {
+    Loc         string
+    LastMod     time.Time
+    Images      []string
+}
+
+type urlSet struct {
+    XMLName     xml.Name    `xml:"urlset"`
+    NS          string      `xml:"xmlns,attr"`
+    ImageNS     string      `xml:"xmlns:image,attr"`
+    URLs        []url       `xml:"url"`
+}
+
+type url struct {
+    Loc         string      `xml:"loc"`
+    LastMod     string      `xml:"lastmod,omitempty"`
+    Images      []image     `xml:"image:image"`
+}
+
+type image struct {
+    Loc         string      `xml:"image:loc"`
+}
+
+type sitemapIndex struct {
+    XMLName     xml.Name    `xml:"sitemapindex"`
+    NS          string      `xml:"xmlns,attr"`
+    Sitemaps    []sitemap   `xml:"sitemap"`
+}
+
+type sitemap struct {
+    Loc         string      `xml:"loc"`
+    LastMod     string      `xml:"lastmod,omitempty"`
+}
+
+func lastMod(t time.Time) string {
+    if t.IsZero() { return "" }
+    return t.Format(time.RFC3339)
+}
+
+// URL 목록으로 image 확장을 포함한 urlset 문서를 생성한다.
+func URLSet(urls []URL) ([]byte, error) {
+    set := urlSet{
+        NS: "http://www.sitemaps.org/schemas/sitemap/0.9",
+        ImageNS: "http://www.google.com/schemas/sitemap-image/1.1",
+        URLs: make([]url, 0, len(urls)),
+    }
+    for _, u := range urls {
+        entry := url{ Loc: u.Loc, LastMod: lastMod(u.LastMod) }
+        for i, img := range u.Images {
+            if i >= MaxImages { break }
+            entry.Images = append(entry.Images, image{ Loc: img })
+        }
+        set.URLs = append(set.URLs, entry)
+    }
+    return marshal(set)
+}
+
+// sitemap 파일들의 주소와 최종 수정 일자로 sitemapindex 문서를 생성한다.
+func Index(locs []string, lastMods []time.Time) ([]byte, error) {
+    index := sitemapIndex{ NS: "http://www.sitemaps.org/schemas/sitemap/0.9" }
+    for i, loc := range locs {
+        index.Sitemaps = append(index.Sitemaps, sitemap{ Loc: loc, LastMod: lastMod(lastMods[i]) })
+    }
+    return marshal(index)
+}
+
+func marshal(v interface{}) ([]byte, error) {
+    body, err := xml.Marshal(v)
+    if err != nil { return nil, err }
+    return append([]byte(xml.Header), body...), nil
+}
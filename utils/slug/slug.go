@@ -0,0 +1,52 @@
+package slug
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// 문자열을 URL에 사용할 수 있는 slug로 변환한다.
+// 영문은 소문자로 바꾸고, 한글을 포함한 문자와 숫자는 그대로 유지한다.
+// 그 외의 문자가 연속된 구간은 '-' 하나로 치환하며, 양 끝의 '-'는 제거한다.
+func Make(str string) string {
+    var sb strings.Builder
+    dash := false
+    for _, r := range strings.ToLower(str) {
+        if unicode.IsLetter(r) || unicode.IsNumber(r) {
+            if dash && sb.Len() > 0 {
+                sb.WriteRune('-')
+            }
+            sb.WriteRune(r)
+            dash = false
+        } else {
+            dash = true
+        }
+    }
+    return sb.String()
+}
+
+// 태그 이름에서 의미를 가지는 기호와 그 기호를 대신할 단어 (ex. C++, C#)
+var tagSymbols = map[rune]string{
+    '+': "plus",
+    '#': "sharp",
+    '&': "and",
+    '@': "at",
+}
+
+// 태그 이름을 slug로 변환한다. Make와 같지만 tagSymbols의 기호는 단어로 바꾸고,
+// 이모지 등 그 외의 기호 문자는 코드 포인트(ex. u1f525)로 바꾸어 C, C++, C#처럼
+// 기호만 다른 태그가 하나로 합쳐지거나 기호만으로 이루어진 태그가 사라지지 않도록 한다.
+func MakeTag(str string) string {
+    var sb strings.Builder
+    for _, r := range str {
+        if word, ok := tagSymbols[r]; ok {
+            sb.WriteString(" " + word + " ")
+        } else if unicode.IsSymbol(r) {
+            sb.WriteString(fmt.Sprintf(" u%x ", r))
+        } else {
+            sb.WriteRune(r)
+        }
+    }
+    return Make(sb.String())
+}